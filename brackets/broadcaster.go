@@ -0,0 +1,195 @@
+package brackets
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Event — событие для рассылки подписчикам топика на всех экземплярах API.
+type Event struct {
+	Topic   string          `json:"topic"`
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+	Seq     int64           `json:"seq,omitempty"` // Номер в комнате турнира (0 — событие не нумеруется)
+}
+
+// Broadcaster доставляет опубликованные события во все хабы (в том числе в текущий процесс).
+type Broadcaster interface {
+	// Start регистрирует обработчик входящих событий; вызывается один раз до Publish.
+	Start(ctx context.Context, deliver func(Event)) error
+	Publish(ctx context.Context, ev Event) error
+	Close() error
+}
+
+// --- In-memory: один экземпляр API ---
+
+type MemoryBroadcaster struct {
+	mu      sync.RWMutex
+	deliver func(Event)
+}
+
+func NewMemoryBroadcaster() *MemoryBroadcaster {
+	return &MemoryBroadcaster{}
+}
+
+func (b *MemoryBroadcaster) Start(_ context.Context, deliver func(Event)) error {
+	b.mu.Lock()
+	b.deliver = deliver
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *MemoryBroadcaster) Publish(_ context.Context, ev Event) error {
+	b.mu.RLock()
+	deliver := b.deliver
+	b.mu.RUnlock()
+	if deliver == nil {
+		return errors.New("memory broadcaster is not started")
+	}
+	deliver(ev)
+	return nil
+}
+
+func (b *MemoryBroadcaster) Close() error {
+	return nil
+}
+
+// --- Postgres LISTEN/NOTIFY: несколько экземпляров API за балансировщиком ---
+
+const (
+	pgBroadcastChannel    = "ws_events"
+	pgMaxNotifyPayload    = 7900 // Лимит NOTIFY — 8000 байт
+	pgListenerPingPeriod  = 90 * time.Second
+	pgBroadcastsRetention = 10 * time.Minute
+)
+
+// pgNotification — содержимое NOTIFY: либо само событие, либо ссылка на строку ws_broadcasts.
+type pgNotification struct {
+	Event
+	Ref int64 `json:"ref,omitempty"`
+}
+
+type PostgresBroadcaster struct {
+	dsn      string
+	db       *sql.DB
+	listener *pq.Listener
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewPostgresBroadcaster(dsn string, db *sql.DB) *PostgresBroadcaster {
+	return &PostgresBroadcaster{dsn: dsn, db: db}
+}
+
+func (b *PostgresBroadcaster) Start(ctx context.Context, deliver func(Event)) error {
+	b.listener = pq.NewListener(b.dsn, 2*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("WS broadcaster: listener event %d: %v", ev, err)
+		}
+	})
+	if err := b.listener.Listen(pgBroadcastChannel); err != nil {
+		b.listener.Close()
+		return fmt.Errorf("failed to listen on %s: %w", pgBroadcastChannel, err)
+	}
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+	go b.loop(ctx, deliver)
+	return nil
+}
+
+func (b *PostgresBroadcaster) loop(ctx context.Context, deliver func(Event)) {
+	defer close(b.done)
+	ticker := time.NewTicker(pgListenerPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-b.listener.Notify:
+			if n == nil {
+				// Соединение переустановлено; события за время разрыва могли быть потеряны
+				log.Printf("WS broadcaster: listener reconnected, some events may have been missed")
+				continue
+			}
+			ev, err := b.decode(ctx, n.Extra)
+			if err != nil {
+				log.Printf("WS broadcaster: failed to decode notification: %v", err)
+				continue
+			}
+			deliver(ev)
+		case <-ticker.C:
+			if err := b.listener.Ping(); err != nil {
+				log.Printf("WS broadcaster: listener ping failed: %v", err)
+			}
+			if _, err := b.db.ExecContext(ctx, `DELETE FROM ws_broadcasts WHERE created_at < $1`, time.Now().Add(-pgBroadcastsRetention)); err != nil {
+				log.Printf("WS broadcaster: failed to clean up ws_broadcasts: %v", err)
+			}
+		}
+	}
+}
+
+func (b *PostgresBroadcaster) decode(ctx context.Context, extra string) (Event, error) {
+	var n pgNotification
+	if err := json.Unmarshal([]byte(extra), &n); err != nil {
+		return Event{}, err
+	}
+	if n.Ref == 0 {
+		return n.Event, nil
+	}
+
+	ev := Event{Seq: n.Seq}
+	var payload []byte
+	err := b.db.QueryRowContext(ctx, `SELECT topic, event, payload FROM ws_broadcasts WHERE id = $1`, n.Ref).Scan(&ev.Topic, &ev.Event, &payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to load ws_broadcasts row %d: %w", n.Ref, err)
+	}
+	ev.Payload = payload
+	return ev, nil
+}
+
+func (b *PostgresBroadcaster) Publish(ctx context.Context, ev Event) error {
+	data, err := json.Marshal(pgNotification{Event: ev})
+	if err != nil {
+		return err
+	}
+
+	if len(data) > pgMaxNotifyPayload {
+		var id int64
+		err = b.db.QueryRowContext(ctx,
+			`INSERT INTO ws_broadcasts (topic, event, payload) VALUES ($1, $2, $3) RETURNING id`,
+			ev.Topic, ev.Event, []byte(ev.Payload),
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to store large ws event: %w", err)
+		}
+		if data, err = json.Marshal(pgNotification{Event: Event{Seq: ev.Seq}, Ref: id}); err != nil {
+			return err
+		}
+	}
+
+	if _, err = b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, pgBroadcastChannel, string(data)); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", pgBroadcastChannel, err)
+	}
+	return nil
+}
+
+func (b *PostgresBroadcaster) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+		<-b.done
+	}
+	if b.listener != nil {
+		return b.listener.Close()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package brackets
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	// eventBufferSize — сколько последних событий хранится на каждую комнату турнира.
+	eventBufferSize = 500
+	// maxReplayEvents — больший разрыв клиент закрывает загрузкой снимка, а не досылкой.
+	maxReplayEvents = 200
+)
+
+// EventStore хранит пронумерованные события комнат, чтобы переподключившийся клиент мог их дополучить.
+type EventStore interface {
+	// Append присваивает событию следующий номер в комнате и сохраняет его.
+	Append(ctx context.Context, ev *Event) error
+	// Since возвращает события комнаты с номером больше afterSeq и текущий номер.
+	// gap == true — досылка невозможна (события вытеснены из буфера или их слишком много), нужен снимок.
+	Since(ctx context.Context, topic string, afterSeq int64, limit int) (events []Event, current int64, gap bool, err error)
+}
+
+// isSequencedTopic — номера и буфер ведутся только для комнат турниров.
+func isSequencedTopic(topic string) bool {
+	return strings.HasPrefix(topic, topicTournament+":")
+}
+
+type PostgresEventStore struct {
+	db *sql.DB
+}
+
+func NewPostgresEventStore(db *sql.DB) *PostgresEventStore {
+	return &PostgresEventStore{db: db}
+}
+
+func (s *PostgresEventStore) Append(ctx context.Context, ev *Event) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Строка счётчика блокируется до конца транзакции, поэтому номера монотонны и между экземплярами
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO ws_topic_sequences (topic, last_seq) VALUES ($1, 1)
+		ON CONFLICT (topic) DO UPDATE SET last_seq = ws_topic_sequences.last_seq + 1
+		RETURNING last_seq`, ev.Topic).Scan(&ev.Seq)
+	if err != nil {
+		return fmt.Errorf("failed to allocate sequence for %s: %w", ev.Topic, err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		`INSERT INTO ws_events (topic, seq, event, payload) VALUES ($1, $2, $3, $4)`,
+		ev.Topic, ev.Seq, ev.Event, []byte(ev.Payload),
+	); err != nil {
+		return fmt.Errorf("failed to store event %d for %s: %w", ev.Seq, ev.Topic, err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		`DELETE FROM ws_events WHERE topic = $1 AND seq <= $2`,
+		ev.Topic, ev.Seq-eventBufferSize,
+	); err != nil {
+		return fmt.Errorf("failed to trim event buffer for %s: %w", ev.Topic, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit event %d for %s: %w", ev.Seq, ev.Topic, err)
+	}
+	return nil
+}
+
+func (s *PostgresEventStore) Since(ctx context.Context, topic string, afterSeq int64, limit int) ([]Event, int64, bool, error) {
+	var current int64
+	var oldest sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE((SELECT last_seq FROM ws_topic_sequences WHERE topic = $1), 0),
+		       (SELECT MIN(seq) FROM ws_events WHERE topic = $1)`, topic).Scan(&current, &oldest)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to get sequence state for %s: %w", topic, err)
+	}
+
+	switch {
+	case afterSeq == current:
+		return nil, current, false, nil
+	case afterSeq > current:
+		// Клиент знает номер, которого у сервера нет (например, после очистки базы)
+		return nil, current, true, nil
+	case !oldest.Valid || afterSeq+1 < oldest.Int64:
+		return nil, current, true, nil
+	case current-afterSeq > int64(limit):
+		return nil, current, true, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT seq, event, payload FROM ws_events
+		WHERE topic = $1 AND seq > $2
+		ORDER BY seq ASC
+		LIMIT $3`, topic, afterSeq, limit)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to list events for %s: %w", topic, err)
+	}
+	defer rows.Close()
+
+	events := make([]Event, 0)
+	for rows.Next() {
+		ev := Event{Topic: topic}
+		var payload []byte
+		if scanErr := rows.Scan(&ev.Seq, &ev.Event, &payload); scanErr != nil {
+			return nil, 0, false, scanErr
+		}
+		ev.Payload = payload
+		events = append(events, ev)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, false, err
+	}
+	return events, current, false, nil
+}
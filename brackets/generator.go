@@ -8,6 +8,8 @@ import (
 type GenerateBracketParams struct {
 	Tournament   *models.Tournament
 	Participants []*models.Participant
+	// Seeded — участники уже отсортированы по силе (первый — сильнейший)
+	Seeded bool
 }
 
 type BracketGenerator interface {
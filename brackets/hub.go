@@ -1,154 +1,520 @@
 package brackets
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// Authenticator проверяет токен из кадра auth и возвращает ID пользователя.
+type Authenticator func(token string) (int, error)
+
 type Client struct {
 	Hub      *Hub
 	Conn     *websocket.Conn
 	Send     chan []byte
-	Room     string
+	Room     string // Устаревший режим: единственная комната из URL, сообщения в формате WebSocketMessage
 	IsClosed bool
 	Mu       sync.Mutex
+
+	SSE          bool          // Клиент Server-Sent Events: в Send попадают готовые блоки text/event-stream
+	UserID       *int          // Заполняется при аутентификации (заголовок при апгрейде или первый кадр)
+	Authenticate Authenticator // Нужен для кадра auth; nil — аутентификация кадром недоступна
+
+	topics     map[string]bool // Защищено Hub.mu
+	framesRead int
+	// pending — события, пришедшие во время досылки пропущенных (топик -> кадры); защищено Mu
+	pending map[string][]pendingFrame
+}
+
+type clientFormat int
+
+const (
+	formatProtocol clientFormat = iota
+	formatLegacy
+	formatSSE
+	formatCount
+)
+
+func (c *Client) format() clientFormat {
+	switch {
+	case c.SSE:
+		return formatSSE
+	case c.Room != "":
+		return formatLegacy
+	default:
+		return formatProtocol
+	}
 }
 
+type pendingFrame struct {
+	seq  int64
+	data []byte
+}
+
+// WebSocketMessage — формат сообщений для клиентов устаревшего режима (/ws/tournaments/{id}).
 type WebSocketMessage struct {
 	Type    string      `json:"type"`              // Тип сообщения, например, "BRACKET_UPDATED", "MATCH_UPDATED"
 	Payload interface{} `json:"payload"`           // Полезная нагрузка (данные сообщения)
 	RoomID  string      `json:"room_id,omitempty"` // ID комнаты (турнира), к которой относится сообщение
+	Seq     int64       `json:"seq,omitempty"`     // Номер события в комнате
 }
 
 const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
+	writeWait          = 10 * time.Second
+	pongWait           = 60 * time.Second
+	pingPeriod         = (pongWait * 9) / 10
+	maxMessageSize     = 4096
+	maxTopicsPerClient = 50
+
+	legacySnapshotRequired = "SNAPSHOT_REQUIRED"
 )
 
 type Hub struct {
 	clients    map[*Client]bool
-	Broadcast  chan []byte  // <--- Изменено на Broadcast
-	Register   chan *Client // <--- Изменено на Register
-	Unregister chan *Client // <--- Изменено на Unregister
-	rooms      map[string]map[*Client]bool
+	Broadcast  chan []byte
+	Register   chan *Client
+	Unregister chan *Client
+	rooms      map[string]map[*Client]bool // топик -> подписчики
 	mu         sync.RWMutex
+
+	broadcaster Broadcaster
+	events      EventStore // nil — события не нумеруются и не досылаются
 }
 
+// NewHub создаёт хаб с in-memory рассылкой (события доходят только до клиентов этого процесса).
 func NewHub() *Hub {
-	return &Hub{
-		Broadcast:  make(chan []byte),  // <--- Используем Broadcast
-		Register:   make(chan *Client), // <--- Используем Register
-		Unregister: make(chan *Client), // <--- Используем Unregister
+	h := &Hub{
+		Broadcast:  make(chan []byte),
+		Register:   make(chan *Client),
+		Unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
 		rooms:      make(map[string]map[*Client]bool),
 	}
+	memory := NewMemoryBroadcaster()
+	_ = memory.Start(context.Background(), h.deliver)
+	h.broadcaster = memory
+	return h
+}
+
+// UseBroadcaster подключает другой механизм рассылки (например, Postgres LISTEN/NOTIFY для нескольких экземпляров).
+// Вызывается при старте, до публикации событий.
+func (h *Hub) UseBroadcaster(ctx context.Context, b Broadcaster) error {
+	if err := b.Start(ctx, h.deliver); err != nil {
+		return err
+	}
+	h.broadcaster = b
+	return nil
+}
+
+// UseEventStore включает нумерацию и буфер событий комнат турниров.
+func (h *Hub) UseEventStore(store EventStore) {
+	h.events = store
 }
 
 func (h *Hub) Run() {
 	for {
 		select {
-		case client := <-h.Register: // <--- Используем h.Register
+		case client := <-h.Register:
 			h.mu.Lock()
-			if _, ok := h.rooms[client.Room]; !ok {
-				h.rooms[client.Room] = make(map[*Client]bool)
+			h.clients[client] = true
+			if client.Room != "" {
+				h.subscribeLocked(client, client.Room)
 			}
-			h.rooms[client.Room][client] = true
-			log.Printf("Client registered to room %s. Total clients in room: %d", client.Room, len(h.rooms[client.Room]))
 			h.mu.Unlock()
+			log.Printf("Client registered. Total clients: %d", len(h.clients))
 
-		case client := <-h.Unregister: // <--- Используем h.Unregister
+		case client := <-h.Unregister:
 			h.mu.Lock()
-			if _, ok := h.rooms[client.Room]; ok {
-				if _, okClient := h.rooms[client.Room][client]; okClient {
-					client.Mu.Lock()
-					if !client.IsClosed {
-						close(client.Send)
-						client.IsClosed = true
-					}
-					client.Mu.Unlock()
-					delete(h.rooms[client.Room], client)
-					if len(h.rooms[client.Room]) == 0 {
-						delete(h.rooms, client.Room)
-						log.Printf("Room %s closed as it's empty.", client.Room)
-					} else {
-						log.Printf("Client unregistered from room %s. Total clients in room: %d", client.Room, len(h.rooms[client.Room]))
-					}
+			if _, ok := h.clients[client]; ok {
+				client.Mu.Lock()
+				if !client.IsClosed {
+					close(client.Send)
+					client.IsClosed = true
+				}
+				client.Mu.Unlock()
+				for topic := range client.topics {
+					h.unsubscribeLocked(client, topic)
 				}
+				delete(h.clients, client)
+				log.Printf("Client unregistered. Total clients: %d", len(h.clients))
 			}
 			h.mu.Unlock()
 
-		case message := <-h.Broadcast: // <--- Используем h.Broadcast
+		case message := <-h.Broadcast:
 			h.mu.RLock()
-			// Этот цикл по h.clients может быть не нужен, если все клиенты всегда в комнатах.
-			// Если нужен, то нужно обеспечить, чтобы client.Send был доступен и h.clients обновлялся корректно.
-			// Либо удалить этот блок, если все сообщения идут через BroadcastToRoom.
-			for client := range h.clients { // Осторожно с этим блоком, если clients не управляется так же, как rooms
-				client.Mu.Lock()
-				if client.IsClosed {
-					client.Mu.Unlock()
-					continue
-				}
-				select {
-				case client.Send <- message:
-				default: // Канал клиента полон или закрыт
-					// Не закрываем канал здесь повторно, если он уже закрыт в Unregister
-					// log.Printf("Client send channel full or closed in broadcast. Removing client.")
-					// delete(h.clients, client) // Удаление из общей карты клиентов
-				}
-				client.Mu.Unlock()
+			for client := range h.clients {
+				client.trySend(message)
 			}
 			h.mu.RUnlock()
 		}
 	}
 }
 
-// BroadcastToRoom отправляет сообщение всем клиентам в указанной комнате.
-func (h *Hub) BroadcastToRoom(roomID string, message interface{}) {
+func (h *Hub) subscribeLocked(c *Client, topic string) {
+	if c.topics == nil {
+		c.topics = make(map[string]bool)
+	}
+	if _, ok := h.rooms[topic]; !ok {
+		h.rooms[topic] = make(map[*Client]bool)
+	}
+	h.rooms[topic][c] = true
+	c.topics[topic] = true
+}
+
+func (h *Hub) unsubscribeLocked(c *Client, topic string) {
+	delete(c.topics, topic)
+	if subs, ok := h.rooms[topic]; ok {
+		delete(subs, c)
+		if len(subs) == 0 {
+			delete(h.rooms, topic)
+		}
+	}
+}
+
+// Publish рассылает событие подписчикам топика на всех экземплярах через broadcaster.
+func (h *Hub) Publish(topic, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshalling payload of %s for topic %s: %v", event, topic, err)
+		return
+	}
+	ev := Event{Topic: topic, Event: event, Payload: data}
+	if h.events != nil && isSequencedTopic(topic) {
+		if err := h.events.Append(context.Background(), &ev); err != nil {
+			// Событие всё равно рассылаем, но без номера его нельзя будет дослать
+			log.Printf("Error storing %s for topic %s: %v", event, topic, err)
+		}
+	}
+	if err := h.broadcaster.Publish(context.Background(), ev); err != nil {
+		// Лучше доставить хотя бы локальным клиентам, чем потерять событие целиком
+		log.Printf("Error publishing %s for topic %s, delivering locally: %v", event, topic, err)
+		h.deliver(ev)
+	}
+}
+
+// deliver отправляет событие локальным подписчикам топика.
+// Клиенты протокола получают ServerFrame, клиенты устаревшего режима — WebSocketMessage.
+func (h *Hub) deliver(ev Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	roomClients, ok := h.rooms[roomID]
+	subscribers, ok := h.rooms[ev.Topic]
 	if !ok {
-		log.Printf("No clients in room %s to broadcast to.", roomID)
 		return
 	}
 
-	messageBytes, err := json.Marshal(message)
+	var encoded [formatCount][]byte
+	for client := range subscribers {
+		f := client.format()
+		if encoded[f] == nil {
+			data, err := encodeEvent(ev, f)
+			if err != nil {
+				log.Printf("Error marshalling event %s for topic %s: %v", ev.Event, ev.Topic, err)
+				return
+			}
+			encoded[f] = data
+		}
+		client.enqueue(ev.Topic, ev.Seq, encoded[f])
+	}
+	log.Printf("Delivered %s to topic %s (%d subscribers)", ev.Event, ev.Topic, len(subscribers))
+}
+
+// encodeEvent сериализует событие в формате клиента: кадр протокола, WebSocketMessage или блок SSE.
+func encodeEvent(ev Event, f clientFormat) ([]byte, error) {
+	switch f {
+	case formatLegacy:
+		return json.Marshal(WebSocketMessage{Type: ev.Event, Payload: ev.Payload, RoomID: legacyRoomID(ev.Topic), Seq: ev.Seq})
+	case formatSSE:
+		return encodeSSE(ev.Seq, ev.Event, ev.Payload)
+	default:
+		return json.Marshal(newEventFrame(ev))
+	}
+}
+
+// encodeSnapshotRequired — сообщение о том, что пропущенные события дослать нельзя.
+func encodeSnapshotRequired(topic string, current int64, f clientFormat) ([]byte, error) {
+	payload := SnapshotRequiredPayload{Topic: topic, CurrentSeq: current}
+	switch f {
+	case formatLegacy:
+		return json.Marshal(WebSocketMessage{Type: legacySnapshotRequired, Payload: payload, RoomID: legacyRoomID(topic), Seq: current})
+	case formatSSE:
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		return encodeSSE(current, legacySnapshotRequired, data)
+	default:
+		return json.Marshal(ServerFrame{V: ProtocolVersion, Type: FrameSnapshotRequired, Topic: topic, Seq: current, Payload: payload, Time: time.Now().UTC()})
+	}
+}
+
+// encodeSSE формирует блок text/event-stream; id — номер события, по нему браузер пришлёт Last-Event-ID.
+func encodeSSE(seq int64, event string, payload []byte) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, payload); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if seq > 0 {
+		fmt.Fprintf(&buf, "id: %d\n", seq)
+	}
+	fmt.Fprintf(&buf, "event: %s\ndata: %s\n\n", event, compact.Bytes())
+	return buf.Bytes(), nil
+}
+
+// legacyRoomID восстанавливает прежнее имя комнаты ("tournament_5") для старых клиентов.
+func legacyRoomID(topic string) string {
+	if strings.HasPrefix(topic, topicTournament+":") {
+		return "tournament_" + strings.TrimPrefix(topic, topicTournament+":")
+	}
+	return topic
+}
+
+func (c *Client) trySend(data []byte) bool {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+	return c.sendLocked(data)
+}
+
+// enqueue отправляет событие или, если по топику идёт досылка, откладывает его до её окончания.
+func (c *Client) enqueue(topic string, seq int64, data []byte) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+	if pending, ok := c.pending[topic]; ok {
+		c.pending[topic] = append(pending, pendingFrame{seq: seq, data: data})
+		return
+	}
+	c.sendLocked(data)
+}
+
+func (c *Client) sendLocked(data []byte) bool {
+	if c.IsClosed {
+		return false
+	}
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		log.Printf("Client send channel full, dropping message")
+		return false
+	}
+}
+
+func (c *Client) sendFrame(frame ServerFrame) {
+	data, err := json.Marshal(frame)
 	if err != nil {
-		log.Printf("Error marshalling message for room %s: %v", roomID, err)
+		log.Printf("Error marshalling %s frame: %v", frame.Type, err)
 		return
 	}
+	c.trySend(data)
+}
 
-	log.Printf("Broadcasting to room %s: %s", roomID, string(messageBytes))
-	for client := range roomClients {
-		client.Mu.Lock()
-		if client.IsClosed {
-			client.Mu.Unlock()
-			continue
+// Topics возвращает отсортированный список подписок клиента.
+func (c *Client) Topics() []string {
+	c.Hub.mu.RLock()
+	defer c.Hub.mu.RUnlock()
+	topics := make([]string, 0, len(c.topics))
+	for t := range c.topics {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// SendWelcome отправляет приветственный кадр с версией протокола.
+func (c *Client) SendWelcome() {
+	c.sendFrame(ServerFrame{
+		V:       ProtocolVersion,
+		Type:    FrameWelcome,
+		Payload: WelcomePayload{ProtocolVersion: ProtocolVersion, UserID: c.UserID, Topics: c.Topics()},
+		Time:    time.Now().UTC(),
+	})
+}
+
+func (c *Client) handleFrame(frame ClientFrame) {
+	switch frame.Action {
+	case ActionAuth:
+		c.handleAuth(frame)
+	case ActionSubscribe:
+		c.handleSubscribe(frame)
+	case ActionUnsubscribe:
+		c.Hub.mu.Lock()
+		for _, topic := range frame.Topics {
+			c.Hub.unsubscribeLocked(c, topic)
 		}
-		select {
-		case client.Send <- messageBytes:
-		default:
-			log.Printf("Client's send channel full or closed for room %s. Skipping.", roomID)
+		c.Hub.mu.Unlock()
+		c.sendFrame(newAckFrame(frame.ID, AckPayload{Action: ActionUnsubscribe, Topics: c.Topics()}))
+	case ActionPing:
+		c.sendFrame(ServerFrame{V: ProtocolVersion, Type: FramePong, ID: frame.ID, Time: time.Now().UTC()})
+	default:
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeUnknownAction, "unknown action "+strconv.Quote(frame.Action)))
+	}
+}
+
+// handleAuth — браузер не может передать Authorization при апгрейде, поэтому токен приходит первым кадром.
+func (c *Client) handleAuth(frame ClientFrame) {
+	if c.framesRead != 1 {
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeAuthNotFirst, "auth must be the first frame"))
+		return
+	}
+	if c.Authenticate == nil || frame.Token == "" {
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeAuthFailed, "token is required"))
+		return
+	}
+	userID, err := c.Authenticate(frame.Token)
+	if err != nil {
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeAuthFailed, "invalid or expired token"))
+		return
+	}
+	c.Hub.mu.Lock()
+	c.UserID = &userID
+	c.Hub.mu.Unlock()
+	c.sendFrame(newAckFrame(frame.ID, AckPayload{Action: ActionAuth, UserID: &userID}))
+}
+
+func (c *Client) handleSubscribe(frame ClientFrame) {
+	if len(frame.Topics) == 0 {
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeInvalidTopic, "topics are required"))
+		return
+	}
+
+	// Проверяем все топики до подписки, чтобы кадр применялся целиком или не применялся вовсе
+	for _, topic := range frame.Topics {
+		pt, err := ParseTopic(topic)
+		if err != nil {
+			c.sendFrame(newErrorFrame(frame.ID, ErrCodeInvalidTopic, err.Error()))
+			return
+		}
+		if pt.Kind == topicUser && (c.UserID == nil || *c.UserID != pt.ID) {
+			c.sendFrame(newErrorFrame(frame.ID, ErrCodeForbiddenTopic, "cannot subscribe to "+topic))
+			return
+		}
+	}
+
+	c.Hub.mu.Lock()
+	newCount := len(c.topics)
+	for _, topic := range frame.Topics {
+		if !c.topics[topic] {
+			newCount++
 		}
-		client.Mu.Unlock()
 	}
+	if newCount > maxTopicsPerClient {
+		c.Hub.mu.Unlock()
+		c.sendFrame(newErrorFrame(frame.ID, ErrCodeTooManyTopics, "too many subscriptions, limit is "+strconv.Itoa(maxTopicsPerClient)))
+		return
+	}
+	resume := make(map[string]int64)
+	for _, topic := range frame.Topics {
+		c.Hub.subscribeLocked(c, topic)
+		if lastSeq, ok := frame.LastSeq[topic]; ok && c.Hub.events != nil && isSequencedTopic(topic) {
+			c.beginReplay(topic)
+			resume[topic] = lastSeq
+		}
+	}
+	c.Hub.mu.Unlock()
+
+	c.sendFrame(newAckFrame(frame.ID, AckPayload{Action: ActionSubscribe, Topics: c.Topics()}))
+	for topic, lastSeq := range resume {
+		c.replay(topic, lastSeq)
+	}
+}
+
+// PrepareResume вызывается до Register для клиента с Room, который переподключается: Register
+// сразу подписывает его на Room, и live-события нужно придерживать уже с этого момента, а не
+// с вызова Resume — иначе они уйдут раньше пропущенных или придут повторно при досылке.
+// Возвращает false, если досылка для топика недоступна; тогда Resume вызывать не нужно.
+func (c *Client) PrepareResume(topic string) bool {
+	if c.Hub.events == nil || !isSequencedTopic(topic) {
+		return false
+	}
+	c.beginReplay(topic)
+	return true
+}
+
+// Resume подписывает клиента на топик и досылает события после lastSeq (или просит загрузить снимок).
+func (c *Client) Resume(topic string, lastSeq int64) {
+	if c.Hub.events == nil || !isSequencedTopic(topic) {
+		return
+	}
+	c.Hub.mu.Lock()
+	c.Hub.subscribeLocked(c, topic)
+	c.beginReplay(topic)
+	c.Hub.mu.Unlock()
+	c.replay(topic, lastSeq)
+}
+
+// beginReplay начинает придерживать live-события топика; вызывается под Hub.mu вместе с подпиской,
+// чтобы между подпиской и чтением буфера ничего не потерялось.
+func (c *Client) beginReplay(topic string) {
+	c.Mu.Lock()
+	if c.pending == nil {
+		c.pending = make(map[string][]pendingFrame)
+	}
+	if _, ok := c.pending[topic]; !ok {
+		c.pending[topic] = make([]pendingFrame, 0)
+	}
+	c.Mu.Unlock()
+}
+
+func (c *Client) replay(topic string, lastSeq int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	f := c.format()
+	sentUpTo := lastSeq
+	events, current, gap, err := c.Hub.events.Since(ctx, topic, lastSeq, maxReplayEvents)
+	if err != nil {
+		log.Printf("Error loading events for topic %s after seq %d: %v", topic, lastSeq, err)
+		gap, current = true, 0
+	}
+
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	if gap {
+		if data, encErr := encodeSnapshotRequired(topic, current, f); encErr == nil {
+			c.sendLocked(data)
+		}
+		sentUpTo = current
+	} else {
+		for _, ev := range events {
+			data, encErr := encodeEvent(ev, f)
+			if encErr != nil {
+				log.Printf("Error marshalling replayed event %d for topic %s: %v", ev.Seq, topic, encErr)
+				continue
+			}
+			c.sendLocked(data)
+			sentUpTo = ev.Seq
+		}
+	}
+
+	// Отложенные live-события: пропускаем уже отправленные при досылке
+	for _, p := range c.pending[topic] {
+		if p.seq == 0 || p.seq > sentUpTo {
+			c.sendLocked(p.data)
+		}
+	}
+	delete(c.pending, topic)
 }
 
 func (c *Client) ReadPump() {
 	defer func() {
-		c.Hub.Unregister <- c // <--- Используем c.Hub.Unregister (поле Hub экспортируемое, Unregister тоже)
+		c.Hub.Unregister <- c
 		c.Conn.Close()
 		c.Mu.Lock()
 		c.IsClosed = true
 		c.Mu.Unlock()
-		log.Printf("Client readPump closed for room %s", c.Room)
+		log.Printf("Client readPump closed")
 	}()
 	c.Conn.SetReadLimit(maxMessageSize)
 	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -160,10 +526,25 @@ func (c *Client) ReadPump() {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("error: %v", err)
 			}
-			log.Printf("Client in room %s disconnected: %v", c.Room, err)
+			log.Printf("Client disconnected: %v", err)
 			break
 		}
-		log.Printf("Received message from client in room %s: %s (will be ignored by default)", c.Room, message)
+		if c.Room != "" {
+			// Устаревший режим: входящие сообщения игнорируются
+			continue
+		}
+
+		c.framesRead++
+		frame, err := decodeClientFrame(message)
+		if err != nil {
+			c.sendFrame(newErrorFrame("", ErrCodeBadFrame, "frame must be a JSON object"))
+			continue
+		}
+		if frame.V != ProtocolVersion {
+			c.sendFrame(newErrorFrame(frame.ID, ErrCodeUnsupportedVersion, "supported protocol version is "+strconv.Itoa(ProtocolVersion)))
+			continue
+		}
+		c.handleFrame(frame)
 	}
 }
 
@@ -175,7 +556,7 @@ func (c *Client) WritePump() {
 		c.Mu.Lock()
 		c.IsClosed = true
 		c.Mu.Unlock()
-		log.Printf("Client writePump closed for room %s", c.Room)
+		log.Printf("Client writePump closed")
 	}()
 	for {
 		select {
@@ -183,30 +564,18 @@ func (c *Client) WritePump() {
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
-				log.Printf("Hub closed client channel for room %s.", c.Room)
 				return
 			}
 
-			w, err := c.Conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				log.Printf("Error getting next writer for client in room %s: %v", c.Room, err)
-				return
-			}
-			w.Write(message)
-
-			n := len(c.Send)
-			for i := 0; i < n; i++ {
-				w.Write(<-c.Send)
-			}
-
-			if err := w.Close(); err != nil {
-				log.Printf("Error closing writer for client in room %s: %v", c.Room, err)
+			// Каждый кадр — отдельное сообщение: склейка нескольких JSON в одно ломает разбор на клиенте
+			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				log.Printf("Error writing message to client: %v", err)
 				return
 			}
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				log.Printf("Error sending ping to client in room %s: %v", c.Room, err)
+				log.Printf("Error sending ping to client: %v", err)
 				return
 			}
 		}
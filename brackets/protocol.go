@@ -0,0 +1,241 @@
+package brackets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+// ProtocolVersion — версия протокола WebSocket (поле "v" в каждом кадре).
+const ProtocolVersion = 1
+
+// Действия клиента.
+const (
+	ActionAuth        = "auth"
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+	ActionPing        = "ping"
+)
+
+// Типы кадров сервера.
+const (
+	FrameWelcome = "welcome"
+	FrameAck     = "ack"
+	FrameError   = "error"
+	FrameEvent   = "event"
+	FramePong    = "pong"
+	// FrameSnapshotRequired — пропущенные события дослать нельзя, клиент должен загрузить состояние заново
+	FrameSnapshotRequired = "snapshot_required"
+)
+
+// Коды ошибок протокола.
+const (
+	ErrCodeBadFrame           = "bad_frame"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeUnknownAction      = "unknown_action"
+	ErrCodeAuthFailed         = "auth_failed"
+	ErrCodeAuthNotFirst       = "auth_not_first_frame"
+	ErrCodeInvalidTopic       = "invalid_topic"
+	ErrCodeForbiddenTopic     = "forbidden_topic"
+	ErrCodeTooManyTopics      = "too_many_topics"
+)
+
+// Типы событий.
+const (
+	EventMatchUpdated                  = "MATCH_UPDATED"
+	EventStandingsUpdated              = "STANDINGS_UPDATED"
+	EventParticipantAdvanced           = "PARTICIPANT_ADVANCED"
+	EventTournamentFinalMatchCompleted = "TOURNAMENT_FINAL_MATCH_COMPLETED"
+	EventTournamentStatusUpdated       = "TOURNAMENT_STATUS_UPDATED"
+	EventBracketUpdated                = "BRACKET_UPDATED"
+	EventTournamentCompleted           = "TOURNAMENT_COMPLETED"
+	EventMatchStarted                  = "MATCH_STARTED"
+	EventMatchScoreUpdated             = "MATCH_SCORE_UPDATED"
+	EventMatchEventAdded               = "MATCH_EVENT_ADDED"
+	EventMatchEventDeleted             = "MATCH_EVENT_DELETED"
+	EventNotificationCreated           = "NOTIFICATION_CREATED" // Только в топик user:N
+)
+
+// ClientFrame — кадр от клиента.
+//
+//	{"v":1,"id":"1","action":"auth","token":"<jwt>"}
+//	{"v":1,"id":"2","action":"subscribe","topics":["tournament:5","match:solo:12","user:7"]}
+//	{"v":1,"id":"3","action":"subscribe","topics":["tournament:5"],"last_seq":{"tournament:5":42}}
+type ClientFrame struct {
+	V      int      `json:"v"`
+	ID     string   `json:"id,omitempty"` // Эхом возвращается в ack/error
+	Action string   `json:"action"`
+	Token  string   `json:"token,omitempty"`
+	Topics []string `json:"topics,omitempty"`
+	// LastSeq — последний обработанный номер события по топику; сервер дошлёт всё, что после него
+	LastSeq map[string]int64 `json:"last_seq,omitempty"`
+}
+
+// ServerFrame — кадр от сервера.
+type ServerFrame struct {
+	V       int            `json:"v"`
+	Type    string         `json:"type"`
+	ID      string         `json:"id,omitempty"`
+	Topic   string         `json:"topic,omitempty"`
+	Event   string         `json:"event,omitempty"`
+	Seq     int64          `json:"seq,omitempty"`
+	Payload interface{}    `json:"payload,omitempty"`
+	Error   *ProtocolError `json:"error,omitempty"`
+	Time    time.Time      `json:"ts"`
+}
+
+// ProtocolError — описание ошибки в кадре типа "error".
+type ProtocolError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WelcomePayload отправляется сразу после подключения.
+type WelcomePayload struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	UserID          *int     `json:"user_id,omitempty"`
+	Topics          []string `json:"topics"`
+}
+
+// AckPayload подтверждает auth/subscribe/unsubscribe.
+type AckPayload struct {
+	Action string   `json:"action"`
+	UserID *int     `json:"user_id,omitempty"`
+	Topics []string `json:"topics,omitempty"`
+}
+
+// SnapshotRequiredPayload — клиенту нужно перечитать состояние турнира (GET /tournaments/{id}/bracket)
+// и продолжить с CurrentSeq.
+type SnapshotRequiredPayload struct {
+	Topic      string `json:"topic"`
+	CurrentSeq int64  `json:"current_seq"`
+}
+
+// --- Типизированные полезные нагрузки событий ---
+
+type ParticipantAdvancedPayload struct {
+	TournamentID             int `json:"tournament_id"`
+	SourceMatchID            int `json:"source_match_id"`
+	AdvancingParticipantDBID int `json:"advancing_participant_db_id"`
+	NextMatchID              int `json:"next_match_id"`
+	NextMatchSlot            int `json:"next_match_slot"`
+}
+
+type FinalMatchCompletedPayload struct {
+	TournamentID           int  `json:"tournament_id"`
+	MatchID                int  `json:"match_id"`
+	WinnerParticipantID    int  `json:"winner_participant_id"`
+	IsTournamentFinalMatch bool `json:"is_tournament_final_match"`
+}
+
+type TournamentStatusPayload struct {
+	TournamentID int                     `json:"tournament_id"`
+	NewStatus    models.TournamentStatus `json:"new_status"`
+	OldStatus    models.TournamentStatus `json:"old_status"`
+}
+
+// LiveScorePayload — счёт идущего матча (MATCH_STARTED, MATCH_SCORE_UPDATED).
+type LiveScorePayload struct {
+	TournamentID int                    `json:"tournament_id"`
+	MatchID      int                    `json:"match_id"`
+	MatchType    models.RatingMatchType `json:"match_type"`
+	Status       models.MatchStatus     `json:"status"`
+	Score        *string                `json:"score,omitempty"`
+	Score1       *int                   `json:"score1,omitempty"` // P1/T1
+	Score2       *int                   `json:"score2,omitempty"` // P2/T2
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// MatchEventPayload — изменение хронологии матча (MATCH_EVENT_ADDED, MATCH_EVENT_DELETED).
+type MatchEventPayload struct {
+	TournamentID int                    `json:"tournament_id"`
+	MatchID      int                    `json:"match_id"`
+	MatchType    models.RatingMatchType `json:"match_type"`
+	EventID      int                    `json:"event_id"`
+	Event        *models.MatchEvent     `json:"event,omitempty"` // Только для MATCH_EVENT_ADDED
+}
+
+type TournamentCompletedPayload struct {
+	TournamentID          int         `json:"tournament_id"`
+	WinnerParticipantDBID *int        `json:"winner_participant_db_id"`
+	WinnerDetails         interface{} `json:"winner_details"`
+}
+
+// --- Топики ---
+
+const (
+	topicTournament = "tournament"
+	topicMatch      = "match"
+	topicUser       = "user"
+)
+
+func TournamentTopic(tournamentID int) string {
+	return topicTournament + ":" + strconv.Itoa(tournamentID)
+}
+
+// MatchTopic — топик матча; matchType — "solo" или "team".
+func MatchTopic(matchType models.RatingMatchType, matchID int) string {
+	return topicMatch + ":" + string(matchType) + ":" + strconv.Itoa(matchID)
+}
+
+// UserTopic — персональные уведомления пользователя (подписаться может только он сам).
+func UserTopic(userID int) string {
+	return topicUser + ":" + strconv.Itoa(userID)
+}
+
+// ParsedTopic — разобранное имя топика.
+type ParsedTopic struct {
+	Kind      string
+	MatchType models.RatingMatchType
+	ID        int
+}
+
+func ParseTopic(topic string) (ParsedTopic, error) {
+	parts := strings.Split(topic, ":")
+	var pt ParsedTopic
+	var idStr string
+
+	switch {
+	case len(parts) == 2 && (parts[0] == topicTournament || parts[0] == topicUser):
+		pt.Kind, idStr = parts[0], parts[1]
+	case len(parts) == 3 && parts[0] == topicMatch:
+		mt := models.RatingMatchType(parts[1])
+		if mt != models.RatingMatchSolo && mt != models.RatingMatchTeam {
+			return pt, fmt.Errorf("unknown match type %q in topic %q", parts[1], topic)
+		}
+		pt.Kind, pt.MatchType, idStr = parts[0], mt, parts[2]
+	default:
+		return pt, fmt.Errorf("unknown topic %q", topic)
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return pt, fmt.Errorf("invalid id in topic %q", topic)
+	}
+	pt.ID = id
+	return pt, nil
+}
+
+func newEventFrame(ev Event) ServerFrame {
+	return ServerFrame{V: ProtocolVersion, Type: FrameEvent, Topic: ev.Topic, Event: ev.Event, Seq: ev.Seq, Payload: ev.Payload, Time: time.Now().UTC()}
+}
+
+func newErrorFrame(id, code, message string) ServerFrame {
+	return ServerFrame{V: ProtocolVersion, Type: FrameError, ID: id, Error: &ProtocolError{Code: code, Message: message}, Time: time.Now().UTC()}
+}
+
+func newAckFrame(id string, payload AckPayload) ServerFrame {
+	return ServerFrame{V: ProtocolVersion, Type: FrameAck, ID: id, Payload: payload, Time: time.Now().UTC()}
+}
+
+func decodeClientFrame(data []byte) (ClientFrame, error) {
+	var frame ClientFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return frame, err
+	}
+	return frame, nil
+}
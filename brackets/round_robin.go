@@ -2,7 +2,6 @@ package brackets
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
 
@@ -31,14 +30,11 @@ func (g *RoundRobinGenerator) GenerateBracket(ctx context.Context, params Genera
 	}
 
 	rrSettings := &models.RoundRobinSettings{NumberOfRounds: 1} // Default to 1 round
-	if tournament.Format != nil && tournament.Format.SettingsJSON != nil && *tournament.Format.SettingsJSON != "" {
-		var settings models.RoundRobinSettings
-		if err := json.Unmarshal([]byte(*tournament.Format.SettingsJSON), &settings); err == nil {
-			if settings.NumberOfRounds == 1 || settings.NumberOfRounds == 2 {
-				rrSettings.NumberOfRounds = settings.NumberOfRounds
-			}
-		} else {
-			// Log or handle error if settings are invalid, but proceed with default
+	if tournament.Format != nil {
+		// Настройки проверяются по схеме при сохранении формата; значения по умолчанию подставляет модель
+		if settings, err := tournament.Format.GetRoundRobinSettings(); err == nil && settings != nil {
+			rrSettings = settings
+		} else if err != nil {
 			fmt.Printf("Warning: Could not parse RoundRobin settings for tournament %d: %v. Defaulting to 1 round.\n", tournament.ID, err)
 		}
 	}
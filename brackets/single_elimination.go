@@ -78,7 +78,17 @@ func (g *SingleEliminationGenerator) GenerateBracket(ctx context.Context, params
 	currentRoundNodes := make([]*node, sizeOfFullBracket)
 	participantIdx := 0
 
-	if numByes > 0 {
+	if params.Seeded {
+		// Стандартная расстановка: 1-й посев против последнего, сильнейшие получают bye
+		for i, seed := range seedPositions(sizeOfFullBracket) {
+			if seed <= n {
+				pid := shuffledParticipants[seed-1].ID
+				currentRoundNodes[i] = &node{participantID: &pid}
+			} else {
+				currentRoundNodes[i] = &node{isByePlaceholder: true}
+			}
+		}
+	} else if numByes > 0 {
 		for i := 0; i < n; i++ {
 			pid := shuffledParticipants[participantIdx].ID
 			currentRoundNodes[i] = &node{participantID: &pid}
@@ -180,3 +190,18 @@ func (g *SingleEliminationGenerator) GenerateBracket(ctx context.Context, params
 
 	return allGeneratedMatches, nil
 }
+
+// seedPositions возвращает номера посева (с 1) для каждой позиции сетки размера size,
+// так что 1-й и 2-й посевы могут встретиться только в финале.
+func seedPositions(size int) []int {
+	positions := []int{1}
+	for len(positions) < size {
+		total := len(positions)*2 + 1
+		next := make([]int, 0, len(positions)*2)
+		for _, seed := range positions {
+			next = append(next, seed, total-seed)
+		}
+		positions = next
+	}
+	return positions
+}
@@ -11,11 +11,14 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Часовые пояса пользователей в alpine-образе без tzdata
 
 	"github.com/Dosada05/tournament-system/brackets"
 	"github.com/Dosada05/tournament-system/config"
 	"github.com/Dosada05/tournament-system/db"
 	"github.com/Dosada05/tournament-system/handlers"
+	"github.com/Dosada05/tournament-system/imaging"
+	"github.com/Dosada05/tournament-system/mailer"
 	"github.com/Dosada05/tournament-system/repositories"
 	api "github.com/Dosada05/tournament-system/routes"
 	"github.com/Dosada05/tournament-system/services"
@@ -25,6 +28,8 @@ import (
 )
 
 const schedulerInterval = 30 * time.Second
+const emailOutboxInterval = 10 * time.Second
+const webhookDeliveryInterval = 10 * time.Second
 
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -50,22 +55,76 @@ func main() {
 	}()
 	logger.Info("database connection established")
 
-	cloudflareUploader, err := storage.NewCloudflareR2Uploader(storage.CloudflareR2UploaderConfig{
-		AccountID:       cfg.R2AccountID,
-		AccessKeyID:     cfg.R2AccessKeyID,
-		SecretAccessKey: cfg.R2SecretAccessKey,
-		BucketName:      cfg.R2BucketName,
-		PublicBaseURL:   cfg.R2PublicBaseURL,
+	var fileUploader storage.FileUploader
+	var uploadsHandler http.Handler // Раздача файлов по /uploads/ для бэкендов без собственного CDN
+	switch cfg.StorageBackend {
+	case "local":
+		var localUploader *storage.LocalUploader
+		localUploader, err = storage.NewLocalUploader(storage.LocalUploaderConfig{
+			Dir:           cfg.StorageDir,
+			PublicBaseURL: cfg.StoragePublicBaseURL,
+		})
+		if err == nil {
+			fileUploader = localUploader
+			uploadsHandler = localUploader.Handler()
+		}
+	case "memory":
+		memoryUploader := storage.NewMemoryUploader(cfg.StoragePublicBaseURL)
+		fileUploader = memoryUploader
+		uploadsHandler = memoryUploader.Handler()
+	default:
+		fileUploader, err = storage.NewCloudflareR2Uploader(storage.CloudflareR2UploaderConfig{
+			AccountID:       cfg.R2AccountID,
+			AccessKeyID:     cfg.R2AccessKeyID,
+			SecretAccessKey: cfg.R2SecretAccessKey,
+			BucketName:      cfg.R2BucketName,
+			PublicBaseURL:   cfg.R2PublicBaseURL,
+		})
+	}
+	if err != nil {
+		logger.Error("failed to initialize file storage", slog.String("backend", cfg.StorageBackend), slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Info("File storage initialized", slog.String("backend", cfg.StorageBackend))
+
+	imageProcessor := imaging.NewProcessor(imaging.Config{
+		MaxBytes:     cfg.ImageMaxBytes,
+		MaxDimension: cfg.ImageMaxDimension,
 	})
+
+	var mailTransport mailer.Transport
+	switch cfg.MailTransport {
+	case "file":
+		mailTransport, err = mailer.NewFileTransport(cfg.MailDir)
+	case "memory":
+		mailTransport = mailer.NewMemoryTransport()
+	default:
+		mailTransport, err = mailer.NewSMTPTransport(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPass,
+			TLSMode:  cfg.SMTPTLS,
+		})
+	}
 	if err != nil {
-		logger.Error("failed to initialize Cloudflare R2 uploader", slog.Any("error", err))
+		logger.Error("failed to initialize mail transport", slog.String("transport", cfg.MailTransport), slog.Any("error", err))
 		os.Exit(1)
 	}
-	logger.Info("Cloudflare R2 uploader initialized")
+	logger.Info("Mail transport initialized", slog.String("transport", cfg.MailTransport))
 
 	wsHub := brackets.NewHub()
+	if cfg.WSBroadcaster == "postgres" {
+		pgBroadcaster := brackets.NewPostgresBroadcaster(cfg.DatabaseURL, dbConn)
+		if err := wsHub.UseBroadcaster(context.Background(), pgBroadcaster); err != nil {
+			logger.Error("failed to start Postgres WebSocket broadcaster", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer pgBroadcaster.Close()
+	}
+	wsHub.UseEventStore(brackets.NewPostgresEventStore(dbConn))
 	go wsHub.Run()
-	logger.Info("WebSocket Hub started")
+	logger.Info("WebSocket Hub started", slog.String("broadcaster", cfg.WSBroadcaster))
 
 	userRepo := repositories.NewPostgresUserRepository(dbConn)
 	teamRepo := repositories.NewPostgresTeamRepository(dbConn)
@@ -77,25 +136,45 @@ func main() {
 	soloMatchRepo := repositories.NewPostgresSoloMatchRepository(dbConn)
 	teamMatchRepo := repositories.NewPostgresTeamMatchRepository(dbConn)
 	standingRepo := repositories.NewPostgresTournamentStandingRepository(dbConn)
+	organizationRepo := repositories.NewPostgresOrganizationRepository(dbConn)
+	seasonRepo := repositories.NewPostgresSeasonRepository(dbConn)
+	ratingRepo := repositories.NewPostgresRatingRepository(dbConn)
+	placementRepo := repositories.NewPostgresTournamentPlacementRepository(dbConn)
+	careerRepo := repositories.NewPostgresCareerRepository(dbConn)
+	matchEventRepo := repositories.NewPostgresMatchEventRepository(dbConn)
+	bracketPreviewRepo := repositories.NewPostgresBracketPreviewRepository(dbConn)
+	emailOutboxRepo := repositories.NewPostgresEmailOutboxRepository(dbConn)
+	notificationRepo := repositories.NewPostgresNotificationRepository(dbConn)
+	matchReminderRepo := repositories.NewPostgresMatchReminderRepository(dbConn)
+	webhookRepo := repositories.NewPostgresWebhookRepository(dbConn)
+	calendarRepo := repositories.NewPostgresCalendarRepository(dbConn)
+	logoKeyRepo := repositories.NewPostgresLogoKeyRepository(dbConn)
 	logger.Info("Repositories initialized")
 
-	emailService := services.NewEmailService(cfg)
-	authService := services.NewAuthService(userRepo)
-	userService := services.NewUserService(userRepo, cloudflareUploader)
-	sportService := services.NewSportService(sportRepo, userRepo, cloudflareUploader)
+	emailService := services.NewEmailService(cfg, mailTransport)
+	emailOutboxService := services.NewEmailOutboxService(emailOutboxRepo, emailService, logger)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, participantRepo, emailService, emailOutboxService, wsHub, cfg.PublicURL, logger)
+	webhookService := services.NewWebhookService(webhookRepo, organizationRepo, logger)
+	authService := services.NewAuthService(dbConn, userRepo, emailService, emailOutboxService, logger)
+	userService := services.NewUserService(userRepo, placementRepo, fileUploader, imageProcessor)
+	sportService := services.NewSportService(sportRepo, userRepo, fileUploader, imageProcessor)
 	formatService := services.NewFormatService(formatRepo)
-	teamService := services.NewTeamService(teamRepo, userRepo, sportRepo, cloudflareUploader)
-	inviteService := services.NewInviteService(inviteRepo, teamRepo, userRepo)
+	teamService := services.NewTeamService(teamRepo, userRepo, sportRepo, placementRepo, fileUploader, imageProcessor)
+	inviteService := services.NewInviteService(dbConn, inviteRepo, teamRepo, userRepo, emailService, emailOutboxService, notificationService, logger)
 	adminService := services.NewAdminUserService(userRepo)
 
 	dashboardService := services.NewDashboardService(userRepo, tournamentRepo, soloMatchRepo, teamMatchRepo)
 
+	ratingService := services.NewRatingService(dbConn, ratingRepo, sportRepo, fileUploader, logger)
+
 	bracketService := services.NewBracketService(
 		formatRepo,
 		participantRepo,
 		soloMatchRepo,
 		teamMatchRepo,
 		standingRepo,
+		bracketPreviewRepo,
+		ratingService,
 		logger,
 	)
 
@@ -107,10 +186,30 @@ func main() {
 		participantRepo,
 		formatRepo,
 		standingRepo,
+		organizationRepo,
+		sportRepo,
+		matchEventRepo,
+		ratingService,
+		notificationService,
+		webhookService,
 		wsHub,
 		logger,
 	)
 
+	seasonService := services.NewSeasonService(
+		seasonRepo,
+		tournamentRepo,
+		sportRepo,
+		formatRepo,
+		participantRepo,
+		soloMatchRepo,
+		teamMatchRepo,
+		standingRepo,
+		organizationRepo,
+		fileUploader,
+		logger,
+	)
+
 	tournamentService := services.NewTournamentService(
 		dbConn,
 		tournamentRepo,
@@ -121,9 +220,15 @@ func main() {
 		soloMatchRepo,
 		teamMatchRepo,
 		standingRepo,
+		organizationRepo,
+		placementRepo,
 		bracketService,
 		matchService,
-		cloudflareUploader,
+		seasonService,
+		fileUploader,
+		imageProcessor,
+		notificationService,
+		webhookService,
 		wsHub,
 		logger,
 	)
@@ -133,8 +238,16 @@ func main() {
 		userRepo,
 		teamRepo,
 		formatRepo,
-		cloudflareUploader,
+		organizationRepo,
+		fileUploader,
+		notificationService,
+		webhookService,
 	)
+	organizationService := services.NewOrganizationService(dbConn, organizationRepo, userRepo, teamRepo, tournamentRepo, fileUploader)
+	careerService := services.NewCareerService(careerRepo, userRepo, teamRepo, fileUploader)
+	calendarService := services.NewCalendarService(calendarRepo, tournamentRepo, teamRepo, userRepo, cfg.PublicURL)
+	matchReminderService := services.NewMatchReminderService(dbConn, matchReminderRepo, notificationService, cfg.MatchReminderLead, logger)
+	storageGCService := services.NewStorageGCService(logoKeyRepo, fileUploader, logger)
 	logger.Info("Services initialized")
 
 	go func() {
@@ -152,21 +265,92 @@ func main() {
 				if err := tournamentService.AutoUpdateTournamentStatusesByDates(context.Background()); err != nil {
 					logger.Error("Scheduler: periodic run failed", slog.Any("error", err))
 				}
+				if sent, err := matchReminderService.SendDueReminders(context.Background()); err != nil {
+					logger.Error("Scheduler: match reminders failed", slog.Any("error", err))
+				} else if sent > 0 {
+					logger.Info("Scheduler: match reminders sent", slog.Int("count", sent))
+				}
 			}
 		}
 	}()
 
-	authHandler := handlers.NewAuthHandler(authService, emailService, cfg.JWTSecretKey)
+	go func() {
+		ticker := time.NewTicker(emailOutboxInterval)
+		defer ticker.Stop()
+		logger.Info("Email outbox worker started", slog.Duration("interval", emailOutboxInterval))
+
+		for range ticker.C {
+			// Разбираем очередь пачками, пока в ней есть готовые к отправке письма
+			for {
+				sent, err := emailOutboxService.ProcessDue(context.Background())
+				if err != nil {
+					logger.Error("Email outbox: processing failed", slog.Any("error", err))
+					break
+				}
+				if sent == 0 {
+					break
+				}
+				logger.Info("Email outbox: emails sent", slog.Int("count", sent))
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(webhookDeliveryInterval)
+		defer ticker.Stop()
+		logger.Info("Webhook delivery worker started", slog.Duration("interval", webhookDeliveryInterval))
+
+		for range ticker.C {
+			for {
+				delivered, err := webhookService.ProcessDue(context.Background())
+				if err != nil {
+					logger.Error("Webhooks: processing failed", slog.Any("error", err))
+					break
+				}
+				if delivered == 0 {
+					break
+				}
+				logger.Info("Webhooks: deliveries sent", slog.Int("count", delivered))
+			}
+		}
+	}()
+
+	if cfg.StorageGCInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.StorageGCInterval)
+			defer ticker.Stop()
+			logger.Info("Storage GC worker started", slog.Duration("interval", cfg.StorageGCInterval), slog.Duration("grace_period", cfg.StorageGCGracePeriod))
+
+			for range ticker.C {
+				opts := services.StorageGCOptions{GracePeriod: cfg.StorageGCGracePeriod}
+				if _, err := storageGCService.CollectGarbage(context.Background(), opts); err != nil {
+					logger.Error("Storage GC: run failed", slog.Any("error", err))
+				}
+			}
+		}()
+	}
+
+	authHandler := handlers.NewAuthHandler(authService, cfg.JWTSecretKey)
 	userHandler := handlers.NewUserHandler(userService)
 	teamHandler := handlers.NewTeamHandler(teamService, userService)
 	sportHandler := handlers.NewSportHandler(sportService)
 	formatHandler := handlers.NewFormatHandler(formatService)
 	tournamentHandler := handlers.NewTournamentHandler(tournamentService, matchService)
-	inviteHandler := handlers.NewInviteHandler(inviteService, emailService, cfg.PublicURL)
+	inviteHandler := handlers.NewInviteHandler(inviteService)
 	participantHandler := handlers.NewParticipantHandler(participantService)
 	webSocketHandler := handlers.NewWebSocketHandler(wsHub)
 	adminHandler := handlers.NewAdminUserHandler(adminService)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService)
+	seasonHandler := handlers.NewSeasonHandler(seasonService)
+	ratingHandler := handlers.NewRatingHandler(ratingService)
+	careerHandler := handlers.NewCareerHandler(careerService)
+	sseHandler := handlers.NewSSEHandler(wsHub, tournamentService, cfg.TrustedProxies)
+	emailOutboxHandler := handlers.NewEmailOutboxHandler(emailOutboxService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	storageHandler := handlers.NewStorageHandler(storageGCService, cfg.StorageGCGracePeriod)
 	logger.Info("HTTP handlers initialized")
 
 	router := chi.NewRouter()
@@ -183,7 +367,20 @@ func main() {
 		formatHandler,
 		adminHandler,
 		dashboardHandler,
+		organizationHandler,
+		seasonHandler,
+		ratingHandler,
+		careerHandler,
+		sseHandler,
+		emailOutboxHandler,
+		notificationHandler,
+		webhookHandler,
+		calendarHandler,
+		storageHandler,
 	)
+	if uploadsHandler != nil {
+		router.Handle("/uploads/*", http.StripPrefix("/uploads/", uploadsHandler))
+	}
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{"https://tournament-frontend-fgm0.onrender.com", "http://localhost:5173", "https://heartbit.live", "https://www.heartbit.live"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -0,0 +1,59 @@
+// Команда полного пересчёта рейтингов по истории завершённых матчей.
+//
+//	go run ./cmd/rebuild-ratings            # все виды спорта
+//	go run ./cmd/rebuild-ratings -sport 3   # только sport_id = 3
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Dosada05/tournament-system/config"
+	"github.com/Dosada05/tournament-system/db"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/services"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	sportFlag := flag.Int("sport", 0, "rebuild ratings only for this sport ID (0 = all sports)")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("failed to load configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	dbConn, err := db.Connect(cfg.DatabaseURL, 5*time.Second)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer dbConn.Close()
+
+	ratingService := services.NewRatingService(
+		dbConn,
+		repositories.NewPostgresRatingRepository(dbConn),
+		repositories.NewPostgresSportRepository(dbConn),
+		nil,
+		logger,
+	)
+
+	var sportID *int
+	if *sportFlag > 0 {
+		sportID = sportFlag
+	}
+
+	start := time.Now()
+	if err := ratingService.RebuildRatings(context.Background(), sportID); err != nil {
+		logger.Error("ratings rebuild failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Info("ratings rebuilt", slog.Any("sport_id", sportID), slog.Duration("took", time.Since(start)))
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -19,11 +22,33 @@ type Config struct {
 	R2PublicBaseURL   string
 	PublicURL         string
 
+	StorageBackend       string // "r2", "local" (файлы в StorageDir) или "memory"
+	StorageDir           string
+	StoragePublicBaseURL string // Базовый URL раздачи файлов локального хранилища; по умолчанию PUBLIC_URL + "/uploads/"
+
+	ImageMaxBytes     int64 // Максимальный размер загружаемого логотипа/аватара
+	ImageMaxDimension int   // Максимальная ширина/высота загружаемого изображения в пикселях
+
+	StorageGCInterval    time.Duration // Как часто удалять неиспользуемые файлы из хранилища; 0 — не удалять
+	StorageGCGracePeriod time.Duration // Файлы моложе этого срока сборка мусора не трогает
+
 	SMTPHost string
 	SMTPPort int
 	SMTPUser string
 	SMTPPass string
 	SMTPFrom string
+	SMTPTLS  string // "starttls", "tls" или "none"; пусто — по порту
+
+	SMTPReplyTo   string
+	MailTransport string // "smtp", "file" (.eml в MailDir) или "memory"
+	MailDir       string
+
+	WSBroadcaster string // "memory" (один экземпляр) или "postgres" (LISTEN/NOTIFY между экземплярами)
+
+	// TrustedProxies — адреса прокси, чьим X-Forwarded-For/X-Real-IP можно верить при лимитах по IP
+	TrustedProxies []*net.IPNet
+
+	MatchReminderLead time.Duration // За сколько до начала матча напоминать участникам; 0 — не напоминать
 }
 
 func Load() (*Config, error) {
@@ -51,25 +76,93 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("SERVER_PORT must be between 1 and 65535, got %d", port)
 	}
 
+	// Хранилище загрузок. По умолчанию — R2, если заданы его ключи, иначе локальная директория
 	r2AccountID := os.Getenv("R2_ACCOUNT_ID")
-	if r2AccountID == "" {
-		return nil, fmt.Errorf("R2_ACCOUNT_ID environment variable is not set")
+	storageBackend := os.Getenv("STORAGE_BACKEND")
+	if storageBackend == "" {
+		storageBackend = "local"
+		if r2AccountID != "" {
+			storageBackend = "r2"
+		}
 	}
-	r2AccessKeyID := os.Getenv("R2_ACCESS_KEY_ID")
-	if r2AccessKeyID == "" {
-		return nil, fmt.Errorf("R2_ACCESS_KEY_ID environment variable is not set")
+	if storageBackend != "r2" && storageBackend != "local" && storageBackend != "memory" {
+		return nil, fmt.Errorf("STORAGE_BACKEND must be 'r2', 'local' or 'memory', got %q", storageBackend)
 	}
+
+	// R2 config — обязателен только для хранилища r2
+	r2AccessKeyID := os.Getenv("R2_ACCESS_KEY_ID")
 	r2SecretAccessKey := os.Getenv("R2_SECRET_ACCESS_KEY")
-	if r2SecretAccessKey == "" {
-		return nil, fmt.Errorf("R2_SECRET_ACCESS_KEY environment variable is not set")
-	}
 	r2BucketName := os.Getenv("R2_BUCKET_NAME")
-	if r2BucketName == "" {
-		return nil, fmt.Errorf("R2_BUCKET_NAME environment variable is not set")
-	}
 	r2PublicBaseURL := os.Getenv("R2_PUBLIC_BASE_URL")
-	if r2PublicBaseURL == "" {
-		return nil, fmt.Errorf("R2_PUBLIC_BASE_URL environment variable is not set")
+	if storageBackend == "r2" {
+		if r2AccountID == "" {
+			return nil, fmt.Errorf("R2_ACCOUNT_ID environment variable is not set")
+		}
+		if r2AccessKeyID == "" {
+			return nil, fmt.Errorf("R2_ACCESS_KEY_ID environment variable is not set")
+		}
+		if r2SecretAccessKey == "" {
+			return nil, fmt.Errorf("R2_SECRET_ACCESS_KEY environment variable is not set")
+		}
+		if r2BucketName == "" {
+			return nil, fmt.Errorf("R2_BUCKET_NAME environment variable is not set")
+		}
+		if r2PublicBaseURL == "" {
+			return nil, fmt.Errorf("R2_PUBLIC_BASE_URL environment variable is not set")
+		}
+	}
+
+	storageDir := os.Getenv("STORAGE_DIR")
+	if storageDir == "" {
+		storageDir = "uploads"
+	}
+
+	// Ограничения на загружаемые логотипы и аватары
+	imageMaxMBStr := os.Getenv("IMAGE_MAX_UPLOAD_MB")
+	if imageMaxMBStr == "" {
+		imageMaxMBStr = "10"
+	}
+	imageMaxMB, err := strconv.Atoi(imageMaxMBStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAGE_MAX_UPLOAD_MB environment variable: %w", err)
+	}
+	if imageMaxMB <= 0 {
+		return nil, fmt.Errorf("IMAGE_MAX_UPLOAD_MB must be positive, got %d", imageMaxMB)
+	}
+	imageMaxDimensionStr := os.Getenv("IMAGE_MAX_DIMENSION")
+	if imageMaxDimensionStr == "" {
+		imageMaxDimensionStr = "4096"
+	}
+	imageMaxDimension, err := strconv.Atoi(imageMaxDimensionStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAGE_MAX_DIMENSION environment variable: %w", err)
+	}
+	if imageMaxDimension <= 0 {
+		return nil, fmt.Errorf("IMAGE_MAX_DIMENSION must be positive, got %d", imageMaxDimension)
+	}
+
+	// Сборка мусора в хранилище, в часах
+	storageGCIntervalStr := os.Getenv("STORAGE_GC_INTERVAL_HOURS")
+	if storageGCIntervalStr == "" {
+		storageGCIntervalStr = "24"
+	}
+	storageGCIntervalHours, err := strconv.Atoi(storageGCIntervalStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_GC_INTERVAL_HOURS environment variable: %w", err)
+	}
+	if storageGCIntervalHours < 0 {
+		return nil, fmt.Errorf("STORAGE_GC_INTERVAL_HOURS must not be negative, got %d", storageGCIntervalHours)
+	}
+	storageGCGraceStr := os.Getenv("STORAGE_GC_GRACE_HOURS")
+	if storageGCGraceStr == "" {
+		storageGCGraceStr = "24"
+	}
+	storageGCGraceHours, err := strconv.Atoi(storageGCGraceStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_GC_GRACE_HOURS environment variable: %w", err)
+	}
+	if storageGCGraceHours < 0 {
+		return nil, fmt.Errorf("STORAGE_GC_GRACE_HOURS must not be negative, got %d", storageGCGraceHours)
 	}
 
 	publicURL := os.Getenv("PUBLIC_URL")
@@ -77,9 +170,33 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("PUBLIC_URL environment variable is not set")
 	}
 
-	// SMTP config
+	// Файлы локального хранилища и хранилища в памяти раздаются этим же сервером по /uploads/
+	storagePublicBaseURL := os.Getenv("STORAGE_PUBLIC_BASE_URL")
+	if storagePublicBaseURL == "" {
+		storagePublicBaseURL = strings.TrimRight(publicURL, "/") + "/uploads/"
+	}
+
+	// Почтовый транспорт
+	mailTransport := os.Getenv("MAIL_TRANSPORT")
+	if mailTransport == "" {
+		mailTransport = "smtp"
+	}
+	if mailTransport != "smtp" && mailTransport != "file" && mailTransport != "memory" {
+		return nil, fmt.Errorf("MAIL_TRANSPORT must be 'smtp', 'file' or 'memory', got %q", mailTransport)
+	}
+	mailDir := os.Getenv("MAIL_DIR")
+	if mailDir == "" {
+		mailDir = "tmp/emails"
+	}
+	smtpFrom := os.Getenv("SMTP_FROM")
+	if smtpFrom == "" {
+		return nil, fmt.Errorf("SMTP_FROM environment variable is not set")
+	}
+	smtpReplyTo := os.Getenv("SMTP_REPLY_TO")
+
+	// SMTP config — обязателен только для транспорта smtp
 	smtpHost := os.Getenv("SMTP_HOST")
-	if smtpHost == "" {
+	if smtpHost == "" && mailTransport == "smtp" {
 		return nil, fmt.Errorf("SMTP_HOST environment variable is not set")
 	}
 	smtpPortStr := os.Getenv("SMTP_PORT")
@@ -93,17 +210,63 @@ func Load() (*Config, error) {
 	if smtpPort <= 0 || smtpPort > 65535 {
 		return nil, fmt.Errorf("SMTP_PORT must be between 1 and 65535, got %d", smtpPort)
 	}
+	// Без SMTP_USER письма отправляются без аутентификации (локальный тестовый сервер)
 	smtpUser := os.Getenv("SMTP_USER")
-	if smtpUser == "" {
-		return nil, fmt.Errorf("SMTP_USER environment variable is not set")
-	}
 	smtpPass := os.Getenv("SMTP_PASS")
-	if smtpPass == "" {
+	if smtpUser != "" && smtpPass == "" {
 		return nil, fmt.Errorf("SMTP_PASS environment variable is not set")
 	}
-	smtpFrom := os.Getenv("SMTP_FROM")
-	if smtpFrom == "" {
-		return nil, fmt.Errorf("SMTP_FROM environment variable is not set")
+	// Пусто — по порту: 465 → tls, иначе starttls
+	smtpTLS := os.Getenv("SMTP_TLS")
+	if smtpTLS != "" && smtpTLS != "starttls" && smtpTLS != "tls" && smtpTLS != "none" {
+		return nil, fmt.Errorf("SMTP_TLS must be 'starttls', 'tls' or 'none', got %q", smtpTLS)
+	}
+
+	wsBroadcaster := os.Getenv("WS_BROADCASTER")
+	if wsBroadcaster == "" {
+		wsBroadcaster = "memory"
+	}
+	if wsBroadcaster != "memory" && wsBroadcaster != "postgres" {
+		return nil, fmt.Errorf("WS_BROADCASTER must be 'memory' or 'postgres', got %q", wsBroadcaster)
+	}
+
+	// Прокси перед сервером: адреса и подсети через запятую ("10.0.0.0/8,127.0.0.1")
+	var trustedProxies []*net.IPNet
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			trustedProxies = append(trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", entry, err)
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+
+	// Напоминания о матчах, в минутах
+	reminderMinutesStr := os.Getenv("MATCH_REMINDER_MINUTES")
+	if reminderMinutesStr == "" {
+		reminderMinutesStr = "30"
+	}
+	reminderMinutes, err := strconv.Atoi(reminderMinutesStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MATCH_REMINDER_MINUTES environment variable: %w", err)
+	}
+	if reminderMinutes < 0 {
+		return nil, fmt.Errorf("MATCH_REMINDER_MINUTES must not be negative, got %d", reminderMinutes)
 	}
 
 	cfg := &Config{
@@ -117,11 +280,32 @@ func Load() (*Config, error) {
 		R2PublicBaseURL:   r2PublicBaseURL,
 		PublicURL:         publicURL,
 
+		StorageBackend:       storageBackend,
+		StorageDir:           storageDir,
+		StoragePublicBaseURL: storagePublicBaseURL,
+
+		ImageMaxBytes:     int64(imageMaxMB) << 20,
+		ImageMaxDimension: imageMaxDimension,
+
+		StorageGCInterval:    time.Duration(storageGCIntervalHours) * time.Hour,
+		StorageGCGracePeriod: time.Duration(storageGCGraceHours) * time.Hour,
+
 		SMTPHost: smtpHost,
 		SMTPPort: smtpPort,
 		SMTPUser: smtpUser,
 		SMTPPass: smtpPass,
 		SMTPFrom: smtpFrom,
+		SMTPTLS:  smtpTLS,
+
+		SMTPReplyTo:   smtpReplyTo,
+		MailTransport: mailTransport,
+		MailDir:       mailDir,
+
+		WSBroadcaster: wsBroadcaster,
+
+		TrustedProxies: trustedProxies,
+
+		MatchReminderLead: time.Duration(reminderMinutes) * time.Minute,
 	}
 
 	return cfg, nil
@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Организации (клубы): уровень над турнирами и командами
+CREATE TYPE organization_role AS ENUM ('owner', 'admin', 'member');
+
+CREATE TABLE organizations (
+                               id SERIAL PRIMARY KEY,
+                               name VARCHAR(100) NOT NULL UNIQUE,
+                               description TEXT,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_organizations_name ON organizations (name);
+
+CREATE TABLE organization_members (
+                                      organization_id INT NOT NULL,
+                                      user_id INT NOT NULL,
+                                      role organization_role NOT NULL DEFAULT 'member',
+                                      created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                      PRIMARY KEY (organization_id, user_id),
+                                      CONSTRAINT fk_organization_members_org FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+                                      CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_organization_members_user_id ON organization_members (user_id);
+
+-- Турниры и команды могут принадлежать организации
+ALTER TABLE tournaments
+    ADD COLUMN organization_id INT,
+    ADD CONSTRAINT fk_tournaments_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE SET NULL;
+CREATE INDEX idx_tournaments_organization_id ON tournaments (organization_id);
+
+ALTER TABLE teams
+    ADD COLUMN organization_id INT,
+    ADD CONSTRAINT fk_teams_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE SET NULL;
+CREATE INDEX idx_teams_organization_id ON teams (organization_id);
+
+-- +migrate Down
+ALTER TABLE teams DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+DROP TYPE IF EXISTS organization_role;
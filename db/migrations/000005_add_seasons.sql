@@ -0,0 +1,54 @@
+-- +migrate Up
+-- Сезоны (лиги): объединяют турниры одного вида спорта и суммируют очки за места
+CREATE TABLE seasons (
+                         id SERIAL PRIMARY KEY,
+                         name VARCHAR(100) NOT NULL,
+                         description TEXT,
+                         sport_id INT NOT NULL,
+                         organizer_id INT NOT NULL,
+                         organization_id INT,
+                         points_table TEXT NOT NULL DEFAULT '[]', -- JSON-массив: индекс 0 = очки за 1 место
+                         participation_points INT NOT NULL DEFAULT 0, -- Очки за участие вне таблицы
+                         created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                         CONSTRAINT fk_seasons_sport FOREIGN KEY (sport_id) REFERENCES sports (id) ON DELETE RESTRICT,
+                         CONSTRAINT fk_seasons_organizer FOREIGN KEY (organizer_id) REFERENCES users (id) ON DELETE RESTRICT,
+                         CONSTRAINT fk_seasons_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE SET NULL,
+                         CONSTRAINT seasons_organizer_id_name_key UNIQUE (organizer_id, name)
+);
+CREATE INDEX idx_seasons_sport_id ON seasons (sport_id);
+CREATE INDEX idx_seasons_organization_id ON seasons (organization_id);
+
+-- Турнир может входить только в один сезон
+CREATE TABLE season_tournaments (
+                                    season_id INT NOT NULL,
+                                    tournament_id INT NOT NULL UNIQUE,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    PRIMARY KEY (season_id, tournament_id),
+                                    CONSTRAINT fk_season_tournaments_season FOREIGN KEY (season_id) REFERENCES seasons (id) ON DELETE CASCADE,
+                                    CONSTRAINT fk_season_tournaments_tournament FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE
+);
+
+-- Итоги завершённых турниров сезона; таблица лидеров считается агрегатом по этой таблице
+CREATE TABLE season_results (
+                                id SERIAL PRIMARY KEY,
+                                season_id INT NOT NULL,
+                                tournament_id INT NOT NULL,
+                                participant_id INT NOT NULL,
+                                user_id INT,
+                                team_id INT,
+                                placement INT NOT NULL,
+                                points INT NOT NULL DEFAULT 0,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                FOREIGN KEY (season_id) REFERENCES seasons (id) ON DELETE CASCADE,
+                                FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE,
+                                FOREIGN KEY (participant_id) REFERENCES participants (id) ON DELETE CASCADE,
+                                FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                                FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+                                UNIQUE (season_id, tournament_id, participant_id)
+);
+CREATE INDEX idx_season_results_season_id ON season_results (season_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS season_results;
+DROP TABLE IF EXISTS season_tournaments;
+DROP TABLE IF EXISTS seasons;
@@ -0,0 +1,55 @@
+-- +migrate Up
+-- Рейтинг (Glicko-2) игрока или команды в рамках вида спорта
+CREATE TABLE ratings (
+                         id SERIAL PRIMARY KEY,
+                         sport_id INT NOT NULL,
+                         user_id INT,
+                         team_id INT,
+                         rating DOUBLE PRECISION NOT NULL DEFAULT 1500,
+                         deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+                         volatility DOUBLE PRECISION NOT NULL DEFAULT 0.06,
+                         matches_played INT NOT NULL DEFAULT 0,
+                         wins INT NOT NULL DEFAULT 0,
+                         losses INT NOT NULL DEFAULT 0,
+                         draws INT NOT NULL DEFAULT 0,
+                         created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                         updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                         CONSTRAINT fk_ratings_sport FOREIGN KEY (sport_id) REFERENCES sports (id) ON DELETE CASCADE,
+                         CONSTRAINT fk_ratings_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                         CONSTRAINT fk_ratings_team FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+                         CONSTRAINT chk_rating_entity CHECK ((user_id IS NOT NULL AND team_id IS NULL) OR (user_id IS NULL AND team_id IS NOT NULL))
+);
+CREATE UNIQUE INDEX uq_ratings_sport_user ON ratings (sport_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX uq_ratings_sport_team ON ratings (sport_id, team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_ratings_sport_rating ON ratings (sport_id, rating DESC);
+
+-- История изменений рейтинга: одна запись на участника матча
+CREATE TABLE rating_history (
+                                id SERIAL PRIMARY KEY,
+                                rating_id INT NOT NULL,
+                                match_type VARCHAR(10) NOT NULL, -- 'solo' или 'team'
+                                match_id INT NOT NULL,
+                                tournament_id INT NOT NULL,
+                                opponent_rating_id INT,
+                                score DOUBLE PRECISION NOT NULL, -- 1 победа, 0.5 ничья, 0 поражение
+                                rating_before DOUBLE PRECISION NOT NULL,
+                                rating_after DOUBLE PRECISION NOT NULL,
+                                deviation_before DOUBLE PRECISION NOT NULL,
+                                deviation_after DOUBLE PRECISION NOT NULL,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                CONSTRAINT fk_rating_history_rating FOREIGN KEY (rating_id) REFERENCES ratings (id) ON DELETE CASCADE,
+                                CONSTRAINT fk_rating_history_opponent FOREIGN KEY (opponent_rating_id) REFERENCES ratings (id) ON DELETE SET NULL,
+                                FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE,
+                                CONSTRAINT chk_rating_history_match_type CHECK (match_type IN ('solo', 'team')),
+                                UNIQUE (rating_id, match_type, match_id)
+);
+CREATE INDEX idx_rating_history_rating_id ON rating_history (rating_id, id);
+CREATE INDEX idx_rating_history_match ON rating_history (match_type, match_id);
+
+-- Посев участников по рейтингу при генерации сетки
+ALTER TABLE tournaments ADD COLUMN seed_by_rating BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE tournaments DROP COLUMN IF EXISTS seed_by_rating;
+DROP TABLE IF EXISTS rating_history;
+DROP TABLE IF EXISTS ratings;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Итоговые места участников, фиксируются при завершении турнира
+CREATE TABLE tournament_placements (
+                                       tournament_id INT NOT NULL,
+                                       participant_id INT NOT NULL,
+                                       placement INT NOT NULL CHECK (placement > 0),
+                                       created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                       PRIMARY KEY (tournament_id, participant_id),
+                                       FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE,
+                                       FOREIGN KEY (participant_id) REFERENCES participants (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_tournament_placements_participant_id ON tournament_placements (participant_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS tournament_placements;
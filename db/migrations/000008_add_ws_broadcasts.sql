@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Полезные нагрузки WebSocket-событий, не помещающиеся в NOTIFY (лимит 8000 байт).
+-- Экземпляры API получают через NOTIFY только ссылку на строку; старые строки периодически удаляются.
+CREATE TABLE ws_broadcasts (
+                               id BIGSERIAL PRIMARY KEY,
+                               topic VARCHAR(255) NOT NULL,
+                               event VARCHAR(100) NOT NULL,
+                               payload JSONB NOT NULL,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_ws_broadcasts_created_at ON ws_broadcasts (created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS ws_broadcasts;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Последний выданный номер события для каждой комнаты (топика) WebSocket
+CREATE TABLE ws_topic_sequences (
+                                    topic VARCHAR(255) PRIMARY KEY,
+                                    last_seq BIGINT NOT NULL
+);
+
+-- Ограниченный буфер последних событий комнаты для досылки переподключившимся клиентам
+CREATE TABLE ws_events (
+                           topic VARCHAR(255) NOT NULL,
+                           seq BIGINT NOT NULL,
+                           event VARCHAR(100) NOT NULL,
+                           payload JSONB NOT NULL,
+                           created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                           PRIMARY KEY (topic, seq)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS ws_events;
+DROP TABLE IF EXISTS ws_topic_sequences;
@@ -0,0 +1,36 @@
+-- +migrate Up
+-- Какие события матча ведутся в виде спорта и считается ли итоговый счёт по голам
+ALTER TABLE sports
+    ADD COLUMN match_event_types TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN score_from_goals BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Хронология матча: голы, карточки, замены, выигранные раунды, тайм-ауты
+CREATE TABLE match_events (
+                              id SERIAL PRIMARY KEY,
+                              tournament_id INT NOT NULL,
+                              match_type VARCHAR(10) NOT NULL CHECK (match_type IN ('solo', 'team')),
+                              match_id INT NOT NULL,
+                              event_type VARCHAR(30) NOT NULL,
+                              participant_id INT NOT NULL,
+                              player_user_id INT NULL,
+                              related_user_id INT NULL,
+                              minute INT NULL CHECK (minute >= 0),
+                              period INT NULL CHECK (period > 0),
+                              card VARCHAR(10) NULL CHECK (card IN ('yellow', 'red')),
+                              note TEXT NULL,
+                              created_by INT NULL,
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                              FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE,
+                              FOREIGN KEY (participant_id) REFERENCES participants (id) ON DELETE CASCADE,
+                              FOREIGN KEY (player_user_id) REFERENCES users (id) ON DELETE SET NULL,
+                              FOREIGN KEY (related_user_id) REFERENCES users (id) ON DELETE SET NULL,
+                              FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX idx_match_events_match ON match_events (match_type, match_id);
+CREATE INDEX idx_match_events_tournament ON match_events (tournament_id, match_type);
+
+-- +migrate Down
+DROP TABLE IF EXISTS match_events;
+ALTER TABLE sports
+    DROP COLUMN IF EXISTS score_from_goals,
+    DROP COLUMN IF EXISTS match_event_types;
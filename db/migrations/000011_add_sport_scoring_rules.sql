@@ -0,0 +1,8 @@
+-- +migrate Up
+-- Правила счёта вида спорта: схема счёта, ничьи, сеты/леги, «меньше — лучше»
+ALTER TABLE sports
+    ADD COLUMN scoring_rules JSONB NOT NULL DEFAULT '{"schema": "points", "draw_allowed": true}';
+
+-- +migrate Down
+ALTER TABLE sports
+    DROP COLUMN IF EXISTS scoring_rules;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Зафиксированный организатором предпросмотр сетки: при активации турнира используется именно он
+CREATE TABLE bracket_previews (
+                                  tournament_id INT PRIMARY KEY,
+                                  bracket_type VARCHAR(50) NOT NULL,
+                                  participant_ids INT[] NOT NULL,
+                                  seeded BOOLEAN NOT NULL DEFAULT FALSE,
+                                  matches JSONB NOT NULL,
+                                  locked_by INT NULL,
+                                  locked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  FOREIGN KEY (tournament_id) REFERENCES tournaments (id) ON DELETE CASCADE,
+                                  FOREIGN KEY (locked_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS bracket_previews;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Исходящие письма: пишутся в той же транзакции, что и бизнес-изменение, отправляются фоновым воркером
+CREATE TABLE email_outbox (
+                              id BIGSERIAL PRIMARY KEY,
+                              kind VARCHAR(50) NOT NULL,
+                              recipients TEXT[] NOT NULL,
+                              subject TEXT NOT NULL,
+                              html_body TEXT NOT NULL,
+                              status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+                              attempts INT NOT NULL DEFAULT 0,
+                              max_attempts INT NOT NULL DEFAULT 8 CHECK (max_attempts > 0),
+                              next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                              last_error TEXT NULL,
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                              updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                              sent_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_email_outbox_due ON email_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_email_outbox_status ON email_outbox (status, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS email_outbox;
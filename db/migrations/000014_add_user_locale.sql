@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Язык писем и часовой пояс пользователя
+ALTER TABLE users
+ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'ru',
+ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- +migrate Down
+ALTER TABLE users
+DROP COLUMN IF EXISTS timezone,
+DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Уведомления пользователя (центр уведомлений в приложении)
+CREATE TABLE notifications (
+                               id BIGSERIAL PRIMARY KEY,
+                               user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                               type VARCHAR(50) NOT NULL,
+                               title TEXT NOT NULL,
+                               body TEXT NOT NULL,
+                               data JSONB NOT NULL DEFAULT '{}'::jsonb,
+                               read_at TIMESTAMPTZ NULL,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC, id DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Каналы доставки по типам уведомлений; отсутствие строки означает «всё включено»
+CREATE TABLE notification_preferences (
+                                          user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                          type VARCHAR(50) NOT NULL,
+                                          email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+                                          in_app_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+                                          updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                          PRIMARY KEY (user_id, type)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Отправленные напоминания о матчах. Ключ включает match_time: после переноса
+-- матча напоминание о новом времени будет отправлено ещё раз.
+CREATE TABLE match_reminders (
+                                 match_type VARCHAR(10) NOT NULL CHECK (match_type IN ('solo', 'team')),
+                                 match_id INT NOT NULL,
+                                 user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 match_time TIMESTAMPTZ NOT NULL,
+                                 sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                 PRIMARY KEY (match_type, match_id, user_id, match_time)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS match_reminders;
@@ -0,0 +1,41 @@
+-- +migrate Up
+-- Подписки на события: принадлежат либо организатору, либо организации
+CREATE TABLE webhooks (
+                          id SERIAL PRIMARY KEY,
+                          owner_user_id INT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          organization_id INT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+                          url TEXT NOT NULL,
+                          secret TEXT NOT NULL,
+                          events TEXT[] NOT NULL DEFAULT '{}', -- Пустой список — все события
+                          is_active BOOLEAN NOT NULL DEFAULT TRUE,
+                          created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                          updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                          CONSTRAINT chk_webhook_owner CHECK ((owner_user_id IS NOT NULL) <> (organization_id IS NOT NULL))
+);
+CREATE INDEX idx_webhooks_owner_user ON webhooks (owner_user_id) WHERE owner_user_id IS NOT NULL;
+CREATE INDEX idx_webhooks_organization ON webhooks (organization_id) WHERE organization_id IS NOT NULL;
+
+-- Журнал доставок; работает как очередь с повторами, аналогично email_outbox
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+                                    event VARCHAR(50) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+                                    attempts INT NOT NULL DEFAULT 0,
+                                    max_attempts INT NOT NULL DEFAULT 8 CHECK (max_attempts > 0),
+                                    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    response_status INT NULL,
+                                    last_error TEXT NULL,
+                                    redelivery_of BIGINT NULL REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    delivered_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Секретные токены персональных iCalendar-лент: ссылку можно открыть без авторизации,
+-- поэтому токен выдаётся отдельно и может быть перевыпущен.
+CREATE TABLE calendar_tokens (
+                                 user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+                                 token VARCHAR(64) NOT NULL UNIQUE,
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS calendar_tokens;
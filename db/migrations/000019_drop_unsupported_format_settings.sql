@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Настройки third_place_match, series_length и group_count генераторы сеток не поддерживали
+-- и убраны из схем; удаляем их из сохранённых форматов, чтобы те проходили проверку.
+-- Такие ключи могли попасть только через проверку схемой, поэтому settings_json здесь — валидный JSON.
+UPDATE formats
+SET settings_json = ((settings_json::jsonb) - 'third_place_match' - 'series_length' - 'group_count')::text
+WHERE settings_json LIKE '%third_place_match%'
+   OR settings_json LIKE '%series_length%'
+   OR settings_json LIKE '%group_count%';
+
+-- +migrate Down
+-- Удалённые значения не восстанавливаются.
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Момент завершения матча: в этом порядке рейтинги применяются при сохранении результата
+-- и при полном пересчёте. Для уже завершённых матчей берём время первой записи истории
+-- рейтинга (фактический порядок применения), иначе — время матча по расписанию.
+ALTER TABLE solo_matches ADD COLUMN completed_at TIMESTAMPTZ;
+ALTER TABLE team_matches ADD COLUMN completed_at TIMESTAMPTZ;
+
+UPDATE solo_matches m
+SET completed_at = COALESCE(
+        (SELECT MIN(h.created_at) FROM rating_history h WHERE h.match_type = 'solo' AND h.match_id = m.id),
+        m.match_time)
+WHERE m.status = 'completed';
+
+UPDATE team_matches m
+SET completed_at = COALESCE(
+        (SELECT MIN(h.created_at) FROM rating_history h WHERE h.match_type = 'team' AND h.match_id = m.id),
+        m.match_time)
+WHERE m.status = 'completed';
+
+-- +migrate Down
+ALTER TABLE team_matches DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE solo_matches DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- Настройки формата, с которыми построен предпросмотр: если организатор поменял их после
+-- фиксации, раскладка устарела. У ранее зафиксированных предпросмотров настроек нет —
+-- их придётся зафиксировать заново.
+ALTER TABLE bracket_previews ADD COLUMN format_settings JSONB;
+
+-- +migrate Down
+ALTER TABLE bracket_previews DROP COLUMN IF EXISTS format_settings;
@@ -3,6 +3,7 @@ module github.com/Dosada05/tournament-system
 go 1.23.1
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/aws/aws-sdk-go-v2 v1.36.3
 	github.com/aws/aws-sdk-go-v2/config v1.29.14
 	github.com/aws/aws-sdk-go-v2/credentials v1.17.67
@@ -14,6 +15,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
 	golang.org/x/crypto v0.37.0
+	golang.org/x/image v0.25.0
 	golang.org/x/sync v0.13.0
 )
 
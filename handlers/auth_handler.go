@@ -5,24 +5,23 @@ import (
 	"fmt"
 
 	"net/http"
+	"strings"
 	"time"
 
-	_ "github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/services"
 	"github.com/golang-jwt/jwt/v4"
 )
 
 type AuthHandler struct {
-	authService  services.AuthService
-	emailService *services.EmailService
-	jwtSecret    []byte
+	authService services.AuthService
+	jwtSecret   []byte
 }
 
-func NewAuthHandler(authService services.AuthService, emailService *services.EmailService, jwtSecret string) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, jwtSecret string) *AuthHandler {
 	return &AuthHandler{
-		authService:  authService,
-		emailService: emailService,
-		jwtSecret:    []byte(jwtSecret),
+		authService: authService,
+		jwtSecret:   []byte(jwtSecret),
 	}
 }
 
@@ -41,15 +40,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, confirmationToken, err := h.authService.Register(r.Context(), input)
+	if input.Locale == "" {
+		input.Locale = localeFromAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	// Приветственное письмо ставится в очередь внутри Register
+	user, _, err := h.authService.Register(r.Context(), input)
 	if err != nil {
 		mapServiceErrorToHTTP(w, r, err)
 		return
 	}
 
-	if err := h.emailService.SendWelcomeEmail(user.Email, confirmationToken); err != nil {
-		fmt.Println("Ошибка отправки email:", err)
-	}
 	response := jsonResponse{
 		"user": user,
 	}
@@ -136,14 +137,10 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		badRequestResponse(w, r, errors.New("email is required"))
 		return
 	}
-	resetToken, err := h.authService.GeneratePasswordResetToken(r.Context(), input.Email)
-	if err != nil {
+	if _, err := h.authService.GeneratePasswordResetToken(r.Context(), input.Email); err != nil {
 		mapServiceErrorToHTTP(w, r, err)
 		return
 	}
-	if err := h.emailService.SendPasswordResetEmail(input.Email, resetToken); err != nil {
-		fmt.Println("Ошибка отправки email для сброса пароля:", err)
-	}
 	response := map[string]string{"message": "Если email зарегистрирован, ссылка для сброса отправлена"}
 	if err := writeJSON(w, http.StatusOK, response, nil); err != nil {
 		serverErrorResponse(w, r, err)
@@ -172,3 +169,14 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		serverErrorResponse(w, r, err)
 	}
 }
+
+// localeFromAcceptLanguage возвращает первый поддерживаемый язык из заголовка Accept-Language.
+func localeFromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if locale, ok := models.NormalizeLocale(tag); ok {
+			return locale
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarHandler struct {
+	calendarService services.CalendarService
+}
+
+func NewCalendarHandler(calendarService services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// TournamentCalendar godoc
+// @Summary Календарь матчей турнира (iCalendar)
+// @Description Все матчи турнира; ссылку можно добавить в календарь как подписку.
+// @Tags calendar
+// @Produce text/calendar
+// @Param tournamentID path int true "ID турнира"
+// @Success 200 {string} string "Лента .ics"
+// @Failure 404 {object} map[string]string
+// @Router /tournaments/{tournamentID}/calendar.ics [get]
+func (h *CalendarHandler) TournamentCalendar(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := strconv.Atoi(chi.URLParam(r, "tournamentID"))
+	if err != nil || tournamentID <= 0 {
+		badRequestResponse(w, r, errors.New("invalid tournament id"))
+		return
+	}
+
+	feed, err := h.calendarService.TournamentFeed(r.Context(), tournamentID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCalendar(w, fmt.Sprintf("tournament-%d.ics", tournamentID), feed)
+}
+
+// TeamCalendar godoc
+// @Summary Календарь матчей команды (iCalendar)
+// @Tags calendar
+// @Produce text/calendar
+// @Param teamID path int true "ID команды"
+// @Success 200 {string} string "Лента .ics"
+// @Failure 404 {object} map[string]string
+// @Router /teams/{teamID}/calendar.ics [get]
+func (h *CalendarHandler) TeamCalendar(w http.ResponseWriter, r *http.Request) {
+	teamID, err := strconv.Atoi(chi.URLParam(r, "teamID"))
+	if err != nil || teamID <= 0 {
+		badRequestResponse(w, r, errors.New("invalid team id"))
+		return
+	}
+
+	feed, err := h.calendarService.TeamFeed(r.Context(), teamID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCalendar(w, fmt.Sprintf("team-%d.ics", teamID), feed)
+}
+
+// UserCalendar godoc
+// @Summary Персональный календарь матчей (iCalendar)
+// @Description Доступ по секретному токену из приватной ссылки, без авторизации.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Токен ленты"
+// @Success 200 {string} string "Лента .ics"
+// @Failure 404 {object} map[string]string
+// @Router /calendar/{token}.ics [get]
+func (h *CalendarHandler) UserCalendar(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.calendarService.UserFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	// Лента персональная: промежуточные кэши не должны её сохранять
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	writeCalendar(w, "my-matches.ics", feed)
+}
+
+// GetMyCalendarLink godoc
+// @Summary Приватная ссылка на персональный календарь
+// @Description Токен выпускается при первом запросе.
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /calendar/me [get]
+func (h *CalendarHandler) GetMyCalendarLink(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	token, err := h.calendarService.UserFeedToken(r.Context(), currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	h.writeCalendarLink(w, r, token)
+}
+
+// ResetMyCalendarLink godoc
+// @Summary Перевыпустить ссылку на персональный календарь
+// @Description Старая ссылка перестаёт работать.
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /calendar/me/reset [post]
+func (h *CalendarHandler) ResetMyCalendarLink(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	token, err := h.calendarService.ResetUserFeedToken(r.Context(), currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	h.writeCalendarLink(w, r, token)
+}
+
+func (h *CalendarHandler) writeCalendarLink(w http.ResponseWriter, r *http.Request, token string) {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feedURL := fmt.Sprintf("%s://%s/calendar/%s.ics", scheme, r.Host, token)
+	webcalURL := fmt.Sprintf("webcal://%s/calendar/%s.ics", r.Host, token)
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"url": feedURL, "webcal_url": webcalURL}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func writeCalendar(w http.ResponseWriter, filename string, feed []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(feed)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/services"
+)
+
+type CareerHandler struct {
+	careerService services.CareerService
+}
+
+func NewCareerHandler(cs services.CareerService) *CareerHandler {
+	return &CareerHandler{
+		careerService: cs,
+	}
+}
+
+func (h *CareerHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "id")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	stats, err := h.careerService.GetUserStats(r.Context(), userID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"stats": stats}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *CareerHandler) GetTeamStats(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	stats, err := h.careerService.GetTeamStats(r.Context(), teamID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"stats": stats}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// ListUserMatches возвращает матчи игрока: ?sport_id=&limit=&offset=
+func (h *CareerHandler) ListUserMatches(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "id")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	filter, ok := readCareerMatchFilter(w, r)
+	if !ok {
+		return
+	}
+
+	matches, total, err := h.careerService.ListUserMatches(r.Context(), userID, filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCareerPage(w, r, "matches", matches, total, filter.Limit, filter.Offset)
+}
+
+// ListTeamMatches возвращает матчи команды: ?sport_id=&limit=&offset=
+func (h *CareerHandler) ListTeamMatches(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	filter, ok := readCareerMatchFilter(w, r)
+	if !ok {
+		return
+	}
+
+	matches, total, err := h.careerService.ListTeamMatches(r.Context(), teamID, filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCareerPage(w, r, "matches", matches, total, filter.Limit, filter.Offset)
+}
+
+func (h *CareerHandler) ListUserTournaments(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "id")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	limit, offset, ok := readPagination(w, r)
+	if !ok {
+		return
+	}
+
+	tournaments, total, err := h.careerService.ListUserTournaments(r.Context(), userID, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCareerPage(w, r, "tournaments", tournaments, total, limit, offset)
+}
+
+func (h *CareerHandler) ListTeamTournaments(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	limit, offset, ok := readPagination(w, r)
+	if !ok {
+		return
+	}
+
+	tournaments, total, err := h.careerService.ListTeamTournaments(r.Context(), teamID, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	writeCareerPage(w, r, "tournaments", tournaments, total, limit, offset)
+}
+
+func (h *CareerHandler) GetUserHeadToHead(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "id")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	opponentID, err := getIDFromURL(r, "opponentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	limit, offset, ok := readPagination(w, r)
+	if !ok {
+		return
+	}
+
+	h2h, err := h.careerService.GetUserHeadToHead(r.Context(), userID, opponentID, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"head_to_head": h2h}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *CareerHandler) GetTeamHeadToHead(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	opponentID, err := getIDFromURL(r, "opponentTeamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	limit, offset, ok := readPagination(w, r)
+	if !ok {
+		return
+	}
+
+	h2h, err := h.careerService.GetTeamHeadToHead(r.Context(), teamID, opponentID, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"head_to_head": h2h}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func readPagination(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	limit := toInt(query.Get("limit"), 20)
+	offset := toInt(query.Get("offset"), 0)
+	if limit <= 0 || limit > 100 || offset < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit or offset query parameter"))
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+func readCareerMatchFilter(w http.ResponseWriter, r *http.Request) (services.CareerMatchFilter, bool) {
+	var filter services.CareerMatchFilter
+	limit, offset, ok := readPagination(w, r)
+	if !ok {
+		return filter, false
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	if sportIDStr := r.URL.Query().Get("sport_id"); sportIDStr != "" {
+		sportID, err := strconv.Atoi(sportIDStr)
+		if err != nil || sportID <= 0 {
+			badRequestResponse(w, r, errors.New("invalid sport_id query parameter"))
+			return filter, false
+		}
+		filter.SportID = &sportID
+	}
+	return filter, true
+}
+
+func writeCareerPage(w http.ResponseWriter, r *http.Request, key string, items interface{}, total, limit, offset int) {
+	resp := jsonResponse{
+		key:           items,
+		"total_count": total,
+		"limit":       limit,
+		"offset":      offset,
+	}
+	if err := writeJSON(w, http.StatusOK, resp, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
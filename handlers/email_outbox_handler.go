@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type EmailOutboxHandler struct {
+	outboxService services.EmailOutboxService
+}
+
+func NewEmailOutboxHandler(outboxService services.EmailOutboxService) *EmailOutboxHandler {
+	return &EmailOutboxHandler{outboxService: outboxService}
+}
+
+// ListEmails godoc
+// @Summary Очередь исходящих писем
+// @Description Список писем из outbox с фильтром по статусу (pending, sent, dead) и типу.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Статус письма"
+// @Param kind query string false "Тип письма"
+// @Param limit query int false "Лимит (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /admin/emails [get]
+func (h *EmailOutboxHandler) ListEmails(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.OutboxEmailFilter{
+		Limit:  toInt(q.Get("limit"), 20),
+		Offset: toInt(q.Get("offset"), 0),
+	}
+	if status := q.Get("status"); status != "" {
+		s := models.OutboxEmailStatus(status)
+		if !s.IsValid() {
+			badRequestResponse(w, r, errors.New("status must be one of pending, sent, dead"))
+			return
+		}
+		filter.Status = &s
+	}
+	if kind := q.Get("kind"); kind != "" {
+		k := models.OutboxEmailKind(kind)
+		filter.Kind = &k
+	}
+
+	emails, total, err := h.outboxService.List(r.Context(), filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	for _, email := range emails {
+		email.HTMLBody = ""
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"emails": emails, "total": total}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetEmail godoc
+// @Summary Письмо из очереди
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID письма"
+// @Success 200 {object} models.OutboxEmail
+// @Failure 404 {object} map[string]string
+// @Router /admin/emails/{id} [get]
+func (h *EmailOutboxHandler) GetEmail(w http.ResponseWriter, r *http.Request) {
+	id, err := readOutboxEmailID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	email, err := h.outboxService.GetByID(r.Context(), id)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"email": email}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// ResendEmail godoc
+// @Summary Повторная отправка письма
+// @Description Возвращает неотправленное (в том числе dead) письмо в очередь со сброшенным счётчиком попыток.
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID письма"
+// @Success 202 {object} models.OutboxEmail
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Письмо уже отправлено"
+// @Router /admin/emails/{id}/resend [post]
+func (h *EmailOutboxHandler) ResendEmail(w http.ResponseWriter, r *http.Request) {
+	id, err := readOutboxEmailID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	email, err := h.outboxService.Resend(r.Context(), id)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	email.HTMLBody = ""
+
+	if err := writeJSON(w, http.StatusAccepted, jsonResponse{"email": email}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func readOutboxEmailID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid email id")
+	}
+	return id, nil
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json" // Для UpdateFormatInput, если будем использовать json.RawMessage
 	"errors"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/services"
@@ -28,6 +27,7 @@ func NewFormatHandler(fs services.FormatService) *FormatHandler {
 // @Param body body services.CreateFormatInput true "Данные для создания формата (включая name, bracket_type, participant_type, settings_json)"
 // @Success 201 {object} map[string]interface{} "Формат создан"
 // @Failure 400 {object} map[string]string "Ошибка валидации"
+// @Failure 422 {object} map[string]string "Ошибки в settings_json по полям"
 // @Failure 401 {object} map[string]string "Неавторизован"
 // @Failure 403 {object} map[string]string "Нет прав (не админ)"
 // @Failure 409 {object} map[string]string "Конфликт (например, имя уже занято)"
@@ -62,34 +62,7 @@ func (h *FormatHandler) CreateFormat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Для ответа клиенту, можно сразу распарсить settings, если они есть
-	var responseSettings interface{}
-	if format.SettingsJSON != nil && *format.SettingsJSON != "" {
-		if format.BracketType == "RoundRobin" {
-			var rrSettings models.RoundRobinSettings
-			if errJson := json.Unmarshal([]byte(*format.SettingsJSON), &rrSettings); errJson == nil {
-				responseSettings = rrSettings
-			} else {
-				log.Printf("Warning: could not unmarshal RoundRobinSettings for created format %d: %v", format.ID, errJson)
-				// Можно вернуть settings_json как строку или пустой объект
-				_ = json.Unmarshal([]byte(*format.SettingsJSON), &responseSettings) // Попытка как generic map
-			}
-		} else {
-			// Для других типов просто пытаемся анмаршалить в map[string]interface{}
-			_ = json.Unmarshal([]byte(*format.SettingsJSON), &responseSettings)
-		}
-	}
-
-	responsePayload := map[string]interface{}{
-		"id":               format.ID,
-		"name":             format.Name,
-		"bracket_type":     format.BracketType,
-		"participant_type": format.ParticipantType,
-		"settings_json":    format.SettingsJSON, // Можно вернуть и сырой JSON
-		"parsed_settings":  responseSettings,    // И распарсенные настройки
-	}
-
-	if err := writeJSON(w, http.StatusCreated, jsonResponse{"format": responsePayload}, nil); err != nil {
+	if err := writeJSON(w, http.StatusCreated, jsonResponse{"format": formatPayload(format)}, nil); err != nil {
 		serverErrorResponse(w, r, err)
 	}
 }
@@ -117,32 +90,7 @@ func (h *FormatHandler) GetFormatByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var responseSettings interface{}
-	if format.SettingsJSON != nil && *format.SettingsJSON != "" {
-		if format.BracketType == "RoundRobin" {
-			// Используем GetRoundRobinSettings из модели
-			rrSettings, rrErr := format.GetRoundRobinSettings()
-			if rrErr == nil && rrSettings != nil {
-				responseSettings = rrSettings
-			} else if rrErr != nil {
-				log.Printf("Warning: could not parse RoundRobinSettings for format %d on get: %v", format.ID, rrErr)
-				_ = json.Unmarshal([]byte(*format.SettingsJSON), &responseSettings) // Fallback
-			}
-		} else {
-			_ = json.Unmarshal([]byte(*format.SettingsJSON), &responseSettings) // Fallback for other types
-		}
-	}
-
-	responsePayload := map[string]interface{}{
-		"id":               format.ID,
-		"name":             format.Name,
-		"bracket_type":     format.BracketType,
-		"participant_type": format.ParticipantType,
-		"settings_json":    format.SettingsJSON, // Можно вернуть и сырой JSON
-		"parsed_settings":  responseSettings,    // И распарсенные настройки
-	}
-
-	if err := writeJSON(w, http.StatusOK, jsonResponse{"format": responsePayload}, nil); err != nil {
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"format": formatPayload(format)}, nil); err != nil {
 		serverErrorResponse(w, r, err)
 	}
 }
@@ -163,29 +111,8 @@ func (h *FormatHandler) GetAllFormats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	responseFormats := make([]map[string]interface{}, len(formats))
-	for i, f := range formats {
-		var parsedSettings interface{}
-		if f.SettingsJSON != nil && *f.SettingsJSON != "" {
-			if f.BracketType == "RoundRobin" {
-				rrSettings, rrErr := f.GetRoundRobinSettings()
-				if rrErr == nil && rrSettings != nil {
-					parsedSettings = rrSettings
-				} else if rrErr != nil {
-					log.Printf("Warning: could not parse RoundRobinSettings for format %d in list: %v", f.ID, rrErr)
-					_ = json.Unmarshal([]byte(*f.SettingsJSON), &parsedSettings)
-				}
-			} else {
-				_ = json.Unmarshal([]byte(*f.SettingsJSON), &parsedSettings)
-			}
-		}
-		responseFormats[i] = map[string]interface{}{
-			"id":               f.ID,
-			"name":             f.Name,
-			"bracket_type":     f.BracketType,
-			"participant_type": f.ParticipantType,
-			"settings_json":    f.SettingsJSON,
-			"parsed_settings":  parsedSettings,
-		}
+	for i := range formats {
+		responseFormats[i] = formatPayload(&formats[i])
 	}
 
 	if err := writeJSON(w, http.StatusOK, jsonResponse{"formats": responseFormats}, nil); err != nil {
@@ -207,6 +134,7 @@ func (h *FormatHandler) GetAllFormats(w http.ResponseWriter, r *http.Request) {
 // @Failure 403 {object} map[string]string "Нет прав (не админ)"
 // @Failure 404 {object} map[string]string "Формат не найден"
 // @Failure 409 {object} map[string]string "Конфликт (например, имя уже занято)"
+// @Failure 422 {object} map[string]string "Ошибки в settings_json по полям"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Security BearerAuth
 // @Router /formats/{formatID} [put]
@@ -235,30 +163,7 @@ func (h *FormatHandler) UpdateFormat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var responseSettings interface{}
-	if updatedFormat.SettingsJSON != nil && *updatedFormat.SettingsJSON != "" {
-		if updatedFormat.BracketType == "RoundRobin" {
-			rrSettings, rrErr := updatedFormat.GetRoundRobinSettings()
-			if rrErr == nil && rrSettings != nil {
-				responseSettings = rrSettings
-			} else if rrErr != nil {
-				log.Printf("Warning: could not parse RoundRobinSettings for updated format %d: %v", updatedFormat.ID, rrErr)
-				_ = json.Unmarshal([]byte(*updatedFormat.SettingsJSON), &responseSettings)
-			}
-		} else {
-			_ = json.Unmarshal([]byte(*updatedFormat.SettingsJSON), &responseSettings)
-		}
-	}
-	responsePayload := map[string]interface{}{
-		"id":               updatedFormat.ID,
-		"name":             updatedFormat.Name,
-		"bracket_type":     updatedFormat.BracketType,
-		"participant_type": updatedFormat.ParticipantType,
-		"settings_json":    updatedFormat.SettingsJSON,
-		"parsed_settings":  responseSettings,
-	}
-
-	if err := writeJSON(w, http.StatusOK, jsonResponse{"format": responsePayload}, nil); err != nil {
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"format": formatPayload(updatedFormat)}, nil); err != nil {
 		serverErrorResponse(w, r, err)
 	}
 }
@@ -293,3 +198,33 @@ func (h *FormatHandler) DeleteFormat(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ListBracketTypes godoc
+// @Summary Получить типы сеток и схемы их настроек
+// @Tags formats
+// @Description Возвращает поддерживаемые типы сеток и JSON Schema для settings_json каждого из них.
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Список типов сеток"
+// @Router /formats/bracket-types [get]
+func (h *FormatHandler) ListBracketTypes(w http.ResponseWriter, r *http.Request) {
+	types := h.formatService.ListBracketTypes()
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"bracket_types": types}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// formatPayload собирает ответ по формату вместе с настройками, разобранными по схеме.
+func formatPayload(format *models.Format) map[string]interface{} {
+	parsedSettings, err := format.ParsedSettings()
+	if err != nil {
+		log.Printf("Warning: could not parse settings for format %d: %v", format.ID, err)
+	}
+	return map[string]interface{}{
+		"id":               format.ID,
+		"name":             format.Name,
+		"bracket_type":     format.BracketType,
+		"participant_type": format.ParticipantType,
+		"settings_json":    format.SettingsJSON,
+		"parsed_settings":  parsedSettings,
+	}
+}
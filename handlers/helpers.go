@@ -127,6 +127,13 @@ func forbiddenResponse(w http.ResponseWriter, r *http.Request, message string) {
 
 // mapServiceErrorToHTTP преобразует ошибки сервисного слоя в HTTP-ответы
 func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	// Ошибки с разбивкой по полям отдаём как 422
+	var settingsErr *services.FormatSettingsError
+	if errors.As(err, &settingsErr) {
+		failedValidationResponse(w, r, settingsErr.Fields)
+		return
+	}
+
 	// Здесь добавляем маппинг конкретных ошибок сервисов
 	switch {
 	// Общие ошибки
@@ -137,7 +144,20 @@ func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
 		errors.Is(err, services.ErrFormatNotFound),
 		errors.Is(err, services.ErrTournamentNotFound),
 		errors.Is(err, services.ErrParticipantNotFound),
-		errors.Is(err, services.ErrInviteNotFound):
+		errors.Is(err, services.ErrInviteNotFound),
+		errors.Is(err, services.ErrOrganizationNotFound),
+		errors.Is(err, services.ErrOrganizationMemberNotFound),
+		errors.Is(err, services.ErrSeasonNotFound),
+		errors.Is(err, services.ErrSeasonTournamentNotFound),
+		errors.Is(err, services.ErrSoloMatchNotFound),
+		errors.Is(err, services.ErrTeamMatchNotFound),
+		errors.Is(err, services.ErrMatchEventNotFound),
+		errors.Is(err, services.ErrBracketPreviewNotFound),
+		errors.Is(err, services.ErrOutboxEmailNotFound),
+		errors.Is(err, services.ErrNotificationNotFound),
+		errors.Is(err, services.ErrWebhookNotFound),
+		errors.Is(err, services.ErrWebhookDeliveryNotFound),
+		errors.Is(err, services.ErrCalendarTokenNotFound):
 		notFoundResponse(w, r)
 
 	// Конфликты
@@ -145,14 +165,33 @@ func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
 		errors.Is(err, services.ErrUserNicknameConflict),
 		errors.Is(err, services.ErrTeamNameConflict),
 		errors.Is(err, services.ErrTournamentNameConflict),
-		errors.Is(err, services.ErrRegistrationConflict):
+		errors.Is(err, services.ErrRegistrationConflict),
+		errors.Is(err, services.ErrOrganizationNameConflict),
+		errors.Is(err, services.ErrOrganizationMemberConflict),
+		errors.Is(err, services.ErrSeasonNameConflict),
+		errors.Is(err, services.ErrSeasonTournamentConflict),
+		errors.Is(err, services.ErrMatchAlreadyCompleted),
+		errors.Is(err, services.ErrMatchAlreadyStarted),
+		errors.Is(err, services.ErrMatchNotStarted),
+		errors.Is(err, services.ErrMatchScoreFromEvents),
+		errors.Is(err, services.ErrBracketPreviewStale),
+		errors.Is(err, services.ErrBracketPreviewNotAllowed),
+		errors.Is(err, services.ErrBracketEditNotAllowed),
+		errors.Is(err, services.ErrBracketEditMatchPlayed),
+		errors.Is(err, services.ErrOutboxEmailAlreadySent),
+		errors.Is(err, services.ErrTournamentFinalized):
 		conflictResponse(w, r, err.Error())
 
 	// Невалидные данные / бизнес-правила (часто 400 или 422)
 	case errors.Is(err, services.ErrValidationFailed), // Если будет общая ошибка валидации
 		errors.Is(err, services.ErrPasswordTooShort),
+		errors.Is(err, services.ErrInvalidLocale),
+		errors.Is(err, services.ErrInvalidTimezone),
+		errors.Is(err, services.ErrInvalidNotificationType),
 		errors.Is(err, services.ErrInvalidCredentials), // Можно 401, но 400 тоже вариант
 		errors.Is(err, services.ErrTeamNameRequired),
+		errors.Is(err, services.ErrFormatNameRequired),
+		errors.Is(err, services.ErrInvalidBracketType),
 		errors.Is(err, services.ErrTournamentNameRequired),
 		errors.Is(err, services.ErrTournamentInvalidRegDate),
 		errors.Is(err, services.ErrTournamentInvalidDateRange),
@@ -162,7 +201,37 @@ func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
 		errors.Is(err, services.ErrUserCannotRegisterSolo),
 		errors.Is(err, services.ErrUserAlreadyInTeam),
 		errors.Is(err, services.ErrCannotRemoveCaptain),
-		errors.Is(err, services.ErrInviteExpired):
+		errors.Is(err, services.ErrInviteExpired),
+		errors.Is(err, services.ErrOrganizationNameRequired),
+		errors.Is(err, services.ErrInvalidOrganizationRole),
+		errors.Is(err, services.ErrCannotRemoveOrgOwner),
+		errors.Is(err, services.ErrTeamNotInOrganization),
+		errors.Is(err, services.ErrSeasonNameRequired),
+		errors.Is(err, services.ErrSeasonSportMismatch),
+		errors.Is(err, services.ErrSeasonInvalidPointsTable),
+		errors.Is(err, services.ErrRatingSportRequired),
+		errors.Is(err, services.ErrCareerSameOpponent),
+		errors.Is(err, services.ErrMatchNotReady),
+		errors.Is(err, services.ErrInvalidMatchTime),
+		errors.Is(err, services.ErrInvalidWebhookURL),
+		errors.Is(err, services.ErrInvalidWebhookEvent),
+		errors.Is(err, services.ErrScoreParsingFailed),
+		errors.Is(err, services.ErrMatchInvalidWinner),
+		errors.Is(err, services.ErrMatchEventInvalid),
+		errors.Is(err, services.ErrMatchEventTypeNotAllowed),
+		errors.Is(err, services.ErrMatchEventPlayerInvalid),
+		errors.Is(err, services.ErrMatchScoreMismatch),
+		errors.Is(err, services.ErrSportInvalidEventType),
+		errors.Is(err, services.ErrSportScoreFromGoals),
+		errors.Is(err, services.ErrSportInvalidScoring),
+		errors.Is(err, services.ErrBracketPreviewInvalidOrder),
+		errors.Is(err, services.ErrBracketPreviewNotEnoughParticipants),
+		errors.Is(err, services.ErrBracketEditUnsupported),
+		errors.Is(err, services.ErrBracketEditInvalidSlot),
+		errors.Is(err, services.ErrBracketEditSlotFed),
+		errors.Is(err, services.ErrBracketEditInvalidParticipant),
+		errors.Is(err, services.ErrInvalidLogoFormat),
+		errors.Is(err, services.ErrStorageGCInvalidGracePeriod):
 		// Используем StatusBadRequest для большинства бизнес-ошибок, если не указано иное
 		badRequestResponse(w, r, err)
 
@@ -172,7 +241,8 @@ func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
 	case errors.Is(err, services.ErrForbiddenOperation),
 		errors.Is(err, services.ErrCaptainActionForbidden),
 		errors.Is(err, services.ErrSelfLeaveForbidden),
-		errors.Is(err, services.ErrUserMustBeCaptain):
+		errors.Is(err, services.ErrUserMustBeCaptain),
+		errors.Is(err, services.ErrMatchUpdateForbidden):
 		forbiddenResponse(w, r, err.Error())
 
 	case errors.Is(err, services.ErrAuthInvalidCredentials):
@@ -185,6 +255,8 @@ func mapServiceErrorToHTTP(w http.ResponseWriter, r *http.Request, err error) {
 		forbiddenResponse(w, r, err.Error()) // Или 400/409? Зависит от семантики.
 	case errors.Is(err, services.ErrTournamentFull):
 		conflictResponse(w, r, err.Error()) // 409 Conflict - подходящий статус
+	case errors.Is(err, services.ErrLogoTooLarge):
+		errorResponse(w, r, http.StatusRequestEntityTooLarge, err.Error())
 
 	// Непредвиденные ошибки / ошибки по умолчанию
 	default:
@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"errors"
 	"github.com/Dosada05/tournament-system/middleware"
-	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/services"
 	"github.com/go-chi/chi/v5"
 	"net/http"
@@ -12,15 +10,11 @@ import (
 
 type InviteHandler struct {
 	inviteService services.InviteService
-	emailService  *services.EmailService
-	publicURL     string
 }
 
-func NewInviteHandler(is services.InviteService, emailService *services.EmailService, publicURL string) *InviteHandler {
+func NewInviteHandler(is services.InviteService) *InviteHandler {
 	return &InviteHandler{
 		inviteService: is,
-		emailService:  emailService,
-		publicURL:     publicURL,
 	}
 }
 
@@ -161,27 +155,11 @@ func (h *InviteHandler) InviteByEmailHandler(w http.ResponseWriter, r *http.Requ
 		badRequestResponse(w, r, errors.New("email is required"))
 		return
 	}
-	invite, err := h.inviteService.CreateOrRenewInvite(r.Context(), teamID, currentUserID)
-	if err != nil {
+	// Письмо уходит из очереди фоновым воркером
+	if _, err := h.inviteService.InviteByEmail(r.Context(), teamID, currentUserID, input.Email); err != nil {
 		mapServiceErrorToHTTP(w, r, err)
 		return
 	}
-	inviteLink := h.publicURL + "/invites/join/" + invite.Token
-	teamName := "Команда"
-	if h.inviteService != nil {
-		if teamGetter, ok := h.inviteService.(interface {
-			GetTeamByID(ctx context.Context, teamID int) (*models.Team, error)
-		}); ok {
-			team, _ := teamGetter.GetTeamByID(r.Context(), teamID)
-			if team != nil && team.Name != "" {
-				teamName = team.Name
-			}
-		}
-	}
-	if err := h.emailService.SendTeamInviteEmail(input.Email, teamName, inviteLink); err != nil {
-		serverErrorResponse(w, r, err)
-		return
-	}
 	response := map[string]string{"message": "Приглашение отправлено на email"}
 	if err := writeJSON(w, http.StatusOK, response, nil); err != nil {
 		serverErrorResponse(w, r, err)
@@ -1,6 +1,12 @@
 package handlers
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/services"
+)
 
 func (h *TournamentHandler) ListTournamentSoloMatchesHandler(w http.ResponseWriter, r *http.Request) {
 	tournamentID, err := getIDFromURL(r, "tournamentID")
@@ -37,3 +43,77 @@ func (h *TournamentHandler) ListTournamentTeamMatchesHandler(w http.ResponseWrit
 		serverErrorResponse(w, r, err)
 	}
 }
+
+// ListMatchEventsHandler — GET .../matches/{solo|team}/{matchID}/events, хронология матча.
+func (h *TournamentHandler) ListMatchEventsHandler(matchType models.RatingMatchType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tournamentID, err := getIDFromURL(r, "tournamentID")
+		if err != nil {
+			badRequestResponse(w, r, fmt.Errorf("invalid tournament ID: %w", err))
+			return
+		}
+		matchID, err := getIDFromURL(r, "matchID")
+		if err != nil {
+			badRequestResponse(w, r, fmt.Errorf("invalid match ID: %w", err))
+			return
+		}
+
+		events, err := h.matchService.ListMatchEvents(r.Context(), tournamentID, matchType, matchID)
+		if err != nil {
+			mapServiceErrorToHTTP(w, r, err)
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, jsonResponse{"events": events}, nil); err != nil {
+			serverErrorResponse(w, r, err)
+		}
+	}
+}
+
+// AddMatchEventHandler — POST .../matches/{solo|team}/{matchID}/events.
+func (h *TournamentHandler) AddMatchEventHandler(matchType models.RatingMatchType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+		if !ok {
+			return
+		}
+
+		var input services.MatchEventInput
+		if err := readJSON(w, r, &input); err != nil {
+			badRequestResponse(w, r, err)
+			return
+		}
+
+		event, err := h.matchService.AddMatchEvent(r.Context(), tournamentID, matchType, matchID, input, currentUserID)
+		if err != nil {
+			mapServiceErrorToHTTP(w, r, err)
+			return
+		}
+
+		if err := writeJSON(w, http.StatusCreated, jsonResponse{"event": event}, nil); err != nil {
+			serverErrorResponse(w, r, err)
+		}
+	}
+}
+
+// DeleteMatchEventHandler — DELETE .../matches/{solo|team}/{matchID}/events/{eventID}.
+func (h *TournamentHandler) DeleteMatchEventHandler(matchType models.RatingMatchType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+		if !ok {
+			return
+		}
+		eventID, err := getIDFromURL(r, "eventID")
+		if err != nil {
+			badRequestResponse(w, r, fmt.Errorf("invalid event ID: %w", err))
+			return
+		}
+
+		if err := h.matchService.DeleteMatchEvent(r.Context(), tournamentID, matchType, matchID, eventID, currentUserID); err != nil {
+			mapServiceErrorToHTTP(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// ListNotifications godoc
+// @Summary Уведомления текущего пользователя
+// @Description Новые сверху; в ответе также общее число и количество непрочитанных.
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param limit query int false "Лимит (по умолчанию 20, максимум 100)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} models.NotificationList
+// @Failure 401 {object} map[string]string
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := models.NotificationFilter{
+		Limit:  toInt(q.Get("limit"), 20),
+		Offset: toInt(q.Get("offset"), 0),
+	}
+	if unread := q.Get("unread"); unread != "" {
+		filter.UnreadOnly, err = strconv.ParseBool(unread)
+		if err != nil {
+			badRequestResponse(w, r, errors.New("unread must be a boolean"))
+			return
+		}
+	}
+
+	list, err := h.notificationService.List(r.Context(), currentUserID, filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{
+		"notifications": list.Notifications,
+		"total":         list.Total,
+		"unread_count":  list.UnreadCount,
+		"limit":         list.Limit,
+		"offset":        list.Offset,
+	}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// MarkNotificationRead godoc
+// @Summary Отметить уведомление прочитанным
+// @Tags notifications
+// @Produce json
+// @Param id path int true "ID уведомления"
+// @Success 200 {object} models.Notification
+// @Failure 404 {object} map[string]string
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		badRequestResponse(w, r, errors.New("invalid notification id"))
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(r.Context(), currentUserID, id)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"notification": notification}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Отметить все уведомления прочитанными
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(r.Context(), currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"updated": updated}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetNotificationPreferences godoc
+// @Summary Настройки уведомлений
+// @Description Каналы доставки (email, in_app) по каждому типу уведомлений.
+// @Tags notifications
+// @Produce json
+// @Success 200 {array} models.NotificationPreference
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(r.Context(), currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"preferences": prefs}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Изменить настройки уведомлений
+// @Description Передаются только изменяемые типы; остальные остаются как были.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param input body object true "{\"preferences\": [{\"type\": \"match_result\", \"email\": false, \"in_app\": true}]}"
+// @Success 200 {array} models.NotificationPreference
+// @Failure 400 {object} map[string]string
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input struct {
+		Preferences []models.NotificationPreference `json:"preferences"`
+	}
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(r.Context(), currentUserID, input.Preferences)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"preferences": prefs}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/services"
+)
+
+type OrganizationHandler struct {
+	organizationService services.OrganizationService
+}
+
+func NewOrganizationHandler(os services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: os,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to create organization")
+		return
+	}
+
+	var input services.CreateOrganizationInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	org, err := h.organizationService.CreateOrganization(r.Context(), currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusCreated, jsonResponse{"organization": org}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := toInt(query.Get("limit"), 20)
+	offset := toInt(query.Get("offset"), 0)
+	if limit <= 0 || offset < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit or offset query parameter"))
+		return
+	}
+
+	orgs, err := h.organizationService.ListOrganizations(r.Context(), limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"organizations": orgs}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) ListMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	orgs, err := h.organizationService.ListUserOrganizations(r.Context(), currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"organizations": orgs}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetOrganizationProfile возвращает профиль организации: состав, команды, турниры и историю.
+func (h *OrganizationHandler) GetOrganizationProfile(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	profile, err := h.organizationService.GetOrganizationProfile(r.Context(), orgID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"profile": profile}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) ListOrganizationTournaments(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	var status *models.TournamentStatus
+	if statusStr := query.Get("status"); statusStr != "" {
+		s := models.TournamentStatus(statusStr)
+		switch s {
+		case models.StatusSoon, models.StatusRegistration, models.StatusActive, models.StatusCompleted, models.StatusCanceled:
+			status = &s
+		default:
+			badRequestResponse(w, r, fmt.Errorf("invalid status query parameter: %s", statusStr))
+			return
+		}
+	}
+	limit := toInt(query.Get("limit"), 20)
+	offset := toInt(query.Get("offset"), 0)
+	if limit <= 0 || offset < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit or offset query parameter"))
+		return
+	}
+
+	tournaments, err := h.organizationService.ListOrganizationTournaments(r.Context(), orgID, status, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"tournaments": tournaments}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input services.UpdateOrganizationInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	org, err := h.organizationService.UpdateOrganization(r.Context(), orgID, currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"organization": org}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.organizationService.DeleteOrganization(r.Context(), orgID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input services.AddOrganizationMemberInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if input.UserID <= 0 {
+		badRequestResponse(w, r, errors.New("user_id is required"))
+		return
+	}
+
+	member, err := h.organizationService.AddMember(r.Context(), orgID, currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusCreated, jsonResponse{"member": member}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	userID, err := getIDFromURL(r, "userID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input struct {
+		Role models.OrganizationRole `json:"role"`
+	}
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := h.organizationService.UpdateMemberRole(r.Context(), orgID, currentUserID, userID, input.Role); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	userID, err := getIDFromURL(r, "userID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.organizationService.RemoveMember(r.Context(), orgID, currentUserID, userID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) AttachTeam(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	team, err := h.organizationService.AttachTeam(r.Context(), orgID, teamID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"team": team}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *OrganizationHandler) DetachTeam(w http.ResponseWriter, r *http.Request) {
+	orgID, err := getIDFromURL(r, "orgID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.organizationService.DetachTeam(r.Context(), orgID, teamID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/services"
+)
+
+type RatingHandler struct {
+	ratingService services.RatingService
+}
+
+func NewRatingHandler(rs services.RatingService) *RatingHandler {
+	return &RatingHandler{
+		ratingService: rs,
+	}
+}
+
+// GetLeaderboard возвращает рейтинг-лист по виду спорта: ?sport_id=&type=user|team&min_matches=
+func (h *RatingHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	sportID, err := strconv.Atoi(query.Get("sport_id"))
+	if err != nil || sportID <= 0 {
+		badRequestResponse(w, r, errors.New("valid sport_id query parameter is required"))
+		return
+	}
+
+	filter := services.RatingLeaderboardFilter{SportID: sportID}
+	switch query.Get("type") {
+	case "", "user":
+		filter.Teams = false
+	case "team":
+		filter.Teams = true
+	default:
+		badRequestResponse(w, r, errors.New("invalid type query parameter, expected 'user' or 'team'"))
+		return
+	}
+	filter.MinMatches = toInt(query.Get("min_matches"), 0)
+	filter.Limit = toInt(query.Get("limit"), 50)
+	filter.Offset = toInt(query.Get("offset"), 0)
+	if filter.Limit <= 0 || filter.Offset < 0 || filter.MinMatches < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit, offset or min_matches query parameter"))
+		return
+	}
+
+	leaderboard, err := h.ratingService.GetLeaderboard(r.Context(), filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"leaderboard": leaderboard}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *RatingHandler) GetUserRatings(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "userID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	ratings, err := h.ratingService.GetUserRatings(r.Context(), userID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"ratings": ratings}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *RatingHandler) GetTeamRatings(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	ratings, err := h.ratingService.GetTeamRatings(r.Context(), teamID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"ratings": ratings}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *RatingHandler) GetUserRatingHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := getIDFromURL(r, "userID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	h.writeHistory(w, r, &userID, nil)
+}
+
+func (h *RatingHandler) GetTeamRatingHistory(w http.ResponseWriter, r *http.Request) {
+	teamID, err := getIDFromURL(r, "teamID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	h.writeHistory(w, r, nil, &teamID)
+}
+
+func (h *RatingHandler) writeHistory(w http.ResponseWriter, r *http.Request, userID, teamID *int) {
+	query := r.URL.Query()
+	sportID, err := strconv.Atoi(query.Get("sport_id"))
+	if err != nil || sportID <= 0 {
+		badRequestResponse(w, r, errors.New("valid sport_id query parameter is required"))
+		return
+	}
+	limit := toInt(query.Get("limit"), 50)
+	offset := toInt(query.Get("offset"), 0)
+	if limit <= 0 || offset < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit or offset query parameter"))
+		return
+	}
+
+	history, err := h.ratingService.GetRatingHistory(r.Context(), sportID, userID, teamID, limit, offset)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"history": history}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// RebuildRatings пересчитывает рейтинги с нуля (?sport_id= — только для одного вида спорта).
+func (h *RatingHandler) RebuildRatings(w http.ResponseWriter, r *http.Request) {
+	var sportID *int
+	if sportIDStr := r.URL.Query().Get("sport_id"); sportIDStr != "" {
+		id, err := strconv.Atoi(sportIDStr)
+		if err != nil || id <= 0 {
+			badRequestResponse(w, r, errors.New("invalid sport_id query parameter"))
+			return
+		}
+		sportID = &id
+	}
+
+	if err := h.ratingService.RebuildRatings(r.Context(), sportID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"message": "ratings rebuilt"}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
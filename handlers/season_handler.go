@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/services"
+)
+
+type SeasonHandler struct {
+	seasonService services.SeasonService
+}
+
+func NewSeasonHandler(ss services.SeasonService) *SeasonHandler {
+	return &SeasonHandler{
+		seasonService: ss,
+	}
+}
+
+func (h *SeasonHandler) CreateSeason(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to create season")
+		return
+	}
+
+	var input services.CreateSeasonInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if input.SportID <= 0 {
+		badRequestResponse(w, r, errors.New("sport_id is required"))
+		return
+	}
+
+	season, err := h.seasonService.CreateSeason(r.Context(), currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusCreated, jsonResponse{"season": season}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *SeasonHandler) ListSeasons(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var filter services.ListSeasonsFilter
+
+	if sportIDStr := query.Get("sport_id"); sportIDStr != "" {
+		sportID, err := strconv.Atoi(sportIDStr)
+		if err != nil || sportID <= 0 {
+			badRequestResponse(w, r, errors.New("invalid sport_id query parameter"))
+			return
+		}
+		filter.SportID = &sportID
+	}
+	if orgIDStr := query.Get("organization_id"); orgIDStr != "" {
+		orgID, err := strconv.Atoi(orgIDStr)
+		if err != nil || orgID <= 0 {
+			badRequestResponse(w, r, errors.New("invalid organization_id query parameter"))
+			return
+		}
+		filter.OrganizationID = &orgID
+	}
+	filter.Limit = toInt(query.Get("limit"), 20)
+	filter.Offset = toInt(query.Get("offset"), 0)
+	if filter.Limit <= 0 || filter.Offset < 0 {
+		badRequestResponse(w, r, errors.New("invalid limit or offset query parameter"))
+		return
+	}
+
+	seasons, err := h.seasonService.ListSeasons(r.Context(), filter)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"seasons": seasons}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *SeasonHandler) GetSeasonByID(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	season, err := h.seasonService.GetSeasonByID(r.Context(), seasonID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"season": season}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetLeaderboard возвращает сводную таблицу сезона по очкам за места в турнирах.
+func (h *SeasonHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	leaderboard, err := h.seasonService.GetLeaderboard(r.Context(), seasonID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"leaderboard": leaderboard}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *SeasonHandler) UpdateSeason(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input services.UpdateSeasonInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	season, err := h.seasonService.UpdateSeason(r.Context(), seasonID, currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"season": season}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *SeasonHandler) DeleteSeason(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.seasonService.DeleteSeason(r.Context(), seasonID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SeasonHandler) AddTournament(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.seasonService.AddTournament(r.Context(), seasonID, tournamentID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SeasonHandler) RemoveTournament(w http.ResponseWriter, r *http.Request) {
+	seasonID, err := getIDFromURL(r, "seasonID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	if err := h.seasonService.RemoveTournament(r.Context(), seasonID, tournamentID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dosada05/tournament-system/brackets"
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/services"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetryMillis       = 3000
+	sseMaxConnsPerIP     = 5
+)
+
+// SSEHandler — публичный поток событий турнира для клиентов без WebSocket и JWT (виджеты, OBS).
+type SSEHandler struct {
+	hub               *brackets.Hub
+	tournamentService services.TournamentService
+	trustedProxies    []*net.IPNet
+
+	mu        sync.Mutex
+	connsByIP map[string]int
+}
+
+func NewSSEHandler(hub *brackets.Hub, ts services.TournamentService, trustedProxies []*net.IPNet) *SSEHandler {
+	return &SSEHandler{
+		hub:               hub,
+		tournamentService: ts,
+		trustedProxies:    trustedProxies,
+		connsByIP:         make(map[string]int),
+	}
+}
+
+// StreamTournamentEvents — GET /tournaments/{tournamentID}/events (text/event-stream).
+// Переподключение: заголовок Last-Event-ID (его шлёт EventSource) или ?last_event_id=.
+func (h *SSEHandler) StreamTournamentEvents(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if _, err := h.tournamentService.GetTournamentByID(r.Context(), tournamentID, 0); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var lastSeq int64 = -1
+	if lastEventID != "" {
+		lastSeq, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || lastSeq < 0 {
+			badRequestResponse(w, r, errors.New("invalid Last-Event-ID"))
+			return
+		}
+	}
+
+	if _, ok := w.(http.Flusher); !ok {
+		serverErrorResponse(w, r, errors.New("streaming is not supported"))
+		return
+	}
+
+	ip := h.clientIP(r)
+	if !h.acquire(ip) {
+		errorResponse(w, r, http.StatusTooManyRequests, "too many open event streams from this address")
+		return
+	}
+	defer h.release(ip)
+
+	// Поток живёт дольше WriteTimeout сервера
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	_ = rc.Flush()
+
+	client := &brackets.Client{
+		Hub:  h.hub,
+		Send: make(chan []byte, 256),
+		Room: brackets.TournamentTopic(tournamentID),
+		SSE:  true,
+	}
+	resume := lastSeq >= 0 && client.PrepareResume(client.Room)
+	h.hub.Register <- client
+	defer func() { h.hub.Unregister <- client }()
+
+	if resume {
+		client.Resume(client.Room, lastSeq)
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-client.Send:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(msg); err != nil {
+				log.Printf("SSE: write failed for tournament %d: %v", tournamentID, err)
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// Комментарий не виден EventSource, но не даёт прокси закрыть «молчащее» соединение
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *SSEHandler) acquire(ip string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.connsByIP[ip] >= sseMaxConnsPerIP {
+		return false
+	}
+	h.connsByIP[ip]++
+	return true
+}
+
+func (h *SSEHandler) release(ip string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connsByIP[ip]--
+	if h.connsByIP[ip] <= 0 {
+		delete(h.connsByIP, ip)
+	}
+}
+
+// clientIP — адрес клиента для лимита потоков. RemoteAddr уже подменён chi RealIP из заголовков,
+// которые клиент может подставить сам, поэтому берём адрес соединения и верим X-Forwarded-For
+// только от доверенных прокси: идём по цепочке справа налево до первого не-прокси адреса.
+func (h *SSEHandler) clientIP(r *http.Request) string {
+	peer, ok := middleware.PeerAddrFromContext(r.Context())
+	if !ok {
+		peer = r.RemoteAddr
+	}
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !h.trustedProxy(peer) {
+		return peer
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if !h.trustedProxy(hop) {
+			return hop
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return peer
+}
+
+func (h *SSEHandler) trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range h.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dosada05/tournament-system/services"
+)
+
+type StorageHandler struct {
+	gcService   services.StorageGCService
+	gracePeriod time.Duration
+}
+
+func NewStorageHandler(gcService services.StorageGCService, gracePeriod time.Duration) *StorageHandler {
+	return &StorageHandler{gcService: gcService, gracePeriod: gracePeriod}
+}
+
+// CollectGarbage godoc
+// @Summary Сборка мусора в хранилище файлов
+// @Description Ищет загруженные файлы, на которые не ссылается ни один logo_key, и удаляет те,
+// @Description что старше периода ожидания. По умолчанию выполняется пробный прогон (dry_run=true).
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Только отчёт, без удаления (по умолчанию true)"
+// @Param grace_hours query int false "Период ожидания в часах (по умолчанию из конфигурации)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /admin/storage/gc [post]
+func (h *StorageHandler) CollectGarbage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := services.StorageGCOptions{DryRun: true, GracePeriod: h.gracePeriod}
+
+	if dryRunStr := q.Get("dry_run"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			badRequestResponse(w, r, errors.New("invalid dry_run query parameter"))
+			return
+		}
+		opts.DryRun = dryRun
+	}
+	if graceStr := q.Get("grace_hours"); graceStr != "" {
+		hours, err := strconv.Atoi(graceStr)
+		if err != nil || hours < 0 {
+			badRequestResponse(w, r, errors.New("invalid grace_hours query parameter"))
+			return
+		}
+		opts.GracePeriod = time.Duration(hours) * time.Hour
+	}
+
+	report, err := h.gcService.CollectGarbage(r.Context(), opts)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"report": report}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
@@ -98,6 +98,14 @@ func (h *TournamentHandler) ListHandler(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
+	if organizationIDStr := query.Get("organization_id"); organizationIDStr != "" {
+		if id, err := strconv.Atoi(organizationIDStr); err == nil && id > 0 {
+			filter.OrganizationID = &id
+		} else {
+			badRequestResponse(w, r, errors.New("invalid organization_id query parameter"))
+			return
+		}
+	}
 	if statusStr := query.Get("status"); statusStr != "" {
 		validStatuses := map[models.TournamentStatus]bool{
 			models.StatusSoon:         true,
@@ -409,6 +417,152 @@ func (h *TournamentHandler) GetTournamentBracketHandler(w http.ResponseWriter, r
 	}
 }
 
+// readMatchRequest разбирает tournamentID/matchID из URL и текущего пользователя.
+func readMatchRequest(w http.ResponseWriter, r *http.Request) (tournamentID, matchID, currentUserID int, ok bool) {
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, fmt.Errorf("invalid tournament ID: %w", err))
+		return 0, 0, 0, false
+	}
+	matchID, err = getIDFromURL(r, "matchID")
+	if err != nil {
+		badRequestResponse(w, r, fmt.Errorf("invalid match ID: %w", err))
+		return 0, 0, 0, false
+	}
+	currentUserID, err = middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to update match")
+		return 0, 0, 0, false
+	}
+	return tournamentID, matchID, currentUserID, true
+}
+
+func (h *TournamentHandler) StartSoloMatchHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	match, err := h.matchService.StartSoloMatch(r.Context(), matchID, tournamentID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"solo_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *TournamentHandler) StartTeamMatchHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	match, err := h.matchService.StartTeamMatch(r.Context(), matchID, tournamentID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"team_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *TournamentHandler) RescheduleSoloMatchHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input services.RescheduleMatchInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	match, err := h.matchService.RescheduleSoloMatch(r.Context(), matchID, tournamentID, input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"solo_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *TournamentHandler) RescheduleTeamMatchHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input services.RescheduleMatchInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	match, err := h.matchService.RescheduleTeamMatch(r.Context(), matchID, tournamentID, input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"team_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *TournamentHandler) UpdateSoloLiveScoreHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input services.LiveScoreInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	match, err := h.matchService.UpdateSoloLiveScore(r.Context(), matchID, tournamentID, input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"solo_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+func (h *TournamentHandler) UpdateTeamLiveScoreHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, matchID, currentUserID, ok := readMatchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input services.LiveScoreInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	match, err := h.matchService.UpdateTeamLiveScore(r.Context(), matchID, tournamentID, input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"team_match": match}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
 func isValidTournamentStatus(status models.TournamentStatus) bool {
 	switch status {
 	case models.StatusSoon, models.StatusRegistration, models.StatusActive, models.StatusCompleted, models.StatusCanceled:
@@ -416,3 +570,168 @@ func isValidTournamentStatus(status models.TournamentStatus) bool {
 	}
 	return false
 }
+
+// readBracketPreviewRequest читает ID турнира, текущего пользователя и необязательное тело с порядком участников.
+func readBracketPreviewRequest(w http.ResponseWriter, r *http.Request) (int, int, services.BracketPreviewInput, bool) {
+	var input services.BracketPreviewInput
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return 0, 0, input, false
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to manage bracket preview")
+		return 0, 0, input, false
+	}
+	if r.ContentLength != 0 {
+		if err := readJSON(w, r, &input); err != nil {
+			badRequestResponse(w, r, err)
+			return 0, 0, input, false
+		}
+	}
+	return tournamentID, currentUserID, input, true
+}
+
+// PreviewBracketHandler возвращает предлагаемую сетку по текущим подтверждённым участникам, ничего не сохраняя.
+func (h *TournamentHandler) PreviewBracketHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, currentUserID, input, ok := readBracketPreviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := h.tournamentService.PreviewBracket(r.Context(), tournamentID, currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"preview": preview}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// LockBracketPreviewHandler фиксирует раскладку, которая будет использована при активации турнира.
+func (h *TournamentHandler) LockBracketPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, currentUserID, input, ok := readBracketPreviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := h.tournamentService.LockBracketPreview(r.Context(), tournamentID, currentUserID, input)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"preview": preview}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetLockedBracketPreviewHandler возвращает зафиксированную раскладку сетки.
+func (h *TournamentHandler) GetLockedBracketPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to view bracket preview")
+		return
+	}
+
+	preview, err := h.tournamentService.GetLockedBracketPreview(r.Context(), tournamentID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"preview": preview}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// UnlockBracketPreviewHandler снимает фиксацию: при активации сетка снова будет сгенерирована заново.
+func (h *TournamentHandler) UnlockBracketPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to manage bracket preview")
+		return
+	}
+
+	if err := h.tournamentService.UnlockBracketPreview(r.Context(), tournamentID, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readBracketEditRequest читает ID турнира, текущего пользователя и тело операции правки сетки.
+func readBracketEditRequest(w http.ResponseWriter, r *http.Request, input interface{}) (int, int, bool) {
+	tournamentID, err := getIDFromURL(r, "tournamentID")
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return 0, 0, false
+	}
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "authentication required to edit bracket")
+		return 0, 0, false
+	}
+	if err := readJSON(w, r, input); err != nil {
+		badRequestResponse(w, r, err)
+		return 0, 0, false
+	}
+	return tournamentID, currentUserID, true
+}
+
+func writeBracketEditResponse(w http.ResponseWriter, r *http.Request, view *services.FullTournamentBracketView, err error) {
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"bracket": view}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// SwapBracketSlotsHandler меняет местами участников двух слотов несыгранных матчей.
+func (h *TournamentHandler) SwapBracketSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	var input services.SwapBracketSlotsInput
+	tournamentID, currentUserID, ok := readBracketEditRequest(w, r, &input)
+	if !ok {
+		return
+	}
+	view, err := h.tournamentService.SwapBracketSlots(r.Context(), tournamentID, currentUserID, input)
+	writeBracketEditResponse(w, r, view, err)
+}
+
+// ReplaceBracketParticipantHandler ставит в слот запасного участника.
+func (h *TournamentHandler) ReplaceBracketParticipantHandler(w http.ResponseWriter, r *http.Request) {
+	var input services.ReplaceBracketParticipantInput
+	tournamentID, currentUserID, ok := readBracketEditRequest(w, r, &input)
+	if !ok {
+		return
+	}
+	view, err := h.tournamentService.ReplaceBracketParticipant(r.Context(), tournamentID, currentUserID, input)
+	writeBracketEditResponse(w, r, view, err)
+}
+
+// SetBracketByeHandler проводит участника в следующий раунд без игры.
+func (h *TournamentHandler) SetBracketByeHandler(w http.ResponseWriter, r *http.Request) {
+	var input services.SetBracketByeInput
+	tournamentID, currentUserID, ok := readBracketEditRequest(w, r, &input)
+	if !ok {
+		return
+	}
+	view, err := h.tournamentService.SetBracketBye(r.Context(), tournamentID, currentUserID, input)
+	writeBracketEditResponse(w, r, view, err)
+}
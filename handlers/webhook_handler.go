@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dosada05/tournament-system/middleware"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// ListWebhooks godoc
+// @Summary Подписки на вебхуки
+// @Description Личные подписки пользователя или, с organization_id, подписки организации (для владельцев и админов).
+// @Tags webhooks
+// @Produce json
+// @Param organization_id query int false "ID организации"
+// @Success 200 {array} models.Webhook
+// @Failure 403 {object} map[string]string
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var organizationID *int
+	if raw := r.URL.Query().Get("organization_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			badRequestResponse(w, r, errors.New("invalid organization_id"))
+			return
+		}
+		organizationID = &id
+	}
+
+	webhooks, err := h.webhookService.List(r.Context(), organizationID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"webhooks": webhooks}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Создать подписку на вебхуки
+// @Description Пустой список events — все события. Секрет для проверки подписи возвращается только в этом ответе.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param input body services.CreateWebhookInput true "Адрес, события и (необязательно) организация"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return
+	}
+
+	var input services.CreateWebhookInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	webhook, err := h.webhookService.Create(r.Context(), input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusCreated, jsonResponse{"webhook": webhook}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// GetWebhook godoc
+// @Summary Подписка на вебхуки
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} models.Webhook
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUserID, id, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetByID(r.Context(), id, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"webhook": webhook}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateWebhook godoc
+// @Summary Изменить подписку на вебхуки
+// @Description Можно сменить адрес, события, включить/выключить подписку; rotate_secret выдаёт новый секрет.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param input body services.UpdateWebhookInput true "Изменяемые поля"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUserID, id, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input services.UpdateWebhookInput
+	if err := readJSON(w, r, &input); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	webhook, err := h.webhookService.Update(r.Context(), id, input, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"webhook": webhook}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteWebhook godoc
+// @Summary Удалить подписку на вебхуки
+// @Description Журнал доставок подписки удаляется вместе с ней.
+// @Tags webhooks
+// @Param id path int true "ID подписки"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUserID, id, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), id, currentUserID); err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary Журнал доставок вебхука
+// @Description Новые сверху, с фильтром по статусу (pending, delivered, dead).
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param status query string false "Статус доставки"
+// @Param limit query int false "Лимит (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	currentUserID, id, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	filter := models.WebhookDeliveryFilter{
+		Limit:  toInt(q.Get("limit"), 20),
+		Offset: toInt(q.Get("offset"), 0),
+	}
+	if status := q.Get("status"); status != "" {
+		s := models.WebhookDeliveryStatus(status)
+		if !s.IsValid() {
+			badRequestResponse(w, r, errors.New("status must be one of pending, delivered, dead"))
+			return
+		}
+		filter.Status = &s
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(r.Context(), id, filter, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, jsonResponse{"deliveries": deliveries, "total": total}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// RedeliverWebhook godoc
+// @Summary Повторить доставку вебхука
+// @Description Ставит в очередь новую доставку с тем же телом события.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param deliveryID path int true "ID доставки"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUserID, id, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil || deliveryID <= 0 {
+		badRequestResponse(w, r, errors.New("invalid delivery id"))
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), id, deliveryID, currentUserID)
+	if err != nil {
+		mapServiceErrorToHTTP(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusAccepted, jsonResponse{"delivery": delivery}, nil); err != nil {
+		serverErrorResponse(w, r, err)
+	}
+}
+
+// readWebhookRequest извлекает текущего пользователя и ID подписки; при ошибке ответ уже отправлен.
+func readWebhookRequest(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	currentUserID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		unauthorizedResponse(w, r, "failed to identify current user")
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		badRequestResponse(w, r, errors.New("invalid webhook id"))
+		return 0, 0, false
+	}
+	return currentUserID, id, true
+}
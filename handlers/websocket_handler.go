@@ -3,8 +3,10 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/Dosada05/tournament-system/brackets" // Убедись, что путь к пакету brackets правильный
+	"github.com/Dosada05/tournament-system/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/websocket"
 )
@@ -24,68 +26,95 @@ var upgrader = websocket.Upgrader{
 
 type WebSocketHandler struct {
 	hub *brackets.Hub
-	// Здесь могут быть другие зависимости, если нужны, например, TournamentService
-	// для проверки существования турнира перед созданием комнаты.
-	// tournamentService services.TournamentService
 }
 
-func NewWebSocketHandler(hub *brackets.Hub /*, ts services.TournamentService */) *WebSocketHandler {
+func NewWebSocketHandler(hub *brackets.Hub) *WebSocketHandler {
 	return &WebSocketHandler{
 		hub: hub,
-		// tournamentService: ts,
 	}
 }
 
-// ServeWs обрабатывает WebSocket запросы для конкретного турнира.
-// Клиент должен подключаться к /ws/tournaments/{tournamentID}
+// ServeWs — устаревший режим: клиент подключается к /ws/tournaments/{tournamentID}[?last_seq=N]
+// и получает сообщения только этого турнира в формате brackets.WebSocketMessage.
 func (h *WebSocketHandler) ServeWs(w http.ResponseWriter, r *http.Request) {
-	tournamentIDStr := chi.URLParam(r, "tournamentID")
-	if tournamentIDStr == "" {
-		http.Error(w, "Missing tournamentID", http.StatusBadRequest)
+	tournamentID, err := strconv.Atoi(chi.URLParam(r, "tournamentID"))
+	if err != nil || tournamentID <= 0 {
+		http.Error(w, "Invalid tournamentID", http.StatusBadRequest)
 		return
 	}
 
-	// Опционально: Проверить, существует ли турнир с таким ID,
-	// используя tournamentService, прежде чем создавать комнату.
-	// _, err := h.tournamentService.GetTournamentByID(r.Context(), tournamentID, 0) // 0 - если не важен currentUserID
-	// if err != nil {
-	//    log.Printf("Error getting tournament %s for WebSocket: %v", tournamentIDStr, err)
-	//	  // services.ErrTournamentNotFound
-	//    http.NotFound(w, r) // Или другая ошибка в зависимости от типа err
-	//    return
-	// }
-
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("Failed to upgrade connection for tournament %s: %v", tournamentIDStr, err)
+		log.Printf("Failed to upgrade connection for tournament %d: %v", tournamentID, err)
 		// upgrader.Upgrade сам отправляет HTTP ошибку клиенту, так что здесь просто логируем.
 		return
 	}
-	log.Printf("WebSocket connection upgraded for tournament %s", tournamentIDStr)
-
-	// ID комнаты будет соответствовать ID турнира
-	roomID := "tournament_" + tournamentIDStr
 
 	client := &brackets.Client{
 		Hub:  h.hub,
 		Conn: conn,
 		Send: make(chan []byte, 256), // Буферизированный канал
-		Room: roomID,
+		Room: brackets.TournamentTopic(tournamentID),
+	}
+	if userID, err := middleware.GetUserIDFromContext(r.Context()); err == nil {
+		client.UserID = &userID
 	}
+
+	// ?last_seq= — переподключение: дослать события, пропущенные с момента обрыва.
+	// Буфер live-событий включается до Register, который сразу подписывает клиента на Room.
+	var lastSeq int64 = -1
+	if lastSeqStr := r.URL.Query().Get("last_seq"); lastSeqStr != "" {
+		if parsed, parseErr := strconv.ParseInt(lastSeqStr, 10, 64); parseErr == nil && parsed >= 0 {
+			lastSeq = parsed
+		}
+	}
+	resume := lastSeq >= 0 && client.PrepareResume(client.Room)
 	client.Hub.Register <- client
 
-	// Запускаем горутины для чтения и записи в WebSocket соединение для этого клиента.
-	// Эти горутины будут работать, пока клиент не отключится.
 	go client.WritePump()
 	go client.ReadPump()
 
-	log.Printf("Client successfully registered and pumps started for room %s.", roomID)
+	if resume {
+		client.Resume(client.Room, lastSeq)
+	}
+
+	log.Printf("Legacy client registered for tournament %d.", tournamentID)
+}
+
+// ServeWsProtocol обслуживает /ws — протокол с подписками на топики (см. brackets/protocol.go).
+// Токен можно передать заголовком Authorization при апгрейде либо первым кадром {"action":"auth"}.
+func (h *WebSocketHandler) ServeWsProtocol(w http.ResponseWriter, r *http.Request) {
+	var userID *int
+	token, err := middleware.ExtractToken(r)
+	if err != nil {
+		unauthorizedResponse(w, r, err.Error())
+		return
+	}
+	if token != "" {
+		id, authErr := middleware.UserIDFromToken(token)
+		if authErr != nil {
+			unauthorizedResponse(w, r, "invalid or expired token")
+			return
+		}
+		userID = &id
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade WebSocket connection: %v", err)
+		return
+	}
+
+	client := &brackets.Client{
+		Hub:          h.hub,
+		Conn:         conn,
+		Send:         make(chan []byte, 256),
+		UserID:       userID,
+		Authenticate: middleware.UserIDFromToken,
+	}
+	client.Hub.Register <- client
+	client.SendWelcome()
 
-	// После регистрации можно отправить клиенту текущее состояние сетки, если оно уже есть.
-	// Например:
-	// currentBracketState := h.getBracketStateForTournament(tournamentIDStr)
-	// if currentBracketState != nil {
-	//    messageBytes, _ := json.Marshal(currentBracketState) // Предполагается, что currentBracketState можно маршализовать в JSON
-	//    client.Send <- messageBytes
-	// }
+	go client.WritePump()
+	go client.ReadPump()
 }
@@ -0,0 +1,370 @@
+// Package imaging проверяет загруженные логотипы и аватары и готовит из них
+// безопасные для раздачи файлы: определяет настоящий формат по содержимому,
+// отбрасывает метаданные (EXIF) перекодированием и строит уменьшенные варианты.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image is too large")
+	ErrMalformedImage    = errors.New("malformed image")
+)
+
+const (
+	VariantOriginal  = "original"
+	VariantMedium    = "medium"
+	VariantThumbnail = "thumbnail"
+
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+	FormatJPEG = "jpg"
+)
+
+// Variant — уменьшенная копия изображения, вписанная в квадрат Size×Size.
+type Variant struct {
+	Name string
+	Size int
+}
+
+// DefaultVariants — варианты, которые строятся для каждого логотипа в форматах WebP и PNG.
+var DefaultVariants = []Variant{
+	{Name: VariantMedium, Size: 256},
+	{Name: VariantThumbnail, Size: 64},
+}
+
+type Config struct {
+	MaxBytes        int64 // Максимальный размер загружаемого файла
+	MaxDimension    int   // Максимальная ширина/высота исходного изображения
+	OriginalMaxSize int   // Оригинал больше этого размера уменьшается перед сохранением
+	Variants        []Variant
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxBytes:        10 << 20,
+		MaxDimension:    4096,
+		OriginalMaxSize: 1024,
+		Variants:        DefaultVariants,
+	}
+}
+
+// File — готовый к загрузке файл. Name — имя внутри директории логотипа, например "medium.webp".
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type Processor struct {
+	cfg Config
+}
+
+func NewProcessor(cfg Config) *Processor {
+	defaults := DefaultConfig()
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaults.MaxBytes
+	}
+	if cfg.MaxDimension <= 0 {
+		cfg.MaxDimension = defaults.MaxDimension
+	}
+	if cfg.OriginalMaxSize <= 0 {
+		cfg.OriginalMaxSize = defaults.OriginalMaxSize
+	}
+	if cfg.Variants == nil {
+		cfg.Variants = defaults.Variants
+	}
+	return &Processor{cfg: cfg}
+}
+
+// Process читает изображение из r, проверяет его и возвращает оригинал без метаданных
+// (JPEG остаётся JPEG, остальные форматы сохраняются в PNG) и все варианты в WebP и PNG.
+// Оригинал всегда первый в списке.
+func (p *Processor) Process(r io.Reader) ([]File, error) {
+	data, err := io.ReadAll(io.LimitReader(r, p.cfg.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > p.cfg.MaxBytes {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrImageTooLarge, p.cfg.MaxBytes)
+	}
+
+	format, err := sniffFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Размеры проверяем до полного декодирования, чтобы не распаковывать "бомбы".
+	cfg, err := decodeConfig(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrMalformedImage)
+	}
+	if cfg.Width > p.cfg.MaxDimension || cfg.Height > p.cfg.MaxDimension {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height, p.cfg.MaxDimension, p.cfg.MaxDimension)
+	}
+
+	img, err := decode(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedImage, err)
+	}
+	if format == FormatJPEG {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	files := make([]File, 0, 1+2*len(p.cfg.Variants))
+
+	original := fit(img, p.cfg.OriginalMaxSize)
+	if format == FormatJPEG {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, original, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, fmt.Errorf("failed to encode original image: %w", err)
+		}
+		files = append(files, File{Name: VariantOriginal + "." + FormatJPEG, ContentType: "image/jpeg", Data: buf.Bytes()})
+	} else {
+		encoded, err := encodePNG(original)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode original image: %w", err)
+		}
+		files = append(files, File{Name: VariantOriginal + "." + FormatPNG, ContentType: "image/png", Data: encoded})
+	}
+
+	for _, v := range p.cfg.Variants {
+		resized := fit(img, v.Size)
+
+		var webpBuf bytes.Buffer
+		if err := nativewebp.Encode(&webpBuf, resized, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode %s variant as webp: %w", v.Name, err)
+		}
+		files = append(files, File{Name: v.Name + "." + FormatWebP, ContentType: "image/webp", Data: webpBuf.Bytes()})
+
+		pngData, err := encodePNG(resized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s variant as png: %w", v.Name, err)
+		}
+		files = append(files, File{Name: v.Name + "." + FormatPNG, ContentType: "image/png", Data: pngData})
+	}
+
+	return files, nil
+}
+
+// VariantKeys возвращает ключи вариантов логотипа по ключу оригинала, сохранённому в logo_key:
+// variant → format → key. Для логотипов, загруженных до появления вариантов, возвращает nil.
+func VariantKeys(originalKey string, variants []Variant) map[string]map[string]string {
+	if !IsOriginalKey(originalKey) {
+		return nil
+	}
+	dir := path.Dir(originalKey)
+	result := make(map[string]map[string]string, len(variants))
+	for _, v := range variants {
+		result[v.Name] = map[string]string{
+			FormatWebP: dir + "/" + v.Name + "." + FormatWebP,
+			FormatPNG:  dir + "/" + v.Name + "." + FormatPNG,
+		}
+	}
+	return result
+}
+
+// IsOriginalKey сообщает, указывает ли ключ на оригинал в директории с вариантами.
+func IsOriginalKey(key string) bool {
+	return strings.HasPrefix(path.Base(key), VariantOriginal+".") && strings.Contains(key, "/")
+}
+
+func sniffFormat(data []byte) (string, error) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return FormatJPEG, nil
+	case "image/png":
+		return FormatPNG, nil
+	case "image/gif":
+		return "gif", nil
+	case "image/webp":
+		return FormatWebP, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func decodeConfig(format string, data []byte) (image.Config, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case FormatJPEG:
+		return jpeg.DecodeConfig(r)
+	case FormatPNG:
+		return png.DecodeConfig(r)
+	case "gif":
+		return gif.DecodeConfig(r)
+	case FormatWebP:
+		return webp.DecodeConfig(r)
+	}
+	return image.Config{}, ErrUnsupportedFormat
+}
+
+func decode(format string, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case FormatJPEG:
+		return jpeg.Decode(r)
+	case FormatPNG:
+		return png.Decode(r)
+	case "gif":
+		// Анимация не сохраняется — берётся первый кадр.
+		return gif.Decode(r)
+	case FormatWebP:
+		return webp.Decode(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fit уменьшает изображение, чтобы оно вписывалось в квадрат size×size, сохраняя пропорции.
+// Изображения меньше size не увеличиваются, но всё равно копируются в NRGBA.
+func fit(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			h = max(1, h*size/w)
+			w = size
+		} else {
+			w = max(1, w*size/h)
+			h = size
+		}
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+		return dst
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// jpegOrientation читает тег Orientation (0x0112) из EXIF сегмента APP1.
+// Возвращает 1 (без поворота), если тега нет или он повреждён.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // Начало данных изображения
+			return 1
+		}
+		segLen := int(data[pos+2])<<8 | int(data[pos+3])
+		if segLen < 2 || pos+2+segLen > len(data) {
+			return 1
+		}
+		seg := data[pos+4 : pos+2+segLen]
+		if marker == 0xE1 && len(seg) > 14 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		pos += 2 + segLen
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var u16 func([]byte) int
+	var u32 func([]byte) int
+	switch string(tiff[:2]) {
+	case "II":
+		u16 = func(b []byte) int { return int(b[0]) | int(b[1])<<8 }
+		u32 = func(b []byte) int { return int(b[0]) | int(b[1])<<8 | int(b[2])<<16 | int(b[3])<<24 }
+	case "MM":
+		u16 = func(b []byte) int { return int(b[0])<<8 | int(b[1]) }
+		u32 = func(b []byte) int { return int(b[0])<<24 | int(b[1])<<16 | int(b[2])<<8 | int(b[3]) }
+	default:
+		return 1
+	}
+	ifd := u32(tiff[4:8])
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := u16(tiff[ifd : ifd+2])
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if u16(tiff[entry:entry+2]) == 0x0112 {
+			if o := u16(tiff[entry+8 : entry+10]); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation поворачивает/отражает изображение согласно EXIF Orientation,
+// так как после удаления метаданных браузер уже не сможет сделать это сам.
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, color.NRGBAModel.Convert(src.At(b.Min.X+x, b.Min.Y+y)))
+		}
+	}
+	return dst
+}
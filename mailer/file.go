@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileTransport struct {
+	dir string
+}
+
+// NewFileTransport сохраняет письма файлами .eml в dir — для локальной разработки,
+// их можно открыть любым почтовым клиентом.
+func NewFileTransport(dir string) (Transport, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("invalid file mail transport configuration: directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory %s: %w", dir, err)
+	}
+	return &fileTransport{dir: dir}, nil
+}
+
+func (t *fileTransport) Send(ctx context.Context, msg *Message) error {
+	if _, err := msg.Recipients(); err != nil {
+		return err
+	}
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	path := filepath.Join(t.dir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write email to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTransport хранит отправленные письма в памяти — для тестов.
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (t *MemoryTransport) Send(ctx context.Context, msg *Message) error {
+	if _, err := msg.Recipients(); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	stored := *msg
+	stored.To = append([]string(nil), msg.To...)
+	t.messages = append(t.messages, stored)
+	return nil
+}
+
+// Messages возвращает копию отправленных писем в порядке отправки.
+func (t *MemoryTransport) Messages() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := make([]Message, len(t.messages))
+	copy(result, t.messages)
+	return result
+}
+
+// FailWith заставляет транспорт возвращать err на каждую отправку; nil отключает сбои.
+func (t *MemoryTransport) FailWith(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+}
+
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = nil
+	t.err = nil
+}
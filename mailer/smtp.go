@@ -0,0 +1,147 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Режимы шифрования SMTP-соединения.
+const (
+	SMTPTLSStartTLS = "starttls" // Обычное соединение с обязательным STARTTLS (порт 587)
+	SMTPTLSImplicit = "tls"      // TLS с первого байта (порт 465)
+	SMTPTLSNone     = "none"     // Без шифрования — только для локальных тестовых серверов
+)
+
+const defaultSMTPTimeout = 30 * time.Second
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLSMode  string
+	Timeout  time.Duration
+}
+
+type smtpTransport struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPTransport(cfg SMTPConfig) (Transport, error) {
+	if cfg.Host == "" || cfg.Port <= 0 {
+		return nil, errors.New("invalid SMTP configuration: host and port are required")
+	}
+	switch cfg.TLSMode {
+	case "":
+		cfg.TLSMode = SMTPTLSStartTLS
+		if cfg.Port == 465 {
+			cfg.TLSMode = SMTPTLSImplicit
+		}
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+	default:
+		return nil, fmt.Errorf("invalid SMTP TLS mode %q", cfg.TLSMode)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSMTPTimeout
+	}
+	return &smtpTransport{cfg: cfg}, nil
+}
+
+func (t *smtpTransport) Send(ctx context.Context, msg *Message) error {
+	recipients, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := parseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	client, err := t.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if t.cfg.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP server does not support AUTH")
+		}
+		auth := smtp.PlainAuth("", t.cfg.Username, t.cfg.Password, t.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected email: %w", err)
+	}
+	return client.Quit()
+}
+
+func (t *smtpTransport) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(t.cfg.Host, strconv.Itoa(t.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: t.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	dialer := &net.Dialer{Timeout: t.cfg.Timeout}
+	var conn net.Conn
+	var err error
+	if t.cfg.TLSMode == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(t.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+
+	if t.cfg.TLSMode == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,178 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoRecipients   = errors.New("email has no recipients")
+	ErrInvalidAddress = errors.New("invalid email address")
+)
+
+// Message — письмо, готовое к отправке любым транспортом.
+type Message struct {
+	From     string
+	To       []string
+	ReplyTo  string
+	Subject  string
+	HTMLBody string
+	TextBody string // Если пусто, строится из HTMLBody
+}
+
+// Transport доставляет письма: по SMTP, в файлы .eml или в память.
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Recipients возвращает адреса получателей без отображаемых имён — в таком виде они уходят в RCPT TO.
+func (m *Message) Recipients() ([]string, error) {
+	if len(m.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+	addrs := make([]string, 0, len(m.To))
+	for _, to := range m.To {
+		addr, err := parseAddress(to)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr.Address)
+	}
+	return addrs, nil
+}
+
+// Bytes собирает письмо в формате RFC 5322: multipart/alternative с текстовой и HTML-частями.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := parseAddress(m.From)
+	if err != nil {
+		return nil, err
+	}
+	to := make([]string, 0, len(m.To))
+	for _, raw := range m.To {
+		addr, err := parseAddress(raw)
+		if err != nil {
+			return nil, err
+		}
+		to = append(to, addr.String())
+	}
+	if len(to) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	var buf bytes.Buffer
+	writeHeader := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	writeHeader("From", from.String())
+	writeHeader("To", strings.Join(to, ", "))
+	if m.ReplyTo != "" {
+		replyTo, err := parseAddress(m.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		writeHeader("Reply-To", replyTo.String())
+	}
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", stripLineBreaks(m.Subject)))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("Message-ID", newMessageID(from.Address))
+	writeHeader("MIME-Version", "1.0")
+
+	mw := multipart.NewWriter(&buf)
+	writeHeader("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+
+	textBody := m.TextBody
+	if textBody == "" {
+		textBody = HTMLToText(m.HTMLBody)
+	}
+	if err := writePart(mw, "text/plain; charset=utf-8", textBody); err != nil {
+		return nil, err
+	}
+	if m.HTMLBody != "" {
+		if err := writePart(mw, "text/html; charset=utf-8", m.HTMLBody); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize email body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s part: %w", contentType, err)
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to write %s part: %w", contentType, err)
+	}
+	return qp.Close()
+}
+
+func parseAddress(raw string) (*mail.Address, error) {
+	if strings.ContainsAny(raw, "\r\n") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, raw)
+	}
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, raw)
+	}
+	return addr, nil
+}
+
+func stripLineBreaks(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+func newMessageID(fromAddress string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
+}
+
+var (
+	htmlLinkRe      = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']+)["'][^>]*>(.*?)</a>`)
+	htmlBlockRe     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr|table)>`)
+	htmlDropRe      = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	htmlTagRe       = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesRe    = regexp.MustCompile(`\n{3,}`)
+	inlineSpacingRe = regexp.MustCompile(`[ \t]+`)
+)
+
+// HTMLToText делает упрощённую текстовую версию HTML-письма: ссылки сохраняются как «текст (url)».
+func HTMLToText(htmlBody string) string {
+	text := htmlDropRe.ReplaceAllString(htmlBody, "")
+	text = htmlLinkRe.ReplaceAllString(text, "$2 ($1)")
+	text = htmlBlockRe.ReplaceAllString(text, "\n")
+	text = htmlTagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(inlineSpacingRe.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(text, "\n\n"))
+}
@@ -36,13 +36,7 @@ func Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		parsedToken, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				log.Printf("Unexpected signing method: %v", t.Header["alg"])
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return utils.GetJWTSecret(), nil
-		})
+		parsedToken, err := jwt.Parse(tokenString, jwtKeyFunc)
 
 		if err != nil {
 			log.Printf("Token parsing/validation error: %v", err)
@@ -113,6 +107,40 @@ func Authorize(roles ...models.UserRole) func(http.Handler) http.Handler {
 	}
 }
 
+func jwtKeyFunc(t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		log.Printf("Unexpected signing method: %v", t.Header["alg"])
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return utils.GetJWTSecret(), nil
+}
+
+// UserIDFromToken проверяет JWT вне HTTP-запроса (например, кадр auth в WebSocket) и возвращает ID пользователя.
+func UserIDFromToken(tokenString string) (int, error) {
+	if tokenString == "" {
+		return 0, errors.New("no token provided")
+	}
+	parsedToken, err := jwt.Parse(tokenString, jwtKeyFunc)
+	if err != nil {
+		return 0, err
+	}
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return 0, errors.New("invalid token")
+	}
+
+	ctx := context.WithValue(context.Background(), userContextKey, claims)
+	if _, err := GetUserRoleFromContext(ctx); err != nil {
+		return 0, err
+	}
+	return GetUserIDFromContext(ctx)
+}
+
+// ExtractToken возвращает токен из заголовка Authorization (пустая строка, если заголовка нет).
+func ExtractToken(r *http.Request) (string, error) {
+	return extractToken(r)
+}
+
 func extractToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
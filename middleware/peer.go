@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+const peerAddrContextKey contextKey = "peer_addr"
+
+// PeerAddr сохраняет адрес TCP-соединения до того, как chi RealIP подменит RemoteAddr
+// значением из заголовков. Подключается перед RealIP.
+func PeerAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), peerAddrContextKey, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PeerAddrFromContext возвращает адрес соединения, сохранённый PeerAddr.
+func PeerAddrFromContext(ctx context.Context) (string, bool) {
+	addr, ok := ctx.Value(peerAddrContextKey).(string)
+	return addr, ok
+}
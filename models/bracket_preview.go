@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// BracketPreviewMatch — матч предлагаемой сетки до сохранения в БД.
+type BracketPreviewMatch struct {
+	UID              string  `json:"uid"`
+	Round            int     `json:"round"`
+	OrderInRound     int     `json:"order_in_round"`
+	Participant1ID   *int    `json:"participant1_id,omitempty"`
+	Participant2ID   *int    `json:"participant2_id,omitempty"`
+	SourceMatch1UID  *string `json:"source_match1_uid,omitempty"`
+	SourceMatch2UID  *string `json:"source_match2_uid,omitempty"`
+	IsBye            bool    `json:"is_bye"`
+	ByeParticipantID *int    `json:"bye_participant_id,omitempty"`
+}
+
+// BracketPreview — результат пробной генерации сетки. ParticipantIDs задаёт порядок,
+// в котором участники были переданы генератору; по нему предпросмотр воспроизводится при фиксации.
+// FormatSettings — настройки формата (со значениями по умолчанию), с которыми построена сетка.
+type BracketPreview struct {
+	TournamentID   int                    `json:"tournament_id" db:"tournament_id"`
+	BracketType    string                 `json:"bracket_type" db:"bracket_type"`
+	FormatSettings map[string]interface{} `json:"format_settings" db:"format_settings"`
+	ParticipantIDs []int                  `json:"participant_ids" db:"participant_ids"`
+	Seeded         bool                   `json:"seeded" db:"seeded"`
+	Matches        []BracketPreviewMatch  `json:"matches" db:"matches"`
+	Locked         bool                   `json:"locked" db:"-"`
+	LockedBy       *int                   `json:"locked_by,omitempty" db:"locked_by"`
+	LockedAt       *time.Time             `json:"locked_at,omitempty" db:"locked_at"`
+
+	Participants []Participant `json:"participants,omitempty" db:"-"`
+}
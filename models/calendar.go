@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CalendarMatch — матч для iCalendar-ленты; имена сторон пустые, пока слот не заполнен.
+type CalendarMatch struct {
+	MatchType        RatingMatchType  `json:"match_type" db:"match_type"`
+	MatchID          int              `json:"match_id" db:"match_id"`
+	TournamentID     int              `json:"tournament_id" db:"tournament_id"`
+	TournamentName   string           `json:"tournament_name" db:"tournament_name"`
+	TournamentStatus TournamentStatus `json:"tournament_status" db:"tournament_status"`
+	Location         *string          `json:"location,omitempty" db:"location"`
+	Round            *int             `json:"round,omitempty" db:"round"`
+	MatchTime        time.Time        `json:"match_time" db:"match_time"`
+	Status           MatchStatus      `json:"status" db:"status"`
+	Score            *string          `json:"score,omitempty" db:"score"`
+	Side1Name        *string          `json:"side1_name,omitempty" db:"side1_name"`
+	Side2Name        *string          `json:"side2_name,omitempty" db:"side2_name"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+type MatchResult string
+
+const (
+	MatchResultWin  MatchResult = "win"
+	MatchResultLoss MatchResult = "loss"
+	MatchResultDraw MatchResult = "draw"
+)
+
+// CareerSportStats — сводная статистика игрока или команды по одному виду спорта.
+type CareerSportStats struct {
+	SportID           int    `json:"sport_id"`
+	SportName         string `json:"sport_name"`
+	MatchesPlayed     int    `json:"matches_played"`
+	Wins              int    `json:"wins"`
+	Losses            int    `json:"losses"`
+	Draws             int    `json:"draws"`
+	TournamentsPlayed int    `json:"tournaments_played"`
+	TournamentWins    int    `json:"tournament_wins"`
+	Podiums           int    `json:"podiums"`
+	BestPlacement     *int   `json:"best_placement,omitempty"`
+}
+
+// CareerMatch — матч с точки зрения игрока или команды.
+type CareerMatch struct {
+	MatchID        int         `json:"match_id"`
+	TournamentID   int         `json:"tournament_id"`
+	TournamentName string      `json:"tournament_name"`
+	SportID        int         `json:"sport_id"`
+	Round          *int        `json:"round,omitempty"`
+	MatchTime      time.Time   `json:"match_time"`
+	Status         MatchStatus `json:"status"`
+	Score          *string     `json:"score,omitempty"` // Как в матче: счёт P1-P2
+	ScoreFor       *int        `json:"score_for,omitempty"`
+	ScoreAgainst   *int        `json:"score_against,omitempty"`
+	Result         MatchResult `json:"result,omitempty"`
+	ParticipantID  int         `json:"participant_id"`
+	IsFirstSlot    bool        `json:"is_first_slot"`
+
+	WinnerParticipantID   *int   `json:"winner_participant_id,omitempty"`
+	OpponentParticipantID *int   `json:"opponent_participant_id,omitempty"`
+	OpponentUserID        *int   `json:"opponent_user_id,omitempty"`
+	OpponentTeamID        *int   `json:"opponent_team_id,omitempty"`
+	OpponentName          string `json:"opponent_name,omitempty"`
+}
+
+// CareerTournament — участие в турнире и итоговое место (если турнир завершён).
+type CareerTournament struct {
+	TournamentID  int              `json:"tournament_id"`
+	Name          string           `json:"name"`
+	SportID       int              `json:"sport_id"`
+	Status        TournamentStatus `json:"status"`
+	StartDate     time.Time        `json:"start_date"`
+	EndDate       time.Time        `json:"end_date"`
+	ParticipantID int              `json:"participant_id"`
+	Placement     *int             `json:"placement,omitempty"`
+	LogoKey       *string          `json:"-"`
+	LogoURL       *string          `json:"logo_url,omitempty"`
+	LogoURLs      *LogoURLs        `json:"logo_urls,omitempty"`
+}
+
+type HeadToHeadRecord struct {
+	OpponentID    int           `json:"opponent_id"`
+	MatchesPlayed int           `json:"matches_played"`
+	Wins          int           `json:"wins"`
+	Losses        int           `json:"losses"`
+	Draws         int           `json:"draws"`
+	Matches       []CareerMatch `json:"matches"`
+	TotalMatches  int           `json:"total_matches"`
+}
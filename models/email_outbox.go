@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+type OutboxEmailStatus string
+
+const (
+	OutboxEmailPending OutboxEmailStatus = "pending"
+	OutboxEmailSent    OutboxEmailStatus = "sent"
+	OutboxEmailDead    OutboxEmailStatus = "dead" // Исчерпаны попытки отправки
+)
+
+func (s OutboxEmailStatus) IsValid() bool {
+	switch s {
+	case OutboxEmailPending, OutboxEmailSent, OutboxEmailDead:
+		return true
+	}
+	return false
+}
+
+type OutboxEmailKind string
+
+const (
+	EmailKindWelcome          OutboxEmailKind = "welcome"
+	EmailKindPasswordReset    OutboxEmailKind = "password_reset"
+	EmailKindTeamInvite       OutboxEmailKind = "team_invite"
+	EmailKindTournamentStatus OutboxEmailKind = "tournament_status"
+	EmailKindNotification     OutboxEmailKind = "notification" // Копия уведомления из центра уведомлений
+)
+
+// OutboxEmail — письмо в очереди на отправку.
+type OutboxEmail struct {
+	ID            int64             `json:"id" db:"id"`
+	Kind          OutboxEmailKind   `json:"kind" db:"kind"`
+	Recipients    []string          `json:"recipients" db:"recipients"`
+	Subject       string            `json:"subject" db:"subject"`
+	HTMLBody      string            `json:"html_body,omitempty" db:"html_body"`
+	Status        OutboxEmailStatus `json:"status" db:"status"`
+	Attempts      int               `json:"attempts" db:"attempts"`
+	MaxAttempts   int               `json:"max_attempts" db:"max_attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string           `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+	SentAt        *time.Time        `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+type OutboxEmailFilter struct {
+	Status *OutboxEmailStatus
+	Kind   *OutboxEmailKind
+	Limit  int
+	Offset int
+}
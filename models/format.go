@@ -1,6 +1,9 @@
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 type FormatParticipantType string
 
@@ -23,17 +26,43 @@ type Format struct {
 	ParsedRoundRobinSettings *RoundRobinSettings `json:"parsed_round_robin_settings,omitempty" db:"-"`
 }
 
+// ParsedSettings возвращает настройки формата, проверенные по схеме типа сетки,
+// с подставленными значениями по умолчанию.
+func (f *Format) ParsedSettings() (map[string]interface{}, error) {
+	schema, ok := SettingsSchemaFor(f.BracketType)
+	if !ok {
+		return nil, fmt.Errorf("unknown bracket type %q", f.BracketType)
+	}
+	var raw []byte
+	if f.SettingsJSON != nil {
+		raw = []byte(*f.SettingsJSON)
+	}
+	settings, fieldErrors := schema.Validate(raw)
+	if len(fieldErrors) > 0 {
+		return nil, fmt.Errorf("invalid %s settings: %v", f.BracketType, fieldErrors)
+	}
+	return settings, nil
+}
+
+func (f *Format) decodeSettings(dst interface{}) error {
+	settings, err := f.ParsedSettings()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
 func (f *Format) GetRoundRobinSettings() (*RoundRobinSettings, error) {
-	if f.BracketType != "RoundRobin" || f.SettingsJSON == nil || *f.SettingsJSON == "" {
+	if f.BracketType != BracketTypeRoundRobin {
 		return nil, nil
 	}
 	var settings RoundRobinSettings
-	err := json.Unmarshal([]byte(*f.SettingsJSON), &settings)
-	if err != nil {
+	if err := f.decodeSettings(&settings); err != nil {
 		return nil, err
 	}
-	if settings.NumberOfRounds < 1 || settings.NumberOfRounds > 2 {
-		settings.NumberOfRounds = 1
-	}
 	return &settings, nil
 }
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+const (
+	BracketTypeSingleElimination = "SingleElimination"
+	BracketTypeRoundRobin        = "RoundRobin"
+)
+
+// SettingsSchemaURI — диалект JSON Schema, подмножество которого описывает настройки форматов.
+const SettingsSchemaURI = "http://json-schema.org/draft-07/schema#"
+
+// SettingsProperty описывает одно поле настроек в терминах JSON Schema.
+type SettingsProperty struct {
+	Type        string        `json:"type"` // integer, boolean
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Minimum     *int          `json:"minimum,omitempty"`
+	Maximum     *int          `json:"maximum,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// SettingsSchema — JSON Schema объекта settings_json для одного типа сетки.
+type SettingsSchema struct {
+	Schema               string                       `json:"$schema"`
+	Type                 string                       `json:"type"`
+	Title                string                       `json:"title,omitempty"`
+	Properties           map[string]*SettingsProperty `json:"properties"`
+	Required             []string                     `json:"required,omitempty"`
+	AdditionalProperties bool                         `json:"additionalProperties"`
+}
+
+// BracketTypeInfo — тип сетки и схема его настроек, отдаётся админке для построения форм.
+type BracketTypeInfo struct {
+	Type        string          `json:"type"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Schema      *SettingsSchema `json:"settings_schema"`
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+var bracketTypes = []BracketTypeInfo{
+	{
+		Type:        BracketTypeSingleElimination,
+		Title:       "Олимпийская система",
+		Description: "Проигравший выбывает, победитель проходит в следующий раунд.",
+		Schema: &SettingsSchema{
+			Schema: SettingsSchemaURI,
+			Type:   "object",
+			Title:  "SingleElimination settings",
+			// Настроек пока нет: матч за третье место и серии генератор не поддерживает.
+			Properties: map[string]*SettingsProperty{},
+		},
+	},
+	{
+		Type:        BracketTypeRoundRobin,
+		Title:       "Круговая система",
+		Description: "Каждый участник играет с каждым.",
+		Schema: &SettingsSchema{
+			Schema: SettingsSchemaURI,
+			Type:   "object",
+			Title:  "RoundRobin settings",
+			Properties: map[string]*SettingsProperty{
+				"number_of_rounds": {
+					Type:        "integer",
+					Title:       "Количество кругов",
+					Description: "1 — одна встреча на пару, 2 — дома и в гостях.",
+					Default:     1,
+					Minimum:     intPtr(1),
+					Maximum:     intPtr(2),
+				},
+			},
+		},
+	},
+}
+
+// BracketTypes возвращает поддерживаемые типы сеток вместе со схемами настроек.
+func BracketTypes() []BracketTypeInfo {
+	result := make([]BracketTypeInfo, len(bracketTypes))
+	copy(result, bracketTypes)
+	return result
+}
+
+// SettingsSchemaFor возвращает схему настроек для типа сетки.
+func SettingsSchemaFor(bracketType string) (*SettingsSchema, bool) {
+	for _, bt := range bracketTypes {
+		if bt.Type == bracketType {
+			return bt.Schema, true
+		}
+	}
+	return nil, false
+}
+
+func IsValidBracketType(bracketType string) bool {
+	_, ok := SettingsSchemaFor(bracketType)
+	return ok
+}
+
+// Validate проверяет settings_json по схеме и возвращает настройки с подставленными
+// значениями по умолчанию. Ошибки возвращаются по полям.
+func (s *SettingsSchema) Validate(raw []byte) (map[string]interface{}, map[string]string) {
+	fieldErrors := make(map[string]string)
+	values := make(map[string]interface{})
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && string(trimmed) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil || values == nil {
+			fieldErrors["settings_json"] = "must be a JSON object"
+			return nil, fieldErrors
+		}
+	}
+
+	for key := range values {
+		if _, ok := s.Properties[key]; !ok && !s.AdditionalProperties {
+			fieldErrors[key] = "unknown setting"
+		}
+	}
+
+	result := make(map[string]interface{}, len(s.Properties))
+	for name, prop := range s.Properties {
+		value, present := values[name]
+		if !present || value == nil {
+			if s.isRequired(name) {
+				fieldErrors[name] = "is required"
+			} else if prop.Default != nil {
+				result[name] = prop.Default
+			}
+			continue
+		}
+		normalized, msg := prop.check(value)
+		if msg != "" {
+			fieldErrors[name] = msg
+			continue
+		}
+		result[name] = normalized
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors
+	}
+	return result, nil
+}
+
+func (s *SettingsSchema) isRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *SettingsProperty) check(value interface{}) (interface{}, string) {
+	switch p.Type {
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, "must be a boolean"
+		}
+		return b, ""
+	case "integer":
+		num, ok := value.(json.Number)
+		if !ok {
+			return nil, "must be an integer"
+		}
+		f, err := num.Float64()
+		if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+			return nil, "must be an integer"
+		}
+		n := int(f)
+		if p.Minimum != nil && n < *p.Minimum {
+			return nil, fmt.Sprintf("must be at least %d", *p.Minimum)
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			return nil, fmt.Sprintf("must be at most %d", *p.Maximum)
+		}
+		if len(p.Enum) > 0 && !enumContains(p.Enum, n) {
+			return nil, fmt.Sprintf("must be one of %v", p.Enum)
+		}
+		return n, ""
+	default:
+		return nil, fmt.Sprintf("unsupported schema type %q", p.Type)
+	}
+}
+
+func enumContains(enum []interface{}, n int) bool {
+	for _, v := range enum {
+		if i, ok := v.(int); ok && i == n {
+			return true
+		}
+	}
+	return false
+}
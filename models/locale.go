@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	LocaleRussian = "ru"
+	LocaleEnglish = "en"
+
+	DefaultLocale   = LocaleRussian
+	DefaultTimezone = "UTC"
+)
+
+var supportedLocales = []string{LocaleRussian, LocaleEnglish}
+
+func SupportedLocales() []string {
+	result := make([]string, len(supportedLocales))
+	copy(result, supportedLocales)
+	return result
+}
+
+// NormalizeLocale приводит тег вида "en-US" или "EN_gb" к поддерживаемой локали.
+// Второе значение false, если язык не поддерживается.
+func NormalizeLocale(tag string) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	for _, l := range supportedLocales {
+		if l == lang {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// IsValidTimezone проверяет имя часового пояса из базы IANA (например, Europe/Moscow).
+func IsValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
@@ -0,0 +1,14 @@
+package models
+
+// LogoURLs — публичные ссылки на все варианты логотипа или аватара.
+// Medium и Thumbnail отсутствуют у логотипов, загруженных до появления вариантов.
+type LogoURLs struct {
+	Original  string            `json:"original"`
+	Medium    *ImageVariantURLs `json:"medium,omitempty"`
+	Thumbnail *ImageVariantURLs `json:"thumbnail,omitempty"`
+}
+
+type ImageVariantURLs struct {
+	WebP string `json:"webp"`
+	PNG  string `json:"png"`
+}
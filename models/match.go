@@ -31,6 +31,8 @@ type SoloMatch struct {
 	P1         *Participant `json:"p1,omitempty" db:"-"`
 	P2         *Participant `json:"p2,omitempty" db:"-"`
 	Winner     *Participant `json:"winner,omitempty" db:"-"`
+
+	Events []MatchEvent `json:"events,omitempty" db:"-"` // Хронология матча
 }
 
 type TeamMatch struct {
@@ -53,4 +55,6 @@ type TeamMatch struct {
 	T1         *Participant `json:"t1,omitempty" db:"-"`
 	T2         *Participant `json:"t2,omitempty" db:"-"`
 	Winner     *Participant `json:"winner,omitempty" db:"-"`
+
+	Events []MatchEvent `json:"events,omitempty" db:"-"` // Хронология матча
 }
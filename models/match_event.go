@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type MatchEventType string
+
+const (
+	MatchEventGoal         MatchEventType = "goal"
+	MatchEventCard         MatchEventType = "card"
+	MatchEventSubstitution MatchEventType = "substitution"
+	MatchEventRoundWon     MatchEventType = "round_won"
+	MatchEventTimeout      MatchEventType = "timeout"
+)
+
+// IsValid — тип события известен системе (разрешён ли он в конкретном спорте, решает Sport.MatchEventTypes).
+func (t MatchEventType) IsValid() bool {
+	switch t {
+	case MatchEventGoal, MatchEventCard, MatchEventSubstitution, MatchEventRoundWon, MatchEventTimeout:
+		return true
+	}
+	return false
+}
+
+type CardColor string
+
+const (
+	CardYellow CardColor = "yellow"
+	CardRed    CardColor = "red"
+)
+
+// MatchEvent — запись хронологии матча.
+// ParticipantID — сторона матча, к которой относится событие (для гола — сторона, которой он засчитан).
+type MatchEvent struct {
+	ID            int             `json:"id" db:"id"`
+	TournamentID  int             `json:"tournament_id" db:"tournament_id"`
+	MatchType     RatingMatchType `json:"match_type" db:"match_type"`
+	MatchID       int             `json:"match_id" db:"match_id"`
+	Type          MatchEventType  `json:"type" db:"event_type"`
+	ParticipantID int             `json:"participant_id" db:"participant_id"`
+	PlayerUserID  *int            `json:"player_user_id,omitempty" db:"player_user_id"`   // Автор гола, получивший карточку, ушедший с поля
+	RelatedUserID *int            `json:"related_user_id,omitempty" db:"related_user_id"` // Ассистент, вышедший на замену
+	Minute        *int            `json:"minute,omitempty" db:"minute"`
+	Period        *int            `json:"period,omitempty" db:"period"` // Тайм, период или номер раунда
+	Card          *CardColor      `json:"card,omitempty" db:"card"`
+	Note          *string         `json:"note,omitempty" db:"note"`
+	CreatedBy     *int            `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MatchReminder — напоминание одному пользователю о предстоящем матче.
+type MatchReminder struct {
+	MatchType      RatingMatchType `json:"match_type" db:"match_type"`
+	MatchID        int             `json:"match_id" db:"match_id"`
+	TournamentID   int             `json:"tournament_id" db:"tournament_id"`
+	TournamentName string          `json:"tournament_name" db:"tournament_name"`
+	MatchTime      time.Time       `json:"match_time" db:"match_time"`
+	UserID         int             `json:"user_id" db:"user_id"`
+}
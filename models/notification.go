@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationApplicationApproved NotificationType = "application_approved"
+	NotificationApplicationRejected NotificationType = "application_rejected"
+	NotificationMatchScheduled      NotificationType = "match_scheduled"
+	NotificationMatchRescheduled    NotificationType = "match_rescheduled"
+	NotificationMatchReminder       NotificationType = "match_reminder"
+	NotificationMatchResult         NotificationType = "match_result"
+	NotificationTeamInvite          NotificationType = "team_invite"
+	NotificationTournamentStatus    NotificationType = "tournament_status"
+)
+
+var notificationTypes = []NotificationType{
+	NotificationApplicationApproved,
+	NotificationApplicationRejected,
+	NotificationMatchScheduled,
+	NotificationMatchRescheduled,
+	NotificationMatchReminder,
+	NotificationMatchResult,
+	NotificationTeamInvite,
+	NotificationTournamentStatus,
+}
+
+func NotificationTypes() []NotificationType {
+	result := make([]NotificationType, len(notificationTypes))
+	copy(result, notificationTypes)
+	return result
+}
+
+func (t NotificationType) IsValid() bool {
+	for _, nt := range notificationTypes {
+		if nt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification — запись в центре уведомлений пользователя.
+// Title и Body хранятся уже на языке пользователя на момент создания.
+type Notification struct {
+	ID        int64                  `json:"id" db:"id"`
+	UserID    int                    `json:"user_id" db:"user_id"`
+	Type      NotificationType       `json:"type" db:"type"`
+	Title     string                 `json:"title" db:"title"`
+	Body      string                 `json:"body" db:"body"`
+	Data      map[string]interface{} `json:"data" db:"data"` // tournament_id, match_id, team_id, link
+	Read      bool                   `json:"read" db:"-"`
+	ReadAt    *time.Time             `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+type NotificationFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+type NotificationList struct {
+	Notifications []*Notification `json:"notifications"`
+	Total         int             `json:"total"`
+	UnreadCount   int             `json:"unread_count"`
+	Limit         int             `json:"limit"`
+	Offset        int             `json:"offset"`
+}
+
+// NotificationPreference — включённые каналы доставки для одного типа уведомлений.
+type NotificationPreference struct {
+	Type  NotificationType `json:"type" db:"type"`
+	Email bool             `json:"email" db:"email_enabled"`
+	InApp bool             `json:"in_app" db:"in_app_enabled"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type OrganizationRole string
+
+const (
+	OrgRoleOwner  OrganizationRole = "owner"
+	OrgRoleAdmin  OrganizationRole = "admin"
+	OrgRoleMember OrganizationRole = "member"
+)
+
+// CanManage сообщает, может ли роль управлять турнирами и участниками организации.
+func (r OrganizationRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+type Organization struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	Members []OrganizationMember `json:"members,omitempty" db:"-"`
+}
+
+type OrganizationMember struct {
+	OrganizationID int              `json:"organization_id" db:"organization_id"`
+	UserID         int              `json:"user_id" db:"user_id"`
+	Role           OrganizationRole `json:"role" db:"role"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+
+	User *User `json:"user,omitempty" db:"-"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+type RatingMatchType string
+
+const (
+	RatingMatchSolo RatingMatchType = "solo"
+	RatingMatchTeam RatingMatchType = "team"
+)
+
+type Rating struct {
+	ID            int       `json:"id" db:"id"`
+	SportID       int       `json:"sport_id" db:"sport_id"`
+	UserID        *int      `json:"user_id,omitempty" db:"user_id"`
+	TeamID        *int      `json:"team_id,omitempty" db:"team_id"`
+	Rating        float64   `json:"rating" db:"rating"`
+	Deviation     float64   `json:"deviation" db:"deviation"`
+	Volatility    float64   `json:"volatility" db:"volatility"`
+	MatchesPlayed int       `json:"matches_played" db:"matches_played"`
+	Wins          int       `json:"wins" db:"wins"`
+	Losses        int       `json:"losses" db:"losses"`
+	Draws         int       `json:"draws" db:"draws"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+
+	Name     string    `json:"name,omitempty" db:"-"`
+	LogoKey  *string   `json:"-" db:"-"`
+	LogoURL  *string   `json:"logo_url,omitempty" db:"-"`
+	LogoURLs *LogoURLs `json:"logo_urls,omitempty" db:"-"`
+	Rank     int       `json:"rank,omitempty" db:"-"`
+}
+
+type RatingHistoryEntry struct {
+	ID               int             `json:"id" db:"id"`
+	RatingID         int             `json:"rating_id" db:"rating_id"`
+	MatchType        RatingMatchType `json:"match_type" db:"match_type"`
+	MatchID          int             `json:"match_id" db:"match_id"`
+	TournamentID     int             `json:"tournament_id" db:"tournament_id"`
+	OpponentRatingID *int            `json:"opponent_rating_id,omitempty" db:"opponent_rating_id"`
+	Score            float64         `json:"score" db:"score"`
+	RatingBefore     float64         `json:"rating_before" db:"rating_before"`
+	RatingAfter      float64         `json:"rating_after" db:"rating_after"`
+	DeviationBefore  float64         `json:"deviation_before" db:"deviation_before"`
+	DeviationAfter   float64         `json:"deviation_after" db:"deviation_after"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+}
+
+// RatedMatch — завершённый матч в виде, достаточном для пересчёта рейтинга.
+type RatedMatch struct {
+	MatchType           RatingMatchType
+	MatchID             int
+	TournamentID        int
+	SportID             int
+	P1ParticipantID     int
+	P2ParticipantID     int
+	P1UserID            *int
+	P1TeamID            *int
+	P2UserID            *int
+	P2TeamID            *int
+	WinnerParticipantID *int
+	MatchTime           time.Time
+	CompletedAt         *time.Time // Рейтинги применяются в порядке завершения матчей
+}
@@ -0,0 +1,35 @@
+package models
+
+type ScoreSchema string
+
+const (
+	ScoreSchemaPoints ScoreSchema = "points" // "3-1", "72-75" (гольф), "3-1" по легам в дартс
+	ScoreSchemaSets   ScoreSchema = "sets"   // "6-4 3-6 7-6(5)", "25-23 25-20 25-18"
+	ScoreSchemaTime   ScoreSchema = "time"   // "1:02:03.450-1:02:05.100", "58.31-59.02"
+)
+
+// ScoringRules — правила счёта вида спорта. Хранится в sports.scoring_rules (JSONB).
+type ScoringRules struct {
+	Schema        ScoreSchema `json:"schema"`
+	DrawAllowed   bool        `json:"draw_allowed"`
+	LowerIsBetter bool        `json:"lower_is_better,omitempty"` // Время, гольф
+
+	// points: матч до RaceTo очков/легов (дартс «first to N»); 0 — без ограничения
+	RaceTo int `json:"race_to,omitempty"`
+
+	// sets: SetsToWin — сколько сетов нужно выиграть (best of 2N-1); 0 — играются все сеты
+	SetsToWin int `json:"sets_to_win,omitempty"`
+	// PointsPerSet — до скольких очков/геймов идёт сет (теннис 6, волейбол 25); 0 — не проверяется
+	PointsPerSet int `json:"points_per_set,omitempty"`
+	// DecidingSetPoints — до скольких очков идёт решающий сет (волейбол 15); 0 — как обычный
+	DecidingSetPoints int `json:"deciding_set_points,omitempty"`
+	// WinBy — с каким минимальным отрывом выигрывается сет (волейбол, теннис 2); 0 — 1
+	WinBy int `json:"win_by,omitempty"`
+	// TiebreakAt — при каком счёте в сете играется тай-брейк (теннис 6 → сет 7-6(5)); 0 — без тай-брейка
+	TiebreakAt int `json:"tiebreak_at,omitempty"`
+}
+
+// DefaultScoringRules — поведение до появления правил: целые очки "S1-S2", ничьи разрешены.
+func DefaultScoringRules() ScoringRules {
+	return ScoringRules{Schema: ScoreSchemaPoints, DrawAllowed: true}
+}
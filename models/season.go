@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+type Season struct {
+	ID                  int       `json:"id" db:"id"`
+	Name                string    `json:"name" db:"name"`
+	Description         *string   `json:"description,omitempty" db:"description"`
+	SportID             int       `json:"sport_id" db:"sport_id"`
+	OrganizerID         int       `json:"organizer_id" db:"organizer_id"`
+	OrganizationID      *int      `json:"organization_id,omitempty" db:"organization_id"`
+	PointsTable         []int     `json:"points_table" db:"points_table"` // Хранится как JSON; индекс 0 = очки за 1 место
+	ParticipationPoints int       `json:"participation_points" db:"participation_points"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+
+	Sport       *Sport       `json:"sport,omitempty" db:"-"`
+	Tournaments []Tournament `json:"tournaments,omitempty" db:"-"`
+}
+
+// PointsForPlacement возвращает очки за занятое место (места начинаются с 1).
+func (s *Season) PointsForPlacement(placement int) int {
+	if placement >= 1 && placement <= len(s.PointsTable) {
+		return s.PointsTable[placement-1]
+	}
+	return s.ParticipationPoints
+}
+
+type SeasonResult struct {
+	ID            int       `json:"id" db:"id"`
+	SeasonID      int       `json:"season_id" db:"season_id"`
+	TournamentID  int       `json:"tournament_id" db:"tournament_id"`
+	ParticipantID int       `json:"participant_id" db:"participant_id"`
+	UserID        *int      `json:"user_id,omitempty" db:"user_id"`
+	TeamID        *int      `json:"team_id,omitempty" db:"team_id"`
+	Placement     int       `json:"placement" db:"placement"`
+	Points        int       `json:"points" db:"points"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+type SeasonLeaderboardEntry struct {
+	Rank              int       `json:"rank"`
+	UserID            *int      `json:"user_id,omitempty"`
+	TeamID            *int      `json:"team_id,omitempty"`
+	Name              string    `json:"name"`
+	LogoKey           *string   `json:"-"`
+	LogoURL           *string   `json:"logo_url,omitempty"`
+	LogoURLs          *LogoURLs `json:"logo_urls,omitempty"`
+	Points            int       `json:"points"`
+	TournamentsPlayed int       `json:"tournaments_played"`
+	TournamentWins    int       `json:"tournament_wins"`
+	BestPlacement     int       `json:"best_placement"`
+}
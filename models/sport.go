@@ -4,7 +4,25 @@ package models
 type Sport struct {
 	ID   int    `json:"id" db:"id"`
 	Name string `json:"name" db:"name"`
-	
-	LogoKey *string `json:"-" db:"logo_key"`
-	LogoURL *string `json:"logo_url,omitempty" db:"-"`
+
+	// MatchEventTypes — какие события можно вносить в хронологию матчей этого спорта
+	MatchEventTypes []MatchEventType `json:"match_event_types" db:"match_event_types"`
+	// ScoreFromGoals — итоговый счёт матча считается по событиям goal
+	ScoreFromGoals bool `json:"score_from_goals" db:"score_from_goals"`
+	// ScoringRules — формат счёта и как по нему определяется победитель
+	ScoringRules ScoringRules `json:"scoring_rules" db:"scoring_rules"`
+
+	LogoKey  *string   `json:"-" db:"logo_key"`
+	LogoURL  *string   `json:"logo_url,omitempty" db:"-"`
+	LogoURLs *LogoURLs `json:"logo_urls,omitempty" db:"-"`
+}
+
+// AllowsMatchEvent — разрешён ли тип события в хронологии матчей этого спорта.
+func (s *Sport) AllowsMatchEvent(t MatchEventType) bool {
+	for _, allowed := range s.MatchEventTypes {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
 }
@@ -9,11 +9,15 @@ type Team struct {
 	CaptainID int       `json:"captain_id" db:"captain_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 
-	Sport        *Sport        `json:"sport,omitempty" db:"-"`
-	Captain      *User         `json:"captain,omitempty" db:"-"`
-	Members      []User        `json:"members,omitempty" db:"-"`
-	Participants []Participant `json:"participants,omitempty" db:"-"`
+	OrganizationID *int `json:"organization_id,omitempty" db:"organization_id"`
 
-	LogoKey *string `json:"-" db:"logo_key"`
-	LogoURL *string `json:"logo_url,omitempty" db:"-"`
+	Sport        *Sport                `json:"sport,omitempty" db:"-"`
+	Captain      *User                 `json:"captain,omitempty" db:"-"`
+	Members      []User                `json:"members,omitempty" db:"-"`
+	Participants []Participant         `json:"participants,omitempty" db:"-"`
+	Placements   []TournamentPlacement `json:"placements,omitempty" db:"-"`
+
+	LogoKey  *string   `json:"-" db:"logo_key"`
+	LogoURL  *string   `json:"logo_url,omitempty" db:"-"`
+	LogoURLs *LogoURLs `json:"logo_urls,omitempty" db:"-"`
 }
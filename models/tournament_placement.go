@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type TournamentPlacement struct {
+	TournamentID  int       `json:"tournament_id" db:"tournament_id"`
+	ParticipantID int       `json:"participant_id" db:"participant_id"`
+	Placement     int       `json:"placement" db:"placement"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+
+	// Заполняются при выборке для профилей игрока и команды
+	UserID         *int       `json:"user_id,omitempty" db:"-"`
+	TeamID         *int       `json:"team_id,omitempty" db:"-"`
+	TournamentName string     `json:"tournament_name,omitempty" db:"-"`
+	SportID        int        `json:"sport_id,omitempty" db:"-"`
+	EndDate        *time.Time `json:"end_date,omitempty" db:"-"`
+}
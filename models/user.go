@@ -22,6 +22,9 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	LogoKey      *string   `json:"-" db:"logo_key"`
 	LogoURL      *string   `json:"logo_url,omitempty" db:"-"`
+	LogoURLs     *LogoURLs `json:"logo_urls,omitempty" db:"-"`
+	Locale       string    `json:"locale" db:"locale"`
+	Timezone     string    `json:"timezone" db:"timezone"`
 
 	EmailConfirmed         bool   `json:"email_confirmed" db:"email_confirmed"`
 	EmailConfirmationToken string `json:"-" db:"email_confirmation_token"`
@@ -29,7 +32,8 @@ type User struct {
 	PasswordResetToken     *string    `json:"-" db:"password_reset_token"`
 	PasswordResetExpiresAt *time.Time `json:"-" db:"password_reset_expires_at"`
 
-	Team *Team `json:"team,omitempty" db:"-"`
+	Team       *Team                 `json:"team,omitempty" db:"-"`
+	Placements []TournamentPlacement `json:"placements,omitempty" db:"-"`
 }
 
 type Credentials struct {
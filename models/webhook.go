@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookMatchStarted           WebhookEvent = "match.started"
+	WebhookMatchScoreUpdated      WebhookEvent = "match.score_updated"
+	WebhookMatchCompleted         WebhookEvent = "match.completed"
+	WebhookMatchRescheduled       WebhookEvent = "match.rescheduled"
+	WebhookTournamentStatusChange WebhookEvent = "tournament.status_changed"
+	WebhookTournamentCompleted    WebhookEvent = "tournament.completed"
+	WebhookBracketUpdated         WebhookEvent = "bracket.updated"
+	WebhookParticipantApproved    WebhookEvent = "participant.approved"
+	WebhookParticipantRejected    WebhookEvent = "participant.rejected"
+)
+
+var webhookEvents = []WebhookEvent{
+	WebhookMatchStarted,
+	WebhookMatchScoreUpdated,
+	WebhookMatchCompleted,
+	WebhookMatchRescheduled,
+	WebhookTournamentStatusChange,
+	WebhookTournamentCompleted,
+	WebhookBracketUpdated,
+	WebhookParticipantApproved,
+	WebhookParticipantRejected,
+}
+
+func WebhookEvents() []WebhookEvent {
+	result := make([]WebhookEvent, len(webhookEvents))
+	copy(result, webhookEvents)
+	return result
+}
+
+func (e WebhookEvent) IsValid() bool {
+	for _, ev := range webhookEvents {
+		if ev == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook — подписка на события турниров организатора (OwnerUserID) или организации (OrganizationID).
+// Events пустой — подписка на все события.
+type Webhook struct {
+	ID             int            `json:"id" db:"id"`
+	OwnerUserID    *int           `json:"owner_user_id,omitempty" db:"owner_user_id"`
+	OrganizationID *int           `json:"organization_id,omitempty" db:"organization_id"`
+	URL            string         `json:"url" db:"url"`
+	Secret         string         `json:"secret,omitempty" db:"secret"` // Отдаётся только при создании
+	Events         []WebhookEvent `json:"events" db:"events"`
+	IsActive       bool           `json:"is_active" db:"is_active"`
+	CreatedBy      *int           `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead" // Исчерпаны попытки доставки
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery — одна доставка события на URL подписки.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id" db:"id"`
+	WebhookID      int                   `json:"webhook_id" db:"webhook_id"`
+	Event          WebhookEvent          `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	MaxAttempts    int                   `json:"max_attempts" db:"max_attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	RedeliveryOf   *int64                `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	Status *WebhookDeliveryStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,120 @@
+// Package ratings реализует расчёт рейтинга Glicko-2.
+// Каждый матч рассматривается как отдельный рейтинговый период с одним соперником.
+package ratings
+
+import "math"
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// MinDeviation не даёт рейтингу "застыть" у очень активных игроков
+	MinDeviation = 30.0
+
+	glickoScale = 173.7178
+	tau         = 0.5
+	epsilon     = 0.000001
+)
+
+// Результат матча с точки зрения игрока.
+const (
+	ScoreLoss = 0.0
+	ScoreDraw = 0.5
+	ScoreWin  = 1.0
+)
+
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+func NewRating() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// Update возвращает новый рейтинг игрока после одной игры против opponent.
+// Оба рейтинга должны быть взяты до матча.
+func Update(player, opponent Rating, score float64) Rating {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.Deviation / glickoScale
+	muJ := (opponent.Rating - DefaultRating) / glickoScale
+	phiJ := opponent.Deviation / glickoScale
+
+	gJ := g(phiJ)
+	e := expected(mu, muJ, gJ)
+	v := 1 / (gJ * gJ * e * (1 - e))
+	delta := v * gJ * (score - e)
+
+	sigma := newVolatility(phi, player.Volatility, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*gJ*(score-e)
+
+	deviation := newPhi * glickoScale
+	if deviation < MinDeviation {
+		deviation = MinDeviation
+	}
+	if deviation > DefaultDeviation {
+		deviation = DefaultDeviation
+	}
+
+	return Rating{
+		Rating:     newMu*glickoScale + DefaultRating,
+		Deviation:  deviation,
+		Volatility: sigma,
+	}
+}
+
+// ExpectedScore — вероятность победы player над opponent.
+func ExpectedScore(player, opponent Rating) float64 {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	muJ := (opponent.Rating - DefaultRating) / glickoScale
+	return expected(mu, muJ, g(opponent.Deviation/glickoScale))
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
+
+// newVolatility — итеративный алгоритм Illinois из спецификации Glicko-2.
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * (phi*phi + v + ex) * (phi*phi + v + ex)
+		return num/den - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for i := 0; math.Abs(B-A) > epsilon && i < 100; i++ {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
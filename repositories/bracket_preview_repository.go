@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var ErrBracketPreviewNotFound = errors.New("bracket preview not found")
+
+type BracketPreviewRepository interface {
+	Save(ctx context.Context, exec SQLExecutor, preview *models.BracketPreview) error
+	GetByTournament(ctx context.Context, exec SQLExecutor, tournamentID int) (*models.BracketPreview, error)
+	Delete(ctx context.Context, exec SQLExecutor, tournamentID int) error
+}
+
+type postgresBracketPreviewRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBracketPreviewRepository(db *sql.DB) BracketPreviewRepository {
+	return &postgresBracketPreviewRepository{db: db}
+}
+
+func (r *postgresBracketPreviewRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+// Save фиксирует предпросмотр турнира, заменяя ранее зафиксированный.
+func (r *postgresBracketPreviewRepository) Save(ctx context.Context, exec SQLExecutor, preview *models.BracketPreview) error {
+	matchesJSON, err := json.Marshal(preview.Matches)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bracket preview matches: %w", err)
+	}
+	settingsJSON, err := json.Marshal(preview.FormatSettings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bracket preview format settings: %w", err)
+	}
+
+	query := `
+		INSERT INTO bracket_previews (tournament_id, bracket_type, format_settings, participant_ids, seeded, matches, locked_by, locked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
+		ON CONFLICT (tournament_id) DO UPDATE
+		SET bracket_type = EXCLUDED.bracket_type,
+		    format_settings = EXCLUDED.format_settings,
+		    participant_ids = EXCLUDED.participant_ids,
+		    seeded = EXCLUDED.seeded,
+		    matches = EXCLUDED.matches,
+		    locked_by = EXCLUDED.locked_by,
+		    locked_at = EXCLUDED.locked_at
+		RETURNING locked_at`
+
+	var lockedAt sql.NullTime
+	err = r.getExecutor(exec).QueryRowContext(ctx, query,
+		preview.TournamentID, preview.BracketType, settingsJSON, pq.Array(preview.ParticipantIDs), preview.Seeded, matchesJSON, preview.LockedBy,
+	).Scan(&lockedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrTournamentNotFound
+		}
+		return fmt.Errorf("failed to save bracket preview for tournament %d: %w", preview.TournamentID, err)
+	}
+	if lockedAt.Valid {
+		preview.LockedAt = &lockedAt.Time
+	}
+	preview.Locked = true
+	return nil
+}
+
+func (r *postgresBracketPreviewRepository) GetByTournament(ctx context.Context, exec SQLExecutor, tournamentID int) (*models.BracketPreview, error) {
+	query := `
+		SELECT tournament_id, bracket_type, format_settings, participant_ids, seeded, matches, locked_by, locked_at
+		FROM bracket_previews
+		WHERE tournament_id = $1`
+
+	var preview models.BracketPreview
+	var participantIDs pq.Int64Array
+	var settingsJSON, matchesJSON []byte
+	var lockedAt sql.NullTime
+	err := r.getExecutor(exec).QueryRowContext(ctx, query, tournamentID).Scan(
+		&preview.TournamentID, &preview.BracketType, &settingsJSON, &participantIDs, &preview.Seeded, &matchesJSON, &preview.LockedBy, &lockedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBracketPreviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get bracket preview for tournament %d: %w", tournamentID, err)
+	}
+
+	preview.ParticipantIDs = make([]int, len(participantIDs))
+	for i, id := range participantIDs {
+		preview.ParticipantIDs[i] = int(id)
+	}
+	if err := json.Unmarshal(matchesJSON, &preview.Matches); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bracket preview matches for tournament %d: %w", tournamentID, err)
+	}
+	// NULL у предпросмотров, зафиксированных до появления колонки, — настройки остаются nil.
+	if settingsJSON != nil {
+		if err := json.Unmarshal(settingsJSON, &preview.FormatSettings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal bracket preview format settings for tournament %d: %w", tournamentID, err)
+		}
+	}
+	if lockedAt.Valid {
+		preview.LockedAt = &lockedAt.Time
+	}
+	preview.Locked = true
+	return &preview, nil
+}
+
+func (r *postgresBracketPreviewRepository) Delete(ctx context.Context, exec SQLExecutor, tournamentID int) error {
+	result, err := r.getExecutor(exec).ExecContext(ctx, `DELETE FROM bracket_previews WHERE tournament_id = $1`, tournamentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bracket preview for tournament %d: %w", tournamentID, err)
+	}
+	return checkAffectedRows(result, ErrBracketPreviewNotFound)
+}
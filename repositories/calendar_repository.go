@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+var ErrCalendarTokenNotFound = errors.New("calendar token not found")
+
+type CalendarRepository interface {
+	ListByTournament(ctx context.Context, tournamentID int) ([]models.CalendarMatch, error)
+	ListByTeam(ctx context.Context, teamID int) ([]models.CalendarMatch, error)
+	// ListByUser — одиночные матчи пользователя и матчи его текущей команды.
+	ListByUser(ctx context.Context, userID int) ([]models.CalendarMatch, error)
+
+	GetToken(ctx context.Context, userID int) (string, error)
+	GetUserIDByToken(ctx context.Context, token string) (int, error)
+	// SetToken создаёт или заменяет токен персональной ленты пользователя.
+	SetToken(ctx context.Context, userID int, token string) error
+}
+
+type postgresCalendarRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCalendarRepository(db *sql.DB) CalendarRepository {
+	return &postgresCalendarRepository{db: db}
+}
+
+// calendarMatchesQuery собирает одиночные и командные матчи; условия получают
+// алиасы m (матч), p1/p2 (участники) и должны использовать параметр $1.
+func calendarMatchesQuery(soloWhere, teamWhere string) string {
+	return `
+		SELECT 'solo', m.id, m.tournament_id, t.name, t.status, t.location, m.round, m.match_time, m.status, m.score,
+		       u1.nickname, u2.nickname
+		FROM solo_matches m
+		JOIN tournaments t ON t.id = m.tournament_id
+		LEFT JOIN participants p1 ON p1.id = m.p1_participant_id
+		LEFT JOIN users u1 ON u1.id = p1.user_id
+		LEFT JOIN participants p2 ON p2.id = m.p2_participant_id
+		LEFT JOIN users u2 ON u2.id = p2.user_id
+		WHERE ` + soloWhere + `
+		UNION ALL
+		SELECT 'team', m.id, m.tournament_id, t.name, t.status, t.location, m.round, m.match_time, m.status, m.score,
+		       tm1.name, tm2.name
+		FROM team_matches m
+		JOIN tournaments t ON t.id = m.tournament_id
+		LEFT JOIN participants p1 ON p1.id = m.t1_participant_id
+		LEFT JOIN teams tm1 ON tm1.id = p1.team_id
+		LEFT JOIN participants p2 ON p2.id = m.t2_participant_id
+		LEFT JOIN teams tm2 ON tm2.id = p2.team_id
+		WHERE ` + teamWhere + `
+		ORDER BY 8, 1, 2`
+}
+
+func (r *postgresCalendarRepository) ListByTournament(ctx context.Context, tournamentID int) ([]models.CalendarMatch, error) {
+	return r.list(ctx, calendarMatchesQuery("m.tournament_id = $1", "m.tournament_id = $1"), tournamentID)
+}
+
+func (r *postgresCalendarRepository) ListByTeam(ctx context.Context, teamID int) ([]models.CalendarMatch, error) {
+	return r.list(ctx, calendarMatchesQuery("FALSE", "$1 IN (p1.team_id, p2.team_id)"), teamID)
+}
+
+func (r *postgresCalendarRepository) ListByUser(ctx context.Context, userID int) ([]models.CalendarMatch, error) {
+	query := calendarMatchesQuery(
+		"$1 IN (p1.user_id, p2.user_id)",
+		"(SELECT team_id FROM users WHERE id = $1) IN (p1.team_id, p2.team_id)",
+	)
+	return r.list(ctx, query, userID)
+}
+
+func (r *postgresCalendarRepository) list(ctx context.Context, query string, arg int) ([]models.CalendarMatch, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]models.CalendarMatch, 0)
+	for rows.Next() {
+		var m models.CalendarMatch
+		if err := rows.Scan(
+			&m.MatchType, &m.MatchID, &m.TournamentID, &m.TournamentName, &m.TournamentStatus, &m.Location,
+			&m.Round, &m.MatchTime, &m.Status, &m.Score, &m.Side1Name, &m.Side2Name,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func (r *postgresCalendarRepository) GetToken(ctx context.Context, userID int) (string, error) {
+	var token string
+	err := r.db.QueryRowContext(ctx, `SELECT token FROM calendar_tokens WHERE user_id = $1`, userID).Scan(&token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrCalendarTokenNotFound
+		}
+		return "", fmt.Errorf("failed to get calendar token of user %d: %w", userID, err)
+	}
+	return token, nil
+}
+
+func (r *postgresCalendarRepository) GetUserIDByToken(ctx context.Context, token string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `SELECT user_id FROM calendar_tokens WHERE token = $1`, token).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCalendarTokenNotFound
+		}
+		return 0, fmt.Errorf("failed to resolve calendar token: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *postgresCalendarRepository) SetToken(ctx context.Context, userID int, token string) error {
+	query := `
+		INSERT INTO calendar_tokens (user_id, token)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP`
+	if _, err := r.db.ExecContext(ctx, query, userID, token); err != nil {
+		return fmt.Errorf("failed to set calendar token of user %d: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+// careerEntity описывает, откуда брать матчи: игроки — solo_matches, команды — team_matches.
+type careerEntity struct {
+	matchesTable      string
+	slotPrefix        string
+	participantColumn string
+	opponentJoin      string
+	opponentName      string
+}
+
+var (
+	soloCareerEntity = careerEntity{
+		matchesTable:      "solo_matches",
+		slotPrefix:        "p",
+		participantColumn: "user_id",
+		opponentJoin:      "LEFT JOIN users ou ON ou.id = opp.user_id",
+		opponentName:      "COALESCE(NULLIF(ou.nickname, ''), ou.first_name || ' ' || ou.last_name, '')",
+	}
+	teamCareerEntity = careerEntity{
+		matchesTable:      "team_matches",
+		slotPrefix:        "t",
+		participantColumn: "team_id",
+		opponentJoin:      "LEFT JOIN teams ot ON ot.id = opp.team_id",
+		opponentName:      "COALESCE(ot.name, '')",
+	}
+)
+
+func careerEntityFor(teams bool) careerEntity {
+	if teams {
+		return teamCareerEntity
+	}
+	return soloCareerEntity
+}
+
+type CareerMatchFilter struct {
+	SportID    *int
+	OpponentID *int // user_id или team_id соперника
+	Limit      int
+	Offset     int
+}
+
+type CareerRepository interface {
+	ListSportStats(ctx context.Context, teams bool, entityID int) ([]models.CareerSportStats, error)
+	ListTournaments(ctx context.Context, teams bool, entityID int, limit, offset int) ([]models.CareerTournament, int, error)
+	ListMatches(ctx context.Context, teams bool, entityID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error)
+	GetHeadToHead(ctx context.Context, teams bool, entityID, opponentID int) (*models.HeadToHeadRecord, error)
+}
+
+type postgresCareerRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCareerRepository(db *sql.DB) CareerRepository {
+	return &postgresCareerRepository{db: db}
+}
+
+// matchesFrom — общая часть запросов по матчам: матчи сущности ($1) с соперником.
+func (e careerEntity) matchesFrom() string {
+	return fmt.Sprintf(`
+		FROM %[1]s m
+		JOIN participants me ON me.id IN (m.%[2]s1_participant_id, m.%[2]s2_participant_id)
+		JOIN tournaments t ON t.id = m.tournament_id
+		LEFT JOIN participants opp ON opp.id = CASE WHEN m.%[2]s1_participant_id = me.id THEN m.%[2]s2_participant_id ELSE m.%[2]s1_participant_id END
+		%[4]s
+		WHERE me.%[3]s = $1`, e.matchesTable, e.slotPrefix, e.participantColumn, e.opponentJoin)
+}
+
+func (r *postgresCareerRepository) ListSportStats(ctx context.Context, teams bool, entityID int) ([]models.CareerSportStats, error) {
+	e := careerEntityFor(teams)
+
+	tournamentsQuery := fmt.Sprintf(`
+		SELECT t.sport_id, s.name,
+		       COUNT(DISTINCT t.id),
+		       COUNT(*) FILTER (WHERE tp.placement = 1),
+		       COUNT(*) FILTER (WHERE tp.placement <= 3),
+		       MIN(tp.placement)
+		FROM participants p
+		JOIN tournaments t ON t.id = p.tournament_id
+		JOIN sports s ON s.id = t.sport_id
+		LEFT JOIN tournament_placements tp ON tp.participant_id = p.id
+		WHERE p.%s = $1 AND p.status = $2
+		GROUP BY t.sport_id, s.name
+		ORDER BY s.name ASC`, e.participantColumn)
+
+	rows, err := r.db.QueryContext(ctx, tournamentsQuery, entityID, models.StatusParticipant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]models.CareerSportStats, 0)
+	bySport := make(map[int]int)
+	for rows.Next() {
+		var st models.CareerSportStats
+		var best sql.NullInt64
+		if scanErr := rows.Scan(&st.SportID, &st.SportName, &st.TournamentsPlayed, &st.TournamentWins, &st.Podiums, &best); scanErr != nil {
+			return nil, scanErr
+		}
+		if best.Valid {
+			b := int(best.Int64)
+			st.BestPlacement = &b
+		}
+		bySport[st.SportID] = len(stats)
+		stats = append(stats, st)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	matchesQuery := `
+		SELECT t.sport_id,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id = me.id),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id IS NOT NULL AND m.winner_participant_id <> me.id),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id IS NULL)` +
+		e.matchesFrom() + `
+		AND m.status = $2
+		GROUP BY t.sport_id`
+
+	matchRows, err := r.db.QueryContext(ctx, matchesQuery, entityID, models.MatchStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer matchRows.Close()
+
+	for matchRows.Next() {
+		var sportID, played, wins, losses, draws int
+		if scanErr := matchRows.Scan(&sportID, &played, &wins, &losses, &draws); scanErr != nil {
+			return nil, scanErr
+		}
+		idx, ok := bySport[sportID]
+		if !ok {
+			continue // Матчи без подтверждённого участия не учитываем
+		}
+		stats[idx].MatchesPlayed = played
+		stats[idx].Wins = wins
+		stats[idx].Losses = losses
+		stats[idx].Draws = draws
+	}
+	if err = matchRows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *postgresCareerRepository) ListTournaments(ctx context.Context, teams bool, entityID int, limit, offset int) ([]models.CareerTournament, int, error) {
+	e := careerEntityFor(teams)
+	query := fmt.Sprintf(`
+		SELECT t.id, t.name, t.sport_id, t.status, t.start_date, t.end_date, t.logo_key,
+		       p.id, tp.placement, COUNT(*) OVER()
+		FROM participants p
+		JOIN tournaments t ON t.id = p.tournament_id
+		LEFT JOIN tournament_placements tp ON tp.participant_id = p.id
+		WHERE p.%s = $1 AND p.status = $2
+		ORDER BY t.start_date DESC, t.id DESC
+		LIMIT $3 OFFSET $4`, e.participantColumn)
+
+	rows, err := r.db.QueryContext(ctx, query, entityID, models.StatusParticipant, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	total := 0
+	list := make([]models.CareerTournament, 0)
+	for rows.Next() {
+		var ct models.CareerTournament
+		if scanErr := rows.Scan(
+			&ct.TournamentID, &ct.Name, &ct.SportID, &ct.Status, &ct.StartDate, &ct.EndDate, &ct.LogoKey,
+			&ct.ParticipantID, &ct.Placement, &total,
+		); scanErr != nil {
+			return nil, 0, scanErr
+		}
+		list = append(list, ct)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+func (r *postgresCareerRepository) ListMatches(ctx context.Context, teams bool, entityID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error) {
+	e := careerEntityFor(teams)
+	query := fmt.Sprintf(`
+		SELECT m.id, m.tournament_id, t.name, t.sport_id, m.round, m.match_time, m.status, m.score,
+		       m.winner_participant_id, me.id, m.%[1]s1_participant_id = me.id,
+		       opp.id, opp.user_id, opp.team_id, %[2]s,
+		       COUNT(*) OVER()`, e.slotPrefix, e.opponentName) + e.matchesFrom()
+
+	args := []interface{}{entityID}
+	argID := 2
+	if filter.SportID != nil {
+		query += fmt.Sprintf(" AND t.sport_id = $%d", argID)
+		args = append(args, *filter.SportID)
+		argID++
+	}
+	if filter.OpponentID != nil {
+		query += fmt.Sprintf(" AND opp.%s = $%d", e.participantColumn, argID)
+		args = append(args, *filter.OpponentID)
+		argID++
+	}
+	query += fmt.Sprintf(" ORDER BY m.match_time DESC, m.id DESC LIMIT $%d OFFSET $%d", argID, argID+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	total := 0
+	matches := make([]models.CareerMatch, 0)
+	for rows.Next() {
+		var cm models.CareerMatch
+		var oppName string
+		if scanErr := rows.Scan(
+			&cm.MatchID, &cm.TournamentID, &cm.TournamentName, &cm.SportID, &cm.Round, &cm.MatchTime, &cm.Status, &cm.Score,
+			&cm.WinnerParticipantID, &cm.ParticipantID, &cm.IsFirstSlot,
+			&cm.OpponentParticipantID, &cm.OpponentUserID, &cm.OpponentTeamID, &oppName,
+			&total,
+		); scanErr != nil {
+			return nil, 0, scanErr
+		}
+		cm.OpponentName = oppName
+		matches = append(matches, cm)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
+}
+
+func (r *postgresCareerRepository) GetHeadToHead(ctx context.Context, teams bool, entityID, opponentID int) (*models.HeadToHeadRecord, error) {
+	e := careerEntityFor(teams)
+	query := `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id = me.id),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id IS NOT NULL AND m.winner_participant_id <> me.id),
+		       COUNT(*) FILTER (WHERE m.winner_participant_id IS NULL)` +
+		e.matchesFrom() + fmt.Sprintf(`
+		AND opp.%s = $2 AND m.status = $3`, e.participantColumn)
+
+	h2h := &models.HeadToHeadRecord{OpponentID: opponentID}
+	err := r.db.QueryRowContext(ctx, query, entityID, opponentID, models.MatchStatusCompleted).Scan(
+		&h2h.MatchesPlayed, &h2h.Wins, &h2h.Losses, &h2h.Draws,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return h2h, nil
+}
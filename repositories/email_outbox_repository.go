@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var ErrOutboxEmailNotFound = errors.New("outbox email not found")
+
+type EmailOutboxRepository interface {
+	Enqueue(ctx context.Context, exec SQLExecutor, email *models.OutboxEmail) error
+	// ClaimDue забирает готовые к отправке письма и откладывает их на lease,
+	// чтобы другой воркер не взял те же письма, пока идёт отправка.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEmail, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time, dead bool) error
+	// Requeue возвращает неотправленное письмо в очередь с обнулёнными попытками.
+	Requeue(ctx context.Context, id int64) (*models.OutboxEmail, error)
+	GetByID(ctx context.Context, id int64) (*models.OutboxEmail, error)
+	List(ctx context.Context, filter models.OutboxEmailFilter) ([]*models.OutboxEmail, int, error)
+}
+
+type postgresEmailOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresEmailOutboxRepository(db *sql.DB) EmailOutboxRepository {
+	return &postgresEmailOutboxRepository{db: db}
+}
+
+func (r *postgresEmailOutboxRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const outboxEmailColumns = `id, kind, recipients, subject, html_body, status, attempts, max_attempts,
+		next_attempt_at, last_error, created_at, updated_at, sent_at`
+
+func (r *postgresEmailOutboxRepository) Enqueue(ctx context.Context, exec SQLExecutor, email *models.OutboxEmail) error {
+	query := `
+		INSERT INTO email_outbox (kind, recipients, subject, html_body, max_attempts)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, 0), 8))
+		RETURNING ` + outboxEmailColumns
+
+	row := r.getExecutor(exec).QueryRowContext(ctx, query,
+		email.Kind, pq.Array(email.Recipients), email.Subject, email.HTMLBody, email.MaxAttempts,
+	)
+	if err := scanOutboxEmail(row, email); err != nil {
+		return fmt.Errorf("failed to enqueue %s email: %w", email.Kind, err)
+	}
+	return nil
+}
+
+func (r *postgresEmailOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEmail, error) {
+	query := `
+		UPDATE email_outbox
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxEmailColumns
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := make([]*models.OutboxEmail, 0)
+	for rows.Next() {
+		var email models.OutboxEmail
+		if err := scanOutboxEmail(rows, &email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, &email)
+	}
+	return emails, rows.Err()
+}
+
+func (r *postgresEmailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	query := `
+		UPDATE email_outbox
+		SET status = 'sent', attempts = attempts + 1, last_error = NULL,
+		    sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark email %d as sent: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrOutboxEmailNotFound)
+}
+
+func (r *postgresEmailOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := models.OutboxEmailPending
+	if dead {
+		status = models.OutboxEmailDead
+	}
+	query := `
+		UPDATE email_outbox
+		SET status = $2, attempts = attempts + 1, last_error = $3,
+		    next_attempt_at = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, status, lastError, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark email %d as failed: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrOutboxEmailNotFound)
+}
+
+func (r *postgresEmailOutboxRepository) Requeue(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	query := `
+		UPDATE email_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status <> 'sent'
+		RETURNING ` + outboxEmailColumns
+
+	var email models.OutboxEmail
+	if err := scanOutboxEmail(r.db.QueryRowContext(ctx, query, id), &email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOutboxEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to requeue email %d: %w", id, err)
+	}
+	return &email, nil
+}
+
+func (r *postgresEmailOutboxRepository) GetByID(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	query := `SELECT ` + outboxEmailColumns + ` FROM email_outbox WHERE id = $1`
+	var email models.OutboxEmail
+	if err := scanOutboxEmail(r.db.QueryRowContext(ctx, query, id), &email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOutboxEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to get email %d: %w", id, err)
+	}
+	return &email, nil
+}
+
+func (r *postgresEmailOutboxRepository) List(ctx context.Context, filter models.OutboxEmailFilter) ([]*models.OutboxEmail, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Kind != nil {
+		args = append(args, *filter.Kind)
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM email_outbox`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count outbox emails: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := `SELECT ` + outboxEmailColumns + ` FROM email_outbox` + where +
+		fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list outbox emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := make([]*models.OutboxEmail, 0)
+	for rows.Next() {
+		var email models.OutboxEmail
+		if err := scanOutboxEmail(rows, &email); err != nil {
+			return nil, 0, err
+		}
+		emails = append(emails, &email)
+	}
+	return emails, total, rows.Err()
+}
+
+func scanOutboxEmail(rowScanner interface {
+	Scan(dest ...interface{}) error
+}, email *models.OutboxEmail) error {
+	var recipients pq.StringArray
+	var lastError sql.NullString
+	var sentAt sql.NullTime
+	if err := rowScanner.Scan(
+		&email.ID, &email.Kind, &recipients, &email.Subject, &email.HTMLBody, &email.Status, &email.Attempts, &email.MaxAttempts,
+		&email.NextAttemptAt, &lastError, &email.CreatedAt, &email.UpdatedAt, &sentAt,
+	); err != nil {
+		return err
+	}
+	email.Recipients = []string(recipients)
+	email.LastError = nil
+	if lastError.Valid {
+		email.LastError = &lastError.String
+	}
+	email.SentAt = nil
+	if sentAt.Valid {
+		email.SentAt = &sentAt.Time
+	}
+	return nil
+}
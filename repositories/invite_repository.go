@@ -18,10 +18,10 @@ var (
 )
 
 type InviteRepository interface {
-	Create(ctx context.Context, invite *models.Invite) error
+	Create(ctx context.Context, exec SQLExecutor, invite *models.Invite) error
 	GetByToken(ctx context.Context, token string) (*models.Invite, error)
 	GetValidByTeamID(ctx context.Context, teamID int) (*models.Invite, error)
-	Update(ctx context.Context, invite *models.Invite) error
+	Update(ctx context.Context, exec SQLExecutor, invite *models.Invite) error
 	DeleteByTeamID(ctx context.Context, teamID int) (int64, error) // Возвращает кол-во удаленных
 	CleanupExpired(ctx context.Context) (int64, error)             // Возвращает кол-во удаленных
 }
@@ -34,13 +34,20 @@ func NewPostgresInviteRepository(db *sql.DB) InviteRepository {
 	return &postgresInviteRepository{db: db}
 }
 
-func (r *postgresInviteRepository) Create(ctx context.Context, invite *models.Invite) error {
+func (r *postgresInviteRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+func (r *postgresInviteRepository) Create(ctx context.Context, exec SQLExecutor, invite *models.Invite) error {
 	query := `
 		INSERT INTO invites (team_id, token, expires_at)
 		VALUES ($1, $2, $3)
 		RETURNING id, created_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.getExecutor(exec).QueryRowContext(ctx, query,
 		invite.TeamID,
 		invite.Token,
 		invite.ExpiresAt,
@@ -120,14 +127,14 @@ func (r *postgresInviteRepository) GetValidByTeamID(ctx context.Context, teamID
 	return invite, nil
 }
 
-func (r *postgresInviteRepository) Update(ctx context.Context, invite *models.Invite) error {
+func (r *postgresInviteRepository) Update(ctx context.Context, exec SQLExecutor, invite *models.Invite) error {
 	query := `
 		UPDATE invites SET
 			token = $1,
 			expires_at = $2
 		WHERE id = $3 AND team_id = $4` // Обновляем только токен и время жизни
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := r.getExecutor(exec).ExecContext(ctx, query,
 		invite.Token,
 		invite.ExpiresAt,
 		invite.ID,
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// LogoKeyRepository читает ключи загруженных файлов, на которые ссылаются записи,
+// — для сборки мусора в хранилище.
+type LogoKeyRepository interface {
+	// ListAll возвращает все непустые logo_key из users, teams, sports и tournaments.
+	ListAll(ctx context.Context) ([]string, error)
+}
+
+type postgresLogoKeyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLogoKeyRepository(db *sql.DB) LogoKeyRepository {
+	return &postgresLogoKeyRepository{db: db}
+}
+
+func (r *postgresLogoKeyRepository) ListAll(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT logo_key FROM users WHERE logo_key IS NOT NULL AND logo_key <> ''
+		UNION
+		SELECT logo_key FROM teams WHERE logo_key IS NOT NULL AND logo_key <> ''
+		UNION
+		SELECT logo_key FROM sports WHERE logo_key IS NOT NULL AND logo_key <> ''
+		UNION
+		SELECT logo_key FROM tournaments WHERE logo_key IS NOT NULL AND logo_key <> ''`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logo keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan logo key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logo keys: %w", err)
+	}
+	return keys, nil
+}
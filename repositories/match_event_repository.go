@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrMatchEventNotFound      = errors.New("match event not found")
+	ErrMatchEventPlayerInvalid = errors.New("match event player does not exist")
+)
+
+type MatchEventRepository interface {
+	Create(ctx context.Context, exec SQLExecutor, event *models.MatchEvent) error
+	GetByID(ctx context.Context, id int) (*models.MatchEvent, error)
+	ListByMatch(ctx context.Context, matchType models.RatingMatchType, matchID int) ([]models.MatchEvent, error)
+	ListByTournament(ctx context.Context, tournamentID int, matchType models.RatingMatchType) ([]models.MatchEvent, error)
+	Delete(ctx context.Context, exec SQLExecutor, id int) error
+	// CountGoals возвращает число голов матча по сторонам: participant_id -> голы.
+	CountGoals(ctx context.Context, exec SQLExecutor, matchType models.RatingMatchType, matchID int) (map[int]int, error)
+}
+
+type postgresMatchEventRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMatchEventRepository(db *sql.DB) MatchEventRepository {
+	return &postgresMatchEventRepository{db: db}
+}
+
+func (r *postgresMatchEventRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const matchEventColumns = `
+		id, tournament_id, match_type, match_id, event_type, participant_id,
+		player_user_id, related_user_id, minute, period, card, note, created_by, created_at`
+
+// matchEventOrder — хронология: по периоду, затем по минуте; события без минуты — в порядке ввода.
+const matchEventOrder = `COALESCE(period, 0) ASC, minute ASC NULLS LAST, id ASC`
+
+func (r *postgresMatchEventRepository) Create(ctx context.Context, exec SQLExecutor, event *models.MatchEvent) error {
+	executor := r.getExecutor(exec)
+	query := `
+		INSERT INTO match_events
+			(tournament_id, match_type, match_id, event_type, participant_id,
+			 player_user_id, related_user_id, minute, period, card, note, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at`
+
+	err := executor.QueryRowContext(ctx, query,
+		event.TournamentID, event.MatchType, event.MatchID, event.Type, event.ParticipantID,
+		event.PlayerUserID, event.RelatedUserID, event.Minute, event.Period, event.Card, event.Note, event.CreatedBy,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			switch pqErr.Constraint {
+			case "match_events_player_user_id_fkey", "match_events_related_user_id_fkey":
+				return ErrMatchEventPlayerInvalid
+			}
+		}
+		return fmt.Errorf("failed to create match event: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresMatchEventRepository) GetByID(ctx context.Context, id int) (*models.MatchEvent, error) {
+	query := `SELECT ` + matchEventColumns + ` FROM match_events WHERE id = $1`
+	event, err := scanMatchEvent(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMatchEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get match event %d: %w", id, err)
+	}
+	return event, nil
+}
+
+func (r *postgresMatchEventRepository) ListByMatch(ctx context.Context, matchType models.RatingMatchType, matchID int) ([]models.MatchEvent, error) {
+	query := `SELECT ` + matchEventColumns + `
+		FROM match_events
+		WHERE match_type = $1 AND match_id = $2
+		ORDER BY ` + matchEventOrder
+	return r.list(ctx, query, matchType, matchID)
+}
+
+func (r *postgresMatchEventRepository) ListByTournament(ctx context.Context, tournamentID int, matchType models.RatingMatchType) ([]models.MatchEvent, error) {
+	query := `SELECT ` + matchEventColumns + `
+		FROM match_events
+		WHERE tournament_id = $1 AND match_type = $2
+		ORDER BY match_id ASC, ` + matchEventOrder
+	return r.list(ctx, query, tournamentID, matchType)
+}
+
+func (r *postgresMatchEventRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.MatchEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list match events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]models.MatchEvent, 0)
+	for rows.Next() {
+		event, scanErr := scanMatchEvent(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to scan match event: %w", scanErr)
+		}
+		events = append(events, *event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *postgresMatchEventRepository) Delete(ctx context.Context, exec SQLExecutor, id int) error {
+	executor := r.getExecutor(exec)
+	result, err := executor.ExecContext(ctx, `DELETE FROM match_events WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete match event %d: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrMatchEventNotFound)
+}
+
+func (r *postgresMatchEventRepository) CountGoals(ctx context.Context, exec SQLExecutor, matchType models.RatingMatchType, matchID int) (map[int]int, error) {
+	executor := r.getExecutor(exec)
+	query := `
+		SELECT participant_id, COUNT(*)
+		FROM match_events
+		WHERE match_type = $1 AND match_id = $2 AND event_type = $3
+		GROUP BY participant_id`
+
+	rows, err := executor.QueryContext(ctx, query, matchType, matchID, models.MatchEventGoal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count goals for %s match %d: %w", matchType, matchID, err)
+	}
+	defer rows.Close()
+
+	goals := make(map[int]int)
+	for rows.Next() {
+		var participantID, count int
+		if err := rows.Scan(&participantID, &count); err != nil {
+			return nil, err
+		}
+		goals[participantID] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func scanMatchEvent(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.MatchEvent, error) {
+	var event models.MatchEvent
+	err := row.Scan(
+		&event.ID, &event.TournamentID, &event.MatchType, &event.MatchID, &event.Type, &event.ParticipantID,
+		&event.PlayerUserID, &event.RelatedUserID, &event.Minute, &event.Period, &event.Card, &event.Note,
+		&event.CreatedBy, &event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+type MatchReminderRepository interface {
+	// ListDue возвращает неотправленные напоминания о запланированных матчах активных турниров,
+	// начинающихся в интервале (now, now+lead]. Для командных матчей — по каждому игроку состава.
+	ListDue(ctx context.Context, exec SQLExecutor, now time.Time, lead time.Duration) ([]models.MatchReminder, error)
+	// MarkSent фиксирует отправку; false — напоминание уже было отправлено.
+	MarkSent(ctx context.Context, exec SQLExecutor, reminder models.MatchReminder) (bool, error)
+}
+
+type postgresMatchReminderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMatchReminderRepository(db *sql.DB) MatchReminderRepository {
+	return &postgresMatchReminderRepository{db: db}
+}
+
+func (r *postgresMatchReminderRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+func (r *postgresMatchReminderRepository) ListDue(ctx context.Context, exec SQLExecutor, now time.Time, lead time.Duration) ([]models.MatchReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT 'solo' AS match_type, m.id AS match_id, m.tournament_id, m.match_time, p.user_id
+			FROM solo_matches m
+			JOIN participants p ON p.id IN (m.p1_participant_id, m.p2_participant_id)
+			WHERE m.status = 'scheduled' AND m.match_time > $1 AND m.match_time <= $2
+			  AND m.p1_participant_id IS NOT NULL AND m.p2_participant_id IS NOT NULL
+			  AND p.user_id IS NOT NULL
+			UNION ALL
+			SELECT 'team', m.id, m.tournament_id, m.match_time, u.id
+			FROM team_matches m
+			JOIN participants p ON p.id IN (m.t1_participant_id, m.t2_participant_id)
+			JOIN users u ON u.team_id = p.team_id
+			WHERE m.status = 'scheduled' AND m.match_time > $1 AND m.match_time <= $2
+			  AND m.t1_participant_id IS NOT NULL AND m.t2_participant_id IS NOT NULL
+		)
+		SELECT d.match_type, d.match_id, d.tournament_id, t.name, d.match_time, d.user_id
+		FROM due d
+		JOIN tournaments t ON t.id = d.tournament_id AND t.status = 'active'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM match_reminders mr
+			WHERE mr.match_type = d.match_type AND mr.match_id = d.match_id
+			  AND mr.user_id = d.user_id AND mr.match_time = d.match_time
+		)
+		ORDER BY d.match_time, d.match_type, d.match_id, d.user_id`
+
+	rows, err := r.getExecutor(exec).QueryContext(ctx, query, now, now.Add(lead))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due match reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := make([]models.MatchReminder, 0)
+	for rows.Next() {
+		var m models.MatchReminder
+		if err := rows.Scan(&m.MatchType, &m.MatchID, &m.TournamentID, &m.TournamentName, &m.MatchTime, &m.UserID); err != nil {
+			return nil, fmt.Errorf("failed to scan match reminder: %w", err)
+		}
+		reminders = append(reminders, m)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *postgresMatchReminderRepository) MarkSent(ctx context.Context, exec SQLExecutor, reminder models.MatchReminder) (bool, error) {
+	query := `
+		INSERT INTO match_reminders (match_type, match_id, user_id, match_time)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, reminder.MatchType, reminder.MatchID, reminder.UserID, reminder.MatchTime)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark reminder for %s match %d, user %d: %w", reminder.MatchType, reminder.MatchID, reminder.UserID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository interface {
+	Create(ctx context.Context, exec SQLExecutor, notification *models.Notification) error
+	ListByUser(ctx context.Context, userID int, filter models.NotificationFilter) ([]*models.Notification, int, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID int, id int64) (*models.Notification, error)
+	MarkAllRead(ctx context.Context, userID int) (int64, error)
+
+	// GetPreferences возвращает только сохранённые настройки; для остальных типов действуют значения по умолчанию.
+	GetPreferences(ctx context.Context, userID int) ([]models.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, exec SQLExecutor, userID int, prefs []models.NotificationPreference) error
+}
+
+type postgresNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNotificationRepository(db *sql.DB) NotificationRepository {
+	return &postgresNotificationRepository{db: db}
+}
+
+func (r *postgresNotificationRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const notificationColumns = `id, user_id, type, title, body, data, read_at, created_at`
+
+func (r *postgresNotificationRepository) Create(ctx context.Context, exec SQLExecutor, notification *models.Notification) error {
+	data := notification.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, title, body, data)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	err = r.getExecutor(exec).QueryRowContext(ctx, query,
+		notification.UserID, notification.Type, notification.Title, notification.Body, dataJSON,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to create notification for user %d: %w", notification.UserID, err)
+	}
+	notification.Data = data
+	return nil
+}
+
+func (r *postgresNotificationRepository) ListByUser(ctx context.Context, userID int, filter models.NotificationFilter) ([]*models.Notification, int, error) {
+	where := ` WHERE user_id = $1`
+	if filter.UnreadOnly {
+		where += ` AND read_at IS NULL`
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications`+where, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications for user %d: %w", userID, err)
+	}
+
+	query := `SELECT ` + notificationColumns + ` FROM notifications` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, userID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, total, rows.Err()
+}
+
+func (r *postgresNotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications for user %d: %w", userID, err)
+	}
+	return count, nil
+}
+
+func (r *postgresNotificationRepository) MarkRead(ctx context.Context, userID int, id int64) (*models.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + notificationColumns
+	notification, err := scanNotification(r.db.QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to mark notification %d as read: %w", id, err)
+	}
+	return notification, nil
+}
+
+func (r *postgresNotificationRepository) MarkAllRead(ctx context.Context, userID int) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read for user %d: %w", userID, err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *postgresNotificationRepository) GetPreferences(ctx context.Context, userID int) ([]models.NotificationPreference, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT type, email_enabled, in_app_enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	prefs := make([]models.NotificationPreference, 0)
+	for rows.Next() {
+		var pref models.NotificationPreference
+		if err := rows.Scan(&pref.Type, &pref.Email, &pref.InApp); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs, rows.Err()
+}
+
+func (r *postgresNotificationRepository) UpsertPreferences(ctx context.Context, exec SQLExecutor, userID int, prefs []models.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, type, email_enabled, in_app_enabled)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, type) DO UPDATE
+		SET email_enabled = EXCLUDED.email_enabled,
+		    in_app_enabled = EXCLUDED.in_app_enabled,
+		    updated_at = CURRENT_TIMESTAMP`
+	for _, pref := range prefs {
+		if _, err := r.getExecutor(exec).ExecContext(ctx, query, userID, pref.Type, pref.Email, pref.InApp); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to save %s notification preference for user %d: %w", pref.Type, userID, err)
+		}
+	}
+	return nil
+}
+
+func scanNotification(rowScanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Notification, error) {
+	var notification models.Notification
+	var dataJSON []byte
+	var readAt sql.NullTime
+	if err := rowScanner.Scan(
+		&notification.ID, &notification.UserID, &notification.Type, &notification.Title, &notification.Body,
+		&dataJSON, &readAt, &notification.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(dataJSON) > 0 {
+		if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data of notification %d: %w", notification.ID, err)
+		}
+	}
+	if readAt.Valid {
+		notification.ReadAt = &readAt.Time
+		notification.Read = true
+	}
+	return &notification, nil
+}
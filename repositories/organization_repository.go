@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOrganizationNotFound          = errors.New("organization not found")
+	ErrOrganizationNameConflict      = errors.New("organization name conflict")
+	ErrOrganizationMemberNotFound    = errors.New("organization member not found")
+	ErrOrganizationMemberConflict    = errors.New("user is already a member of this organization")
+	ErrOrganizationMemberUserInvalid = errors.New("organization member user invalid")
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, exec SQLExecutor, org *models.Organization) error
+	GetByID(ctx context.Context, id int) (*models.Organization, error)
+	List(ctx context.Context, limit, offset int) ([]models.Organization, error)
+	ListByUser(ctx context.Context, userID int) ([]models.Organization, error)
+	Update(ctx context.Context, org *models.Organization) error
+	Delete(ctx context.Context, id int) error
+
+	AddMember(ctx context.Context, exec SQLExecutor, member *models.OrganizationMember) error
+	GetMember(ctx context.Context, organizationID, userID int) (*models.OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationID int) ([]models.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, organizationID, userID int, role models.OrganizationRole) error
+	RemoveMember(ctx context.Context, organizationID, userID int) error
+}
+
+type postgresOrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOrganizationRepository(db *sql.DB) OrganizationRepository {
+	return &postgresOrganizationRepository{db: db}
+}
+
+func (r *postgresOrganizationRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+func (r *postgresOrganizationRepository) Create(ctx context.Context, exec SQLExecutor, org *models.Organization) error {
+	query := `
+		INSERT INTO organizations (name, description)
+		VALUES ($1, $2)
+		RETURNING id, created_at`
+
+	err := r.getExecutor(exec).QueryRowContext(ctx, query, org.Name, org.Description).Scan(&org.ID, &org.CreatedAt)
+	return r.handleOrganizationError(err)
+}
+
+func (r *postgresOrganizationRepository) GetByID(ctx context.Context, id int) (*models.Organization, error) {
+	query := `SELECT id, name, description, created_at FROM organizations WHERE id = $1`
+
+	var org models.Organization
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&org.ID, &org.Name, &org.Description, &org.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *postgresOrganizationRepository) List(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	query := `
+		SELECT id, name, description, created_at
+		FROM organizations
+		ORDER BY name ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrganizations(rows)
+}
+
+func (r *postgresOrganizationRepository) ListByUser(ctx context.Context, userID int) ([]models.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.description, o.created_at
+		FROM organizations o
+		JOIN organization_members om ON om.organization_id = o.id
+		WHERE om.user_id = $1
+		ORDER BY o.name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrganizations(rows)
+}
+
+func (r *postgresOrganizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	query := `UPDATE organizations SET name = $1, description = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, org.Name, org.Description, org.ID)
+	if err != nil {
+		return r.handleOrganizationError(err)
+	}
+	return checkAffectedRows(result, ErrOrganizationNotFound)
+}
+
+func (r *postgresOrganizationRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM organizations WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(result, ErrOrganizationNotFound)
+}
+
+func (r *postgresOrganizationRepository) AddMember(ctx context.Context, exec SQLExecutor, member *models.OrganizationMember) error {
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+
+	err := r.getExecutor(exec).QueryRowContext(ctx, query, member.OrganizationID, member.UserID, member.Role).Scan(&member.CreatedAt)
+	return r.handleOrganizationError(err)
+}
+
+func (r *postgresOrganizationRepository) GetMember(ctx context.Context, organizationID, userID int) (*models.OrganizationMember, error) {
+	query := `
+		SELECT organization_id, user_id, role, created_at
+		FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2`
+
+	var m models.OrganizationMember
+	err := r.db.QueryRowContext(ctx, query, organizationID, userID).Scan(&m.OrganizationID, &m.UserID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrganizationMemberNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *postgresOrganizationRepository) ListMembers(ctx context.Context, organizationID int) ([]models.OrganizationMember, error) {
+	query := `
+		SELECT om.organization_id, om.user_id, om.role, om.created_at,
+		       u.id, u.first_name, u.last_name, u.nickname, u.role, u.logo_key
+		FROM organization_members om
+		JOIN users u ON u.id = om.user_id
+		WHERE om.organization_id = $1
+		ORDER BY om.role ASC, om.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]models.OrganizationMember, 0)
+	for rows.Next() {
+		var m models.OrganizationMember
+		var u models.User
+		if scanErr := rows.Scan(
+			&m.OrganizationID, &m.UserID, &m.Role, &m.CreatedAt,
+			&u.ID, &u.FirstName, &u.LastName, &u.Nickname, &u.Role, &u.LogoKey,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		m.User = &u
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *postgresOrganizationRepository) UpdateMemberRole(ctx context.Context, organizationID, userID int, role models.OrganizationRole) error {
+	query := `UPDATE organization_members SET role = $1 WHERE organization_id = $2 AND user_id = $3`
+	result, err := r.db.ExecContext(ctx, query, role, organizationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update organization member role: %w", err)
+	}
+	return checkAffectedRows(result, ErrOrganizationMemberNotFound)
+}
+
+func (r *postgresOrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID int) error {
+	query := `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(result, ErrOrganizationMemberNotFound)
+}
+
+func (r *postgresOrganizationRepository) handleOrganizationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			switch pqErr.Constraint {
+			case "organizations_name_key":
+				return ErrOrganizationNameConflict
+			case "organization_members_pkey":
+				return ErrOrganizationMemberConflict
+			}
+		case "23503":
+			switch pqErr.Constraint {
+			case "fk_organization_members_org":
+				return ErrOrganizationNotFound
+			case "fk_organization_members_user":
+				return ErrOrganizationMemberUserInvalid
+			}
+		}
+	}
+	return err
+}
+
+func scanOrganizations(rows *sql.Rows) ([]models.Organization, error) {
+	orgs := make([]models.Organization, 0)
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Description, &org.CreatedAt); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
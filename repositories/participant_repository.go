@@ -22,7 +22,7 @@ var (
 
 type ParticipantRepository interface {
 	Create(ctx context.Context, p *models.Participant) error
-	UpdateStatus(ctx context.Context, id int, status models.ParticipantStatus) error
+	UpdateStatus(ctx context.Context, exec SQLExecutor, id int, status models.ParticipantStatus) error
 	FindByID(ctx context.Context, id int) (*models.Participant, error)
 	FindByUserAndTournament(ctx context.Context, userID, tournamentID int) (*models.Participant, error)
 	FindByTeamAndTournament(ctx context.Context, teamID, tournamentID int) (*models.Participant, error)
@@ -39,6 +39,13 @@ func NewPostgresParticipantRepository(db *sql.DB) ParticipantRepository {
 	return &postgresParticipantRepository{db: db}
 }
 
+func (r *postgresParticipantRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
 func (r *postgresParticipantRepository) Create(ctx context.Context, p *models.Participant) error {
 	query := `
 		INSERT INTO participants (user_id, team_id, tournament_id, status)
@@ -80,9 +87,9 @@ func (r *postgresParticipantRepository) Create(ctx context.Context, p *models.Pa
 	return nil
 }
 
-func (r *postgresParticipantRepository) UpdateStatus(ctx context.Context, id int, status models.ParticipantStatus) error {
+func (r *postgresParticipantRepository) UpdateStatus(ctx context.Context, exec SQLExecutor, id int, status models.ParticipantStatus) error {
 	query := `UPDATE participants SET status = $1 WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, status, id)
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, status, id)
 	if err != nil {
 		return fmt.Errorf("failed to update participant status: %w", err)
 	}
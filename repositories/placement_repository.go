@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+type TournamentPlacementRepository interface {
+	ReplaceForTournament(ctx context.Context, exec SQLExecutor, tournamentID int, placements []models.TournamentPlacement) error
+	ListByTournament(ctx context.Context, tournamentID int) ([]models.TournamentPlacement, error)
+	ListByUser(ctx context.Context, userID int) ([]models.TournamentPlacement, error)
+	ListByTeam(ctx context.Context, teamID int) ([]models.TournamentPlacement, error)
+}
+
+type postgresTournamentPlacementRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTournamentPlacementRepository(db *sql.DB) TournamentPlacementRepository {
+	return &postgresTournamentPlacementRepository{db: db}
+}
+
+func (r *postgresTournamentPlacementRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+// ReplaceForTournament перезаписывает места турнира целиком (повторная финализация идемпотентна).
+func (r *postgresTournamentPlacementRepository) ReplaceForTournament(ctx context.Context, exec SQLExecutor, tournamentID int, placements []models.TournamentPlacement) error {
+	executor := r.getExecutor(exec)
+	if _, err := executor.ExecContext(ctx, `DELETE FROM tournament_placements WHERE tournament_id = $1`, tournamentID); err != nil {
+		return fmt.Errorf("failed to clear placements for tournament %d: %w", tournamentID, err)
+	}
+
+	query := `
+		INSERT INTO tournament_placements (tournament_id, participant_id, placement)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+	for i := range placements {
+		placements[i].TournamentID = tournamentID
+		if err := executor.QueryRowContext(ctx, query, tournamentID, placements[i].ParticipantID, placements[i].Placement).Scan(&placements[i].CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert placement for participant %d: %w", placements[i].ParticipantID, err)
+		}
+	}
+	return nil
+}
+
+func (r *postgresTournamentPlacementRepository) ListByTournament(ctx context.Context, tournamentID int) ([]models.TournamentPlacement, error) {
+	return r.list(ctx, `tp.tournament_id = $1`, tournamentID, `tp.placement ASC, tp.participant_id ASC`)
+}
+
+func (r *postgresTournamentPlacementRepository) ListByUser(ctx context.Context, userID int) ([]models.TournamentPlacement, error) {
+	return r.list(ctx, `p.user_id = $1`, userID, `t.end_date DESC, t.id DESC`)
+}
+
+func (r *postgresTournamentPlacementRepository) ListByTeam(ctx context.Context, teamID int) ([]models.TournamentPlacement, error) {
+	return r.list(ctx, `p.team_id = $1`, teamID, `t.end_date DESC, t.id DESC`)
+}
+
+func (r *postgresTournamentPlacementRepository) list(ctx context.Context, where string, arg int, orderBy string) ([]models.TournamentPlacement, error) {
+	query := `
+		SELECT tp.tournament_id, tp.participant_id, tp.placement, tp.created_at,
+		       p.user_id, p.team_id, t.name, t.sport_id, t.end_date
+		FROM tournament_placements tp
+		JOIN participants p ON p.id = tp.participant_id
+		JOIN tournaments t ON t.id = tp.tournament_id
+		WHERE ` + where + `
+		ORDER BY ` + orderBy
+
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	placements := make([]models.TournamentPlacement, 0)
+	for rows.Next() {
+		var tp models.TournamentPlacement
+		var endDate sql.NullTime
+		if scanErr := rows.Scan(
+			&tp.TournamentID, &tp.ParticipantID, &tp.Placement, &tp.CreatedAt,
+			&tp.UserID, &tp.TeamID, &tp.TournamentName, &tp.SportID, &endDate,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		if endDate.Valid {
+			tp.EndDate = &endDate.Time
+		}
+		placements = append(placements, tp)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return placements, nil
+}
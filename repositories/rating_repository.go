@@ -0,0 +1,415 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRatingNotFound = errors.New("rating not found")
+)
+
+// ratingLockNamespace — первый ключ pg_advisory_xact_lock, второй — sport_id.
+// Блокировка упорядочивает пересчёт рейтинга внутри одного вида спорта.
+const ratingLockNamespace = 7301
+
+type ListRatingsFilter struct {
+	SportID    int
+	Teams      bool // false — рейтинги игроков, true — рейтинги команд
+	MinMatches int
+	Limit      int
+	Offset     int
+}
+
+type RatingRepository interface {
+	LockSport(ctx context.Context, exec SQLExecutor, sportID int) error
+	GetOrCreate(ctx context.Context, exec SQLExecutor, sportID int, userID, teamID *int) (*models.Rating, error)
+	Update(ctx context.Context, exec SQLExecutor, rating *models.Rating) error
+	AddHistory(ctx context.Context, exec SQLExecutor, entry *models.RatingHistoryEntry) error
+	// HasLaterRatedMatch сообщает, учтён ли в рейтингах вида спорта матч, завершённый позже match.
+	HasLaterRatedMatch(ctx context.Context, exec SQLExecutor, match *models.RatedMatch) (bool, error)
+	DeleteBySport(ctx context.Context, exec SQLExecutor, sportID int) error
+
+	GetRatedMatch(ctx context.Context, exec SQLExecutor, matchType models.RatingMatchType, matchID int) (*models.RatedMatch, error)
+	ListRatedMatches(ctx context.Context, exec SQLExecutor, sportID int) ([]models.RatedMatch, error)
+
+	ListByEntity(ctx context.Context, userID, teamID *int) ([]models.Rating, error)
+	ListLeaderboard(ctx context.Context, filter ListRatingsFilter) ([]models.Rating, error)
+	ListHistory(ctx context.Context, sportID int, userID, teamID *int, limit, offset int) ([]models.RatingHistoryEntry, error)
+	MapBySport(ctx context.Context, sportID int, userIDs, teamIDs []int) (map[int]float64, map[int]float64, error)
+}
+
+type postgresRatingRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRatingRepository(db *sql.DB) RatingRepository {
+	return &postgresRatingRepository{db: db}
+}
+
+func (r *postgresRatingRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const ratingColumns = `id, sport_id, user_id, team_id, rating, deviation, volatility, matches_played, wins, losses, draws, created_at, updated_at`
+
+func scanRating(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.Rating, error) {
+	var rt models.Rating
+	dest := []interface{}{
+		&rt.ID, &rt.SportID, &rt.UserID, &rt.TeamID, &rt.Rating, &rt.Deviation, &rt.Volatility,
+		&rt.MatchesPlayed, &rt.Wins, &rt.Losses, &rt.Draws, &rt.CreatedAt, &rt.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+func (r *postgresRatingRepository) LockSport(ctx context.Context, exec SQLExecutor, sportID int) error {
+	_, err := r.getExecutor(exec).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, ratingLockNamespace, sportID)
+	if err != nil {
+		return fmt.Errorf("failed to acquire rating lock for sport %d: %w", sportID, err)
+	}
+	return nil
+}
+
+func (r *postgresRatingRepository) GetOrCreate(ctx context.Context, exec SQLExecutor, sportID int, userID, teamID *int) (*models.Rating, error) {
+	executor := r.getExecutor(exec)
+
+	var insertQuery string
+	if userID != nil {
+		insertQuery = `INSERT INTO ratings (sport_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (sport_id, user_id) WHERE user_id IS NOT NULL DO NOTHING`
+	} else {
+		insertQuery = `INSERT INTO ratings (sport_id, team_id) VALUES ($1, $2)
+			ON CONFLICT (sport_id, team_id) WHERE team_id IS NOT NULL DO NOTHING`
+	}
+	entityID := derefEntityID(userID, teamID)
+	if _, err := executor.ExecContext(ctx, insertQuery, sportID, entityID); err != nil {
+		return nil, fmt.Errorf("failed to ensure rating row: %w", err)
+	}
+
+	query := `SELECT ` + ratingColumns + ` FROM ratings WHERE sport_id = $1 AND `
+	if userID != nil {
+		query += `user_id = $2`
+	} else {
+		query += `team_id = $2`
+	}
+	rating, err := scanRating(executor.QueryRowContext(ctx, query, sportID, entityID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRatingNotFound
+		}
+		return nil, err
+	}
+	return rating, nil
+}
+
+func (r *postgresRatingRepository) Update(ctx context.Context, exec SQLExecutor, rating *models.Rating) error {
+	query := `
+		UPDATE ratings SET
+			rating = $1, deviation = $2, volatility = $3,
+			matches_played = $4, wins = $5, losses = $6, draws = $7,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING updated_at`
+
+	err := r.getExecutor(exec).QueryRowContext(ctx, query,
+		rating.Rating, rating.Deviation, rating.Volatility,
+		rating.MatchesPlayed, rating.Wins, rating.Losses, rating.Draws,
+		rating.ID,
+	).Scan(&rating.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRatingNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *postgresRatingRepository) AddHistory(ctx context.Context, exec SQLExecutor, entry *models.RatingHistoryEntry) error {
+	query := `
+		INSERT INTO rating_history (
+			rating_id, match_type, match_id, tournament_id, opponent_rating_id, score,
+			rating_before, rating_after, deviation_before, deviation_after
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at`
+
+	return r.getExecutor(exec).QueryRowContext(ctx, query,
+		entry.RatingID, entry.MatchType, entry.MatchID, entry.TournamentID, entry.OpponentRatingID, entry.Score,
+		entry.RatingBefore, entry.RatingAfter, entry.DeviationBefore, entry.DeviationAfter,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *postgresRatingRepository) HasLaterRatedMatch(ctx context.Context, exec SQLExecutor, match *models.RatedMatch) (bool, error) {
+	if match.CompletedAt == nil {
+		return false, nil
+	}
+	// Порядок тот же, что в ListRatedMatches: completed_at, match_type, id.
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM rating_history h
+			JOIN ratings r ON r.id = h.rating_id
+			LEFT JOIN solo_matches sm ON h.match_type = 'solo' AND sm.id = h.match_id
+			LEFT JOIN team_matches tm ON h.match_type = 'team' AND tm.id = h.match_id
+			WHERE r.sport_id = $1
+			  AND (COALESCE(sm.completed_at, tm.completed_at), h.match_type, h.match_id) > ($2::timestamptz, $3::text, $4::int)
+		)`
+	var exists bool
+	if err := r.getExecutor(exec).QueryRowContext(ctx, query, match.SportID, *match.CompletedAt, match.MatchType, match.MatchID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// DeleteBySport удаляет рейтинги вида спорта вместе с историей (ON DELETE CASCADE).
+func (r *postgresRatingRepository) DeleteBySport(ctx context.Context, exec SQLExecutor, sportID int) error {
+	_, err := r.getExecutor(exec).ExecContext(ctx, `DELETE FROM ratings WHERE sport_id = $1`, sportID)
+	return err
+}
+
+// ratedMatchesQuery строит выборку завершённых матчей с участниками для solo_matches или team_matches.
+func ratedMatchesQuery(matchType models.RatingMatchType) string {
+	table, side := "solo_matches", "p"
+	if matchType == models.RatingMatchTeam {
+		table, side = "team_matches", "t"
+	}
+	return fmt.Sprintf(`
+		SELECT m.id, m.tournament_id, t.sport_id,
+		       m.%[2]s1_participant_id AS p1_participant_id, m.%[2]s2_participant_id AS p2_participant_id,
+		       p1.user_id AS p1_user_id, p1.team_id AS p1_team_id, p2.user_id AS p2_user_id, p2.team_id AS p2_team_id,
+		       m.winner_participant_id, m.match_time, m.completed_at
+		FROM %[1]s m
+		JOIN tournaments t ON t.id = m.tournament_id
+		JOIN participants p1 ON p1.id = m.%[2]s1_participant_id
+		JOIN participants p2 ON p2.id = m.%[2]s2_participant_id
+		WHERE m.status = '%[3]s'`, table, side, models.MatchStatusCompleted)
+}
+
+func scanRatedMatch(row interface{ Scan(...interface{}) error }, matchType models.RatingMatchType) (*models.RatedMatch, error) {
+	rm := models.RatedMatch{MatchType: matchType}
+	if err := row.Scan(
+		&rm.MatchID, &rm.TournamentID, &rm.SportID,
+		&rm.P1ParticipantID, &rm.P2ParticipantID,
+		&rm.P1UserID, &rm.P1TeamID, &rm.P2UserID, &rm.P2TeamID,
+		&rm.WinnerParticipantID, &rm.MatchTime, &rm.CompletedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (r *postgresRatingRepository) GetRatedMatch(ctx context.Context, exec SQLExecutor, matchType models.RatingMatchType, matchID int) (*models.RatedMatch, error) {
+	query := ratedMatchesQuery(matchType) + ` AND m.id = $1`
+	rm, err := scanRatedMatch(r.getExecutor(exec).QueryRowContext(ctx, query, matchID), matchType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if matchType == models.RatingMatchTeam {
+				return nil, ErrTeamMatchNotFound
+			}
+			return nil, ErrSoloMatchNotFound
+		}
+		return nil, err
+	}
+	return rm, nil
+}
+
+// ListRatedMatches возвращает все завершённые матчи вида спорта в порядке завершения —
+// в том же порядке, в котором их применяет ApplyMatchResult.
+func (r *postgresRatingRepository) ListRatedMatches(ctx context.Context, exec SQLExecutor, sportID int) ([]models.RatedMatch, error) {
+	query := `
+		SELECT * FROM (
+			SELECT 'solo' AS match_type, q.* FROM (` + ratedMatchesQuery(models.RatingMatchSolo) + ` AND t.sport_id = $1) q
+			UNION ALL
+			SELECT 'team' AS match_type, q.* FROM (` + ratedMatchesQuery(models.RatingMatchTeam) + ` AND t.sport_id = $1) q
+		) all_matches
+		ORDER BY completed_at ASC NULLS FIRST, match_type ASC, id ASC`
+
+	rows, err := r.getExecutor(exec).QueryContext(ctx, query, sportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]models.RatedMatch, 0)
+	for rows.Next() {
+		var rm models.RatedMatch
+		if scanErr := rows.Scan(
+			&rm.MatchType, &rm.MatchID, &rm.TournamentID, &rm.SportID,
+			&rm.P1ParticipantID, &rm.P2ParticipantID,
+			&rm.P1UserID, &rm.P1TeamID, &rm.P2UserID, &rm.P2TeamID,
+			&rm.WinnerParticipantID, &rm.MatchTime, &rm.CompletedAt,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		matches = append(matches, rm)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (r *postgresRatingRepository) ListByEntity(ctx context.Context, userID, teamID *int) ([]models.Rating, error) {
+	query := `SELECT ` + ratingColumns + ` FROM ratings WHERE `
+	if userID != nil {
+		query += `user_id = $1`
+	} else {
+		query += `team_id = $1`
+	}
+	query += ` ORDER BY sport_id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, derefEntityID(userID, teamID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := make([]models.Rating, 0)
+	for rows.Next() {
+		rt, scanErr := scanRating(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		ratings = append(ratings, *rt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *postgresRatingRepository) ListLeaderboard(ctx context.Context, filter ListRatingsFilter) ([]models.Rating, error) {
+	var query string
+	if filter.Teams {
+		query = `
+			SELECT r.id, r.sport_id, r.user_id, r.team_id, r.rating, r.deviation, r.volatility,
+			       r.matches_played, r.wins, r.losses, r.draws, r.created_at, r.updated_at,
+			       t.name, t.logo_key
+			FROM ratings r
+			JOIN teams t ON t.id = r.team_id
+			WHERE r.sport_id = $1 AND r.team_id IS NOT NULL AND r.matches_played >= $2`
+	} else {
+		query = `
+			SELECT r.id, r.sport_id, r.user_id, r.team_id, r.rating, r.deviation, r.volatility,
+			       r.matches_played, r.wins, r.losses, r.draws, r.created_at, r.updated_at,
+			       COALESCE(NULLIF(u.nickname, ''), u.first_name || ' ' || u.last_name), u.logo_key
+			FROM ratings r
+			JOIN users u ON u.id = r.user_id
+			WHERE r.sport_id = $1 AND r.user_id IS NOT NULL AND r.matches_played >= $2`
+	}
+	query += ` ORDER BY r.rating DESC, r.deviation ASC, r.id ASC LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.SportID, filter.MinMatches, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := make([]models.Rating, 0)
+	for rows.Next() {
+		var name string
+		var logoKey *string
+		rt, scanErr := scanRating(rows, &name, &logoKey)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		rt.Name = name
+		rt.LogoKey = logoKey
+		ratings = append(ratings, *rt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *postgresRatingRepository) ListHistory(ctx context.Context, sportID int, userID, teamID *int, limit, offset int) ([]models.RatingHistoryEntry, error) {
+	query := `
+		SELECT h.id, h.rating_id, h.match_type, h.match_id, h.tournament_id, h.opponent_rating_id, h.score,
+		       h.rating_before, h.rating_after, h.deviation_before, h.deviation_after, h.created_at
+		FROM rating_history h
+		JOIN ratings r ON r.id = h.rating_id
+		WHERE r.sport_id = $1 AND `
+	if userID != nil {
+		query += `r.user_id = $2`
+	} else {
+		query += `r.team_id = $2`
+	}
+	query += ` ORDER BY h.id DESC LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, sportID, derefEntityID(userID, teamID), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]models.RatingHistoryEntry, 0)
+	for rows.Next() {
+		var h models.RatingHistoryEntry
+		if scanErr := rows.Scan(
+			&h.ID, &h.RatingID, &h.MatchType, &h.MatchID, &h.TournamentID, &h.OpponentRatingID, &h.Score,
+			&h.RatingBefore, &h.RatingAfter, &h.DeviationBefore, &h.DeviationAfter, &h.CreatedAt,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		history = append(history, h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// MapBySport возвращает рейтинги игроков и команд (ключ — user_id / team_id) для посева.
+func (r *postgresRatingRepository) MapBySport(ctx context.Context, sportID int, userIDs, teamIDs []int) (map[int]float64, map[int]float64, error) {
+	query := `
+		SELECT user_id, team_id, rating
+		FROM ratings
+		WHERE sport_id = $1 AND (user_id = ANY($2) OR team_id = ANY($3))`
+
+	rows, err := r.db.QueryContext(ctx, query, sportID, pq.Array(userIDs), pq.Array(teamIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	userRatings := make(map[int]float64)
+	teamRatings := make(map[int]float64)
+	for rows.Next() {
+		var userID, teamID *int
+		var rating float64
+		if scanErr := rows.Scan(&userID, &teamID, &rating); scanErr != nil {
+			return nil, nil, scanErr
+		}
+		if userID != nil {
+			userRatings[*userID] = rating
+		} else if teamID != nil {
+			teamRatings[*teamID] = rating
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return userRatings, teamRatings, nil
+}
+
+func derefEntityID(userID, teamID *int) int {
+	if userID != nil {
+		return *userID
+	}
+	if teamID != nil {
+		return *teamID
+	}
+	return 0
+}
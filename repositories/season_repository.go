@@ -0,0 +1,347 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSeasonNotFound            = errors.New("season not found")
+	ErrSeasonNameConflict        = errors.New("season name conflict for this organizer")
+	ErrSeasonInvalidSport        = errors.New("invalid sport reference")
+	ErrSeasonTournamentConflict  = errors.New("tournament already belongs to a season")
+	ErrSeasonTournamentNotFound  = errors.New("tournament is not part of this season")
+	ErrSeasonInvalidPointsTable  = errors.New("invalid season points table")
+	ErrSeasonInvalidOrganization = errors.New("invalid organization reference")
+)
+
+type ListSeasonsFilter struct {
+	SportID        *int
+	OrganizationID *int
+	Limit          int
+	Offset         int
+}
+
+type SeasonRepository interface {
+	Create(ctx context.Context, season *models.Season) error
+	GetByID(ctx context.Context, id int) (*models.Season, error)
+	GetByTournamentID(ctx context.Context, exec SQLExecutor, tournamentID int) (*models.Season, error)
+	List(ctx context.Context, filter ListSeasonsFilter) ([]models.Season, error)
+	Update(ctx context.Context, season *models.Season) error
+	Delete(ctx context.Context, id int) error
+
+	AddTournament(ctx context.Context, seasonID, tournamentID int) error
+	RemoveTournament(ctx context.Context, exec SQLExecutor, seasonID, tournamentID int) error
+	ListTournamentIDs(ctx context.Context, seasonID int) ([]int, error)
+
+	ReplaceTournamentResults(ctx context.Context, exec SQLExecutor, seasonID, tournamentID int, results []*models.SeasonResult) error
+	GetLeaderboard(ctx context.Context, seasonID int) ([]models.SeasonLeaderboardEntry, error)
+}
+
+type postgresSeasonRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSeasonRepository(db *sql.DB) SeasonRepository {
+	return &postgresSeasonRepository{db: db}
+}
+
+func (r *postgresSeasonRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const seasonColumns = `id, name, description, sport_id, organizer_id, organization_id, points_table, participation_points, created_at`
+
+func (r *postgresSeasonRepository) scanSeason(row interface{ Scan(...interface{}) error }) (*models.Season, error) {
+	var s models.Season
+	var pointsJSON string
+	if err := row.Scan(
+		&s.ID, &s.Name, &s.Description, &s.SportID, &s.OrganizerID, &s.OrganizationID,
+		&pointsJSON, &s.ParticipationPoints, &s.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if pointsJSON != "" {
+		if err := json.Unmarshal([]byte(pointsJSON), &s.PointsTable); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSeasonInvalidPointsTable, err)
+		}
+	}
+	if s.PointsTable == nil {
+		s.PointsTable = []int{}
+	}
+	return &s, nil
+}
+
+func (r *postgresSeasonRepository) Create(ctx context.Context, season *models.Season) error {
+	pointsJSON, err := marshalPointsTable(season.PointsTable)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO seasons (name, description, sport_id, organizer_id, organization_id, points_table, participation_points)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		season.Name, season.Description, season.SportID, season.OrganizerID, season.OrganizationID,
+		pointsJSON, season.ParticipationPoints,
+	).Scan(&season.ID, &season.CreatedAt)
+	return r.handleSeasonError(err)
+}
+
+func (r *postgresSeasonRepository) GetByID(ctx context.Context, id int) (*models.Season, error) {
+	query := `SELECT ` + seasonColumns + ` FROM seasons WHERE id = $1`
+	season, err := r.scanSeason(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSeasonNotFound
+		}
+		return nil, err
+	}
+	return season, nil
+}
+
+func (r *postgresSeasonRepository) GetByTournamentID(ctx context.Context, exec SQLExecutor, tournamentID int) (*models.Season, error) {
+	query := `
+		SELECT s.id, s.name, s.description, s.sport_id, s.organizer_id, s.organization_id,
+		       s.points_table, s.participation_points, s.created_at
+		FROM seasons s
+		JOIN season_tournaments st ON st.season_id = s.id
+		WHERE st.tournament_id = $1`
+	season, err := r.scanSeason(r.getExecutor(exec).QueryRowContext(ctx, query, tournamentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSeasonNotFound
+		}
+		return nil, err
+	}
+	return season, nil
+}
+
+func (r *postgresSeasonRepository) List(ctx context.Context, filter ListSeasonsFilter) ([]models.Season, error) {
+	query := `SELECT ` + seasonColumns + ` FROM seasons WHERE 1=1`
+	args := []interface{}{}
+	argID := 1
+
+	if filter.SportID != nil {
+		query += fmt.Sprintf(" AND sport_id = $%d", argID)
+		args = append(args, *filter.SportID)
+		argID++
+	}
+	if filter.OrganizationID != nil {
+		query += fmt.Sprintf(" AND organization_id = $%d", argID)
+		args = append(args, *filter.OrganizationID)
+		argID++
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argID)
+		args = append(args, filter.Limit)
+		argID++
+	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argID)
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := make([]models.Season, 0)
+	for rows.Next() {
+		season, scanErr := r.scanSeason(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		seasons = append(seasons, *season)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+func (r *postgresSeasonRepository) Update(ctx context.Context, season *models.Season) error {
+	pointsJSON, err := marshalPointsTable(season.PointsTable)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE seasons SET
+			name = $1,
+			description = $2,
+			points_table = $3,
+			participation_points = $4
+		WHERE id = $5`
+
+	result, err := r.db.ExecContext(ctx, query, season.Name, season.Description, pointsJSON, season.ParticipationPoints, season.ID)
+	if err != nil {
+		return r.handleSeasonError(err)
+	}
+	return checkAffectedRows(result, ErrSeasonNotFound)
+}
+
+func (r *postgresSeasonRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM seasons WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(result, ErrSeasonNotFound)
+}
+
+func (r *postgresSeasonRepository) AddTournament(ctx context.Context, seasonID, tournamentID int) error {
+	query := `INSERT INTO season_tournaments (season_id, tournament_id) VALUES ($1, $2)`
+	_, err := r.db.ExecContext(ctx, query, seasonID, tournamentID)
+	return r.handleSeasonError(err)
+}
+
+func (r *postgresSeasonRepository) RemoveTournament(ctx context.Context, exec SQLExecutor, seasonID, tournamentID int) error {
+	executor := r.getExecutor(exec)
+	if _, err := executor.ExecContext(ctx, `DELETE FROM season_results WHERE season_id = $1 AND tournament_id = $2`, seasonID, tournamentID); err != nil {
+		return fmt.Errorf("failed to delete season results for tournament %d: %w", tournamentID, err)
+	}
+	result, err := executor.ExecContext(ctx, `DELETE FROM season_tournaments WHERE season_id = $1 AND tournament_id = $2`, seasonID, tournamentID)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(result, ErrSeasonTournamentNotFound)
+}
+
+func (r *postgresSeasonRepository) ListTournamentIDs(ctx context.Context, seasonID int) ([]int, error) {
+	query := `SELECT tournament_id FROM season_tournaments WHERE season_id = $1 ORDER BY created_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if scanErr := rows.Scan(&id); scanErr != nil {
+			return nil, scanErr
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ReplaceTournamentResults перезаписывает итоги турнира в сезоне (идемпотентно при повторной финализации).
+func (r *postgresSeasonRepository) ReplaceTournamentResults(ctx context.Context, exec SQLExecutor, seasonID, tournamentID int, results []*models.SeasonResult) error {
+	executor := r.getExecutor(exec)
+	if _, err := executor.ExecContext(ctx, `DELETE FROM season_results WHERE season_id = $1 AND tournament_id = $2`, seasonID, tournamentID); err != nil {
+		return fmt.Errorf("failed to clear season results for tournament %d: %w", tournamentID, err)
+	}
+
+	query := `
+		INSERT INTO season_results (season_id, tournament_id, participant_id, user_id, team_id, placement, points)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+	for _, res := range results {
+		res.SeasonID = seasonID
+		res.TournamentID = tournamentID
+		if err := executor.QueryRowContext(ctx, query,
+			res.SeasonID, res.TournamentID, res.ParticipantID, res.UserID, res.TeamID, res.Placement, res.Points,
+		).Scan(&res.ID, &res.CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert season result for participant %d: %w", res.ParticipantID, err)
+		}
+	}
+	return nil
+}
+
+func (r *postgresSeasonRepository) GetLeaderboard(ctx context.Context, seasonID int) ([]models.SeasonLeaderboardEntry, error) {
+	query := `
+		SELECT sr.user_id, sr.team_id,
+		       COALESCE(t.name, NULLIF(u.nickname, ''), u.first_name || ' ' || u.last_name, '') AS name,
+		       COALESCE(t.logo_key, u.logo_key) AS logo_key,
+		       SUM(sr.points) AS total_points,
+		       COUNT(*) AS tournaments_played,
+		       COUNT(*) FILTER (WHERE sr.placement = 1) AS tournament_wins,
+		       MIN(sr.placement) AS best_placement
+		FROM season_results sr
+		LEFT JOIN users u ON u.id = sr.user_id
+		LEFT JOIN teams t ON t.id = sr.team_id
+		WHERE sr.season_id = $1
+		GROUP BY sr.user_id, sr.team_id, t.name, u.nickname, u.first_name, u.last_name, t.logo_key, u.logo_key
+		ORDER BY total_points DESC, tournament_wins DESC, best_placement ASC, name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.SeasonLeaderboardEntry, 0)
+	for rows.Next() {
+		var e models.SeasonLeaderboardEntry
+		if scanErr := rows.Scan(
+			&e.UserID, &e.TeamID, &e.Name, &e.LogoKey,
+			&e.Points, &e.TournamentsPlayed, &e.TournamentWins, &e.BestPlacement,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *postgresSeasonRepository) handleSeasonError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			switch pqErr.Constraint {
+			case "seasons_organizer_id_name_key":
+				return ErrSeasonNameConflict
+			case "season_tournaments_tournament_id_key", "season_tournaments_pkey":
+				return ErrSeasonTournamentConflict
+			}
+		case "23503":
+			switch pqErr.Constraint {
+			case "fk_seasons_sport":
+				return ErrSeasonInvalidSport
+			case "fk_seasons_organization":
+				return ErrSeasonInvalidOrganization
+			case "fk_season_tournaments_season":
+				return ErrSeasonNotFound
+			case "fk_season_tournaments_tournament":
+				return ErrTournamentNotFound
+			}
+		}
+	}
+	return err
+}
+
+func marshalPointsTable(table []int) (string, error) {
+	if table == nil {
+		table = []int{}
+	}
+	b, err := json.Marshal(table)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSeasonInvalidPointsTable, err)
+	}
+	return string(b), nil
+}
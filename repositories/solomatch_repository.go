@@ -9,6 +9,7 @@ import (
 	"github.com/lib/pq"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type SQLExecutor interface {
@@ -19,6 +20,7 @@ type SQLExecutor interface {
 
 var (
 	ErrSoloMatchNotFound                 = errors.New("solo match not found")
+	ErrSoloMatchStatusConflict           = errors.New("solo match status does not allow this change")
 	ErrSoloMatchTournamentInvalid        = errors.New("solo match tournament conflict or invalid")
 	ErrSoloMatchParticipantInvalid       = errors.New("solo match participant conflict or invalid")
 	ErrSoloMatchWinnerParticipantInvalid = errors.New("solo match winner participant conflict or invalid")
@@ -29,9 +31,21 @@ type SoloMatchRepository interface {
 	GetByID(ctx context.Context, id int) (*models.SoloMatch, error)
 	ListByTournament(ctx context.Context, tournamentID int, round *int, status *models.MatchStatus) ([]*models.SoloMatch, error)
 	UpdateScoreStatusWinner(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, winnerParticipantID *int) error
-	Delete(ctx context.Context, id int) error
+	// UpdateLiveScore меняет счёт и статус, только если текущий статус входит в fromStatuses.
+	UpdateLiveScore(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, fromStatuses ...models.MatchStatus) error
+	// UpdateMatchTime переносит матч; разрешено только для запланированных матчей.
+	UpdateMatchTime(ctx context.Context, exec SQLExecutor, id int, matchTime time.Time) error
+	Delete(ctx context.Context, exec SQLExecutor, id int) error
 	UpdateNextMatchInfo(ctx context.Context, exec SQLExecutor, matchID int, nextMatchDBID *int, winnerToSlot *int) error
 	UpdateParticipants(ctx context.Context, exec SQLExecutor, matchID int, p1ParticipantID *int, p2ParticipantID *int) error
+	// UpdateUnplayedParticipants меняет участников, только если матч ещё запланирован, без победителя
+	// и его слоты совпадают с expectedP1/expectedP2; иначе ErrSoloMatchStatusConflict.
+	UpdateUnplayedParticipants(ctx context.Context, exec SQLExecutor, matchID int, expectedP1, expectedP2, p1ParticipantID, p2ParticipantID *int) error
+	// LockUnfinished блокирует строку матча до конца транзакции; для завершённого
+	// или отменённого матча возвращает ErrSoloMatchStatusConflict.
+	LockUnfinished(ctx context.Context, exec SQLExecutor, id int) error
+	// DeleteUnplayed удаляет матч, только если он ещё запланирован и без победителя.
+	DeleteUnplayed(ctx context.Context, exec SQLExecutor, id int) error
 	CountSoloMatches(ctx context.Context, filters map[string]interface{}) (int, error)
 }
 
@@ -167,7 +181,8 @@ func (r *postgresSoloMatchRepository) UpdateScoreStatusWinner(ctx context.Contex
 	executor := r.getExecutor(exec)
 	query := `
 		UPDATE solo_matches
-		SET score = $1, status = $2, winner_participant_id = $3
+		SET score = $1, status = $2, winner_participant_id = $3,
+		    completed_at = CASE WHEN $2::text = 'completed' THEN clock_timestamp() ELSE completed_at END
 		WHERE id = $4`
 
 	result, err := executor.ExecContext(ctx, query, score, status, winnerParticipantID, id)
@@ -177,8 +192,8 @@ func (r *postgresSoloMatchRepository) UpdateScoreStatusWinner(ctx context.Contex
 	return r.checkAffectedRows(result, ErrSoloMatchNotFound)
 }
 
-func (r *postgresSoloMatchRepository) Delete(ctx context.Context, id int) error {
-	executor := r.getExecutor(nil)
+func (r *postgresSoloMatchRepository) Delete(ctx context.Context, exec SQLExecutor, id int) error {
+	executor := r.getExecutor(exec)
 	query := `DELETE FROM solo_matches WHERE id = $1`
 	result, err := executor.ExecContext(ctx, query, id)
 	if err != nil {
@@ -207,6 +222,38 @@ func (r *postgresSoloMatchRepository) UpdateParticipants(ctx context.Context, ex
 	return r.checkAffectedRows(result, ErrSoloMatchNotFound)
 }
 
+func (r *postgresSoloMatchRepository) UpdateUnplayedParticipants(ctx context.Context, exec SQLExecutor, matchID int, expectedP1, expectedP2, p1ParticipantID, p2ParticipantID *int) error {
+	query := `
+		UPDATE solo_matches
+		SET p1_participant_id = $1, p2_participant_id = $2
+		WHERE id = $3 AND status = $4 AND winner_participant_id IS NULL
+		  AND p1_participant_id IS NOT DISTINCT FROM $5 AND p2_participant_id IS NOT DISTINCT FROM $6`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, p1ParticipantID, p2ParticipantID, matchID, models.StatusScheduled, expectedP1, expectedP2)
+	if err != nil {
+		return r.handleSoloMatchError(err)
+	}
+	return r.checkAffectedRows(result, ErrSoloMatchStatusConflict)
+}
+
+func (r *postgresSoloMatchRepository) LockUnfinished(ctx context.Context, exec SQLExecutor, id int) error {
+	query := `SELECT id FROM solo_matches WHERE id = $1 AND status NOT IN ($2, $3) FOR UPDATE`
+	var lockedID int
+	err := r.getExecutor(exec).QueryRowContext(ctx, query, id, models.MatchStatusCompleted, models.MatchStatusCanceled).Scan(&lockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSoloMatchStatusConflict
+	}
+	return err
+}
+
+func (r *postgresSoloMatchRepository) DeleteUnplayed(ctx context.Context, exec SQLExecutor, id int) error {
+	query := `DELETE FROM solo_matches WHERE id = $1 AND status = $2 AND winner_participant_id IS NULL`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, id, models.StatusScheduled)
+	if err != nil {
+		return err
+	}
+	return r.checkAffectedRows(result, ErrSoloMatchStatusConflict)
+}
+
 func (r *postgresSoloMatchRepository) handleSoloMatchError(err error) error {
 	if err == nil {
 		return nil
@@ -254,3 +301,31 @@ func (r *postgresSoloMatchRepository) CountSoloMatches(ctx context.Context, filt
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
+
+func (r *postgresSoloMatchRepository) UpdateLiveScore(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, fromStatuses ...models.MatchStatus) error {
+	executor := r.getExecutor(exec)
+	allowed := make([]string, len(fromStatuses))
+	for i, st := range fromStatuses {
+		allowed[i] = string(st)
+	}
+
+	query := `
+		UPDATE solo_matches
+		SET score = $1, status = $2
+		WHERE id = $3 AND status::text = ANY($4)`
+
+	result, err := executor.ExecContext(ctx, query, score, status, id, pq.Array(allowed))
+	if err != nil {
+		return r.handleSoloMatchError(err)
+	}
+	return r.checkAffectedRows(result, ErrSoloMatchStatusConflict)
+}
+
+func (r *postgresSoloMatchRepository) UpdateMatchTime(ctx context.Context, exec SQLExecutor, id int, matchTime time.Time) error {
+	query := `UPDATE solo_matches SET match_time = $1 WHERE id = $2 AND status = $3`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, matchTime, id, models.StatusScheduled)
+	if err != nil {
+		return r.handleSoloMatchError(err)
+	}
+	return r.checkAffectedRows(result, ErrSoloMatchStatusConflict)
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -35,8 +36,12 @@ func NewPostgresSportRepository(db *sql.DB) SportRepository {
 }
 
 func (r *postgresSportRepository) Create(ctx context.Context, sport *models.Sport) error {
-	query := `INSERT INTO sports (name, logo_key) VALUES ($1, $2) RETURNING id` // Добавили logo_key
-	err := r.db.QueryRowContext(ctx, query, sport.Name, sport.LogoKey).Scan(&sport.ID)
+	rulesJSON, err := json.Marshal(sport.ScoringRules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scoring rules: %w", err)
+	}
+	query := `INSERT INTO sports (name, logo_key, match_event_types, score_from_goals, scoring_rules) VALUES ($1, $2, $3, $4, $5) RETURNING id` // Добавили logo_key
+	err = r.db.QueryRowContext(ctx, query, sport.Name, sport.LogoKey, pq.Array(matchEventTypesToStrings(sport.MatchEventTypes)), sport.ScoreFromGoals, rulesJSON).Scan(&sport.ID)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
 			if pqErr.Constraint == "sports_name_key" {
@@ -49,20 +54,19 @@ func (r *postgresSportRepository) Create(ctx context.Context, sport *models.Spor
 }
 
 func (r *postgresSportRepository) GetByID(ctx context.Context, id int) (*models.Sport, error) {
-	query := `SELECT id, name, logo_key FROM sports WHERE id = $1` // Добавили logo_key
-	var sport models.Sport
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&sport.ID, &sport.Name, &sport.LogoKey)
+	query := `SELECT ` + sportColumns + ` FROM sports WHERE id = $1`
+	sport, err := scanSport(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrSportNotFound
 		}
 		return nil, err
 	}
-	return &sport, nil
+	return sport, nil
 }
 
 func (r *postgresSportRepository) GetAll(ctx context.Context) ([]models.Sport, error) {
-	query := `SELECT id, name, logo_key FROM sports ORDER BY name ASC` // Добавили logo_key
+	query := `SELECT ` + sportColumns + ` FROM sports ORDER BY name ASC`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -71,11 +75,11 @@ func (r *postgresSportRepository) GetAll(ctx context.Context) ([]models.Sport, e
 
 	sports := make([]models.Sport, 0)
 	for rows.Next() {
-		var sport models.Sport
-		if scanErr := rows.Scan(&sport.ID, &sport.Name, &sport.LogoKey); scanErr != nil {
+		sport, scanErr := scanSport(rows)
+		if scanErr != nil {
 			return nil, scanErr
 		}
-		sports = append(sports, sport)
+		sports = append(sports, *sport)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -85,9 +89,13 @@ func (r *postgresSportRepository) GetAll(ctx context.Context) ([]models.Sport, e
 }
 
 func (r *postgresSportRepository) Update(ctx context.Context, sport *models.Sport) error {
-	// При обычном обновлении имени логотип не трогаем здесь, для лого будет UpdateLogoKey
-	query := `UPDATE sports SET name = $1 WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, sport.Name, sport.ID)
+	// При обычном обновлении логотип не трогаем здесь, для лого будет UpdateLogoKey
+	rulesJSON, err := json.Marshal(sport.ScoringRules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scoring rules: %w", err)
+	}
+	query := `UPDATE sports SET name = $1, match_event_types = $2, score_from_goals = $3, scoring_rules = $4 WHERE id = $5`
+	result, err := r.db.ExecContext(ctx, query, sport.Name, pq.Array(matchEventTypesToStrings(sport.MatchEventTypes)), sport.ScoreFromGoals, rulesJSON, sport.ID)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
 			if pqErr.Constraint == "sports_name_key" {
@@ -140,3 +148,40 @@ func (r *postgresSportRepository) checkAffected(result sql.Result) error { // Х
 	}
 	return nil
 }
+
+const sportColumns = `id, name, logo_key, match_event_types, score_from_goals, scoring_rules`
+
+func scanSport(rowScanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.Sport, error) {
+	var sport models.Sport
+	var eventTypes []string
+	var rulesJSON []byte
+	if err := rowScanner.Scan(&sport.ID, &sport.Name, &sport.LogoKey, pq.Array(&eventTypes), &sport.ScoreFromGoals, &rulesJSON); err != nil {
+		return nil, err
+	}
+	sport.MatchEventTypes = stringsToMatchEventTypes(eventTypes)
+	sport.ScoringRules = models.DefaultScoringRules()
+	if len(rulesJSON) > 0 {
+		if err := json.Unmarshal(rulesJSON, &sport.ScoringRules); err != nil {
+			return nil, fmt.Errorf("failed to parse scoring rules of sport %d: %w", sport.ID, err)
+		}
+	}
+	return &sport, nil
+}
+
+func matchEventTypesToStrings(types []models.MatchEventType) []string {
+	result := make([]string, len(types))
+	for i, t := range types {
+		result[i] = string(t)
+	}
+	return result
+}
+
+func stringsToMatchEventTypes(values []string) []models.MatchEventType {
+	result := make([]models.MatchEventType, len(values))
+	for i, v := range values {
+		result[i] = models.MatchEventType(v)
+	}
+	return result
+}
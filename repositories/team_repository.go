@@ -24,6 +24,8 @@ type TeamRepository interface {
 	Delete(ctx context.Context, id int) error
 	ExistsByName(ctx context.Context, name string) (bool, error)
 	UpdateLogoKey(ctx context.Context, teamID int, logoKey *string) error
+	UpdateOrganization(ctx context.Context, teamID int, organizationID *int) error
+	ListByOrganization(ctx context.Context, organizationID int) ([]models.Team, error)
 }
 
 type postgresTeamRepository struct {
@@ -70,7 +72,7 @@ func (r *postgresTeamRepository) Create(ctx context.Context, team *models.Team)
 
 func (r *postgresTeamRepository) GetByID(ctx context.Context, id int) (*models.Team, error) {
 	query := `
-		SELECT id, name, sport_id, captain_id, created_at, logo_key
+		SELECT id, name, sport_id, captain_id, created_at, logo_key, organization_id
 		FROM teams
 		WHERE id = $1`
 
@@ -82,6 +84,7 @@ func (r *postgresTeamRepository) GetByID(ctx context.Context, id int) (*models.T
 		&team.CaptainID,
 		&team.CreatedAt,
 		&team.LogoKey,
+		&team.OrganizationID,
 	)
 
 	if err != nil {
@@ -96,7 +99,7 @@ func (r *postgresTeamRepository) GetByID(ctx context.Context, id int) (*models.T
 
 func (r *postgresTeamRepository) GetAll(ctx context.Context) ([]models.Team, error) {
 	query := `
-		SELECT id, name, sport_id, captain_id, created_at, logo_key
+		SELECT id, name, sport_id, captain_id, created_at, logo_key, organization_id
 		FROM teams
 		ORDER BY name ASC`
 
@@ -116,6 +119,7 @@ func (r *postgresTeamRepository) GetAll(ctx context.Context) ([]models.Team, err
 			&team.CaptainID,
 			&team.CreatedAt,
 			&team.LogoKey,
+			&team.OrganizationID,
 		); scanErr != nil {
 			return nil, scanErr
 		}
@@ -219,3 +223,56 @@ func (r *postgresTeamRepository) UpdateLogoKey(ctx context.Context, teamID int,
 	}
 	return nil
 }
+
+func (r *postgresTeamRepository) UpdateOrganization(ctx context.Context, teamID int, organizationID *int) error {
+	query := `
+		UPDATE teams
+		SET organization_id = $1
+		WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, organizationID, teamID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" && pqErr.Constraint == "fk_teams_organization" {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	return checkAffectedRows(result, ErrTeamNotFound)
+}
+
+func (r *postgresTeamRepository) ListByOrganization(ctx context.Context, organizationID int) ([]models.Team, error) {
+	query := `
+		SELECT id, name, sport_id, captain_id, created_at, logo_key, organization_id
+		FROM teams
+		WHERE organization_id = $1
+		ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]models.Team, 0)
+	for rows.Next() {
+		var team models.Team
+		if scanErr := rows.Scan(
+			&team.ID,
+			&team.Name,
+			&team.SportID,
+			&team.CaptainID,
+			&team.CreatedAt,
+			&team.LogoKey,
+			&team.OrganizationID,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
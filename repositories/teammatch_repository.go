@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/lib/pq"
@@ -14,6 +15,7 @@ import (
 
 var (
 	ErrTeamMatchNotFound                 = errors.New("team match not found")
+	ErrTeamMatchStatusConflict           = errors.New("team match status does not allow this change")
 	ErrTeamMatchTournamentInvalid        = errors.New("team match tournament conflict or invalid")
 	ErrTeamMatchParticipantInvalid       = errors.New("team match participant conflict or invalid")
 	ErrTeamMatchWinnerParticipantInvalid = errors.New("team match winner participant conflict or invalid")
@@ -24,9 +26,21 @@ type TeamMatchRepository interface {
 	GetByID(ctx context.Context, id int) (*models.TeamMatch, error)
 	ListByTournament(ctx context.Context, tournamentID int, round *int, status *models.MatchStatus) ([]*models.TeamMatch, error)
 	UpdateScoreStatusWinner(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, winnerParticipantID *int) error
-	Delete(ctx context.Context, id int) error
+	// UpdateLiveScore меняет счёт и статус, только если текущий статус входит в fromStatuses.
+	UpdateLiveScore(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, fromStatuses ...models.MatchStatus) error
+	// UpdateMatchTime переносит матч; разрешено только для запланированных матчей.
+	UpdateMatchTime(ctx context.Context, exec SQLExecutor, id int, matchTime time.Time) error
+	Delete(ctx context.Context, exec SQLExecutor, id int) error
 	UpdateNextMatchInfo(ctx context.Context, exec SQLExecutor, matchID int, nextMatchDBID *int, winnerToSlot *int) error
 	UpdateParticipants(ctx context.Context, exec SQLExecutor, matchID int, t1ParticipantID *int, t2ParticipantID *int) error
+	// UpdateUnplayedParticipants меняет участников, только если матч ещё запланирован, без победителя
+	// и его слоты совпадают с expectedT1/expectedT2; иначе ErrTeamMatchStatusConflict.
+	UpdateUnplayedParticipants(ctx context.Context, exec SQLExecutor, matchID int, expectedT1, expectedT2, t1ParticipantID, t2ParticipantID *int) error
+	// LockUnfinished блокирует строку матча до конца транзакции; для завершённого
+	// или отменённого матча возвращает ErrTeamMatchStatusConflict.
+	LockUnfinished(ctx context.Context, exec SQLExecutor, id int) error
+	// DeleteUnplayed удаляет матч, только если он ещё запланирован и без победителя.
+	DeleteUnplayed(ctx context.Context, exec SQLExecutor, id int) error
 	CountTeamMatches(ctx context.Context, filters map[string]interface{}) (int, error)
 }
 
@@ -163,7 +177,8 @@ func (r *postgresTeamMatchRepository) UpdateScoreStatusWinner(ctx context.Contex
 	executor := r.getExecutor(exec)
 	query := `
 		UPDATE team_matches
-		SET score = $1, status = $2, winner_participant_id = $3
+		SET score = $1, status = $2, winner_participant_id = $3,
+		    completed_at = CASE WHEN $2::text = 'completed' THEN clock_timestamp() ELSE completed_at END
 		WHERE id = $4`
 
 	result, err := executor.ExecContext(ctx, query, score, status, winnerParticipantID, id)
@@ -173,8 +188,8 @@ func (r *postgresTeamMatchRepository) UpdateScoreStatusWinner(ctx context.Contex
 	return checkAffectedRows(result, ErrTeamMatchNotFound)
 }
 
-func (r *postgresTeamMatchRepository) Delete(ctx context.Context, id int) error {
-	executor := r.getExecutor(nil)
+func (r *postgresTeamMatchRepository) Delete(ctx context.Context, exec SQLExecutor, id int) error {
+	executor := r.getExecutor(exec)
 	query := `DELETE FROM team_matches WHERE id = $1`
 	result, err := executor.ExecContext(ctx, query, id)
 	if err != nil {
@@ -203,6 +218,38 @@ func (r *postgresTeamMatchRepository) UpdateParticipants(ctx context.Context, ex
 	return checkAffectedRows(result, ErrTeamMatchNotFound)
 }
 
+func (r *postgresTeamMatchRepository) UpdateUnplayedParticipants(ctx context.Context, exec SQLExecutor, matchID int, expectedT1, expectedT2, t1ParticipantID, t2ParticipantID *int) error {
+	query := `
+		UPDATE team_matches
+		SET t1_participant_id = $1, t2_participant_id = $2
+		WHERE id = $3 AND status = $4 AND winner_participant_id IS NULL
+		  AND t1_participant_id IS NOT DISTINCT FROM $5 AND t2_participant_id IS NOT DISTINCT FROM $6`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, t1ParticipantID, t2ParticipantID, matchID, models.StatusScheduled, expectedT1, expectedT2)
+	if err != nil {
+		return r.handleTeamMatchError(err)
+	}
+	return checkAffectedRows(result, ErrTeamMatchStatusConflict)
+}
+
+func (r *postgresTeamMatchRepository) LockUnfinished(ctx context.Context, exec SQLExecutor, id int) error {
+	query := `SELECT id FROM team_matches WHERE id = $1 AND status NOT IN ($2, $3) FOR UPDATE`
+	var lockedID int
+	err := r.getExecutor(exec).QueryRowContext(ctx, query, id, models.MatchStatusCompleted, models.MatchStatusCanceled).Scan(&lockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTeamMatchStatusConflict
+	}
+	return err
+}
+
+func (r *postgresTeamMatchRepository) DeleteUnplayed(ctx context.Context, exec SQLExecutor, id int) error {
+	query := `DELETE FROM team_matches WHERE id = $1 AND status = $2 AND winner_participant_id IS NULL`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, id, models.StatusScheduled)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(result, ErrTeamMatchStatusConflict)
+}
+
 func (r *postgresTeamMatchRepository) handleTeamMatchError(err error) error {
 	if err == nil {
 		return nil
@@ -239,3 +286,31 @@ func (r *postgresTeamMatchRepository) CountTeamMatches(ctx context.Context, filt
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
+
+func (r *postgresTeamMatchRepository) UpdateLiveScore(ctx context.Context, exec SQLExecutor, id int, score *string, status models.MatchStatus, fromStatuses ...models.MatchStatus) error {
+	executor := r.getExecutor(exec)
+	allowed := make([]string, len(fromStatuses))
+	for i, st := range fromStatuses {
+		allowed[i] = string(st)
+	}
+
+	query := `
+		UPDATE team_matches
+		SET score = $1, status = $2
+		WHERE id = $3 AND status::text = ANY($4)`
+
+	result, err := executor.ExecContext(ctx, query, score, status, id, pq.Array(allowed))
+	if err != nil {
+		return r.handleTeamMatchError(err)
+	}
+	return checkAffectedRows(result, ErrTeamMatchStatusConflict)
+}
+
+func (r *postgresTeamMatchRepository) UpdateMatchTime(ctx context.Context, exec SQLExecutor, id int, matchTime time.Time) error {
+	query := `UPDATE team_matches SET match_time = $1 WHERE id = $2 AND status = $3`
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, matchTime, id, models.StatusScheduled)
+	if err != nil {
+		return r.handleTeamMatchError(err)
+	}
+	return checkAffectedRows(result, ErrTeamMatchStatusConflict)
+}
@@ -19,15 +19,18 @@ var (
 	ErrTournamentInvalidSport  = errors.New("invalid sport reference")
 	ErrTournamentInvalidFormat = errors.New("invalid format reference")
 	ErrTournamentInvalidOrg    = errors.New("invalid organizer reference")
+
+	ErrTournamentInvalidOrganization = errors.New("invalid organization reference")
 )
 
 type ListTournamentsFilter struct {
-	SportID     *int
-	FormatID    *int
-	OrganizerID *int
-	Status      *models.TournamentStatus
-	Limit       int
-	Offset      int
+	SportID        *int
+	FormatID       *int
+	OrganizerID    *int
+	OrganizationID *int
+	Status         *models.TournamentStatus
+	Limit          int
+	Offset         int
 }
 
 type TournamentRepository interface {
@@ -64,13 +67,13 @@ func (r *postgresTournamentRepository) Create(ctx context.Context, t *models.Tou
 	query := `
 		INSERT INTO tournaments (
 			name, description, sport_id, format_id, organizer_id,
-			reg_date, start_date, end_date, location, status, max_participants, logo_key 
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			reg_date, start_date, end_date, location, status, max_participants, logo_key, organization_id, seed_by_rating
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at`
 
 	err := executor.QueryRowContext(ctx, query,
 		t.Name, t.Description, t.SportID, t.FormatID, t.OrganizerID,
-		t.RegDate, t.StartDate, t.EndDate, t.Location, t.Status, t.MaxParticipants, t.LogoKey,
+		t.RegDate, t.StartDate, t.EndDate, t.Location, t.Status, t.MaxParticipants, t.LogoKey, t.OrganizationID, t.SeedByRating,
 	).Scan(&t.ID, &t.CreatedAt)
 
 	return r.handleTournamentError(err)
@@ -82,7 +85,7 @@ func (r *postgresTournamentRepository) GetByID(ctx context.Context, id int) (*mo
 		SELECT
 			id, name, description, sport_id, format_id, organizer_id,
 			reg_date, start_date, end_date, location, status, max_participants, created_at, logo_key,
-			overall_winner_participant_id
+			overall_winner_participant_id, organization_id, seed_by_rating
 		FROM tournaments
 		WHERE id = $1`
 
@@ -90,7 +93,7 @@ func (r *postgresTournamentRepository) GetByID(ctx context.Context, id int) (*mo
 	err := executor.QueryRowContext(ctx, query, id).Scan(
 		&t.ID, &t.Name, &t.Description, &t.SportID, &t.FormatID, &t.OrganizerID,
 		&t.RegDate, &t.StartDate, &t.EndDate, &t.Location, &t.Status, &t.MaxParticipants, &t.CreatedAt, &t.LogoKey,
-		&t.OverallWinnerParticipantID, // Added scan for the new field
+		&t.OverallWinnerParticipantID, &t.OrganizationID, &t.SeedByRating,
 	)
 
 	if err != nil {
@@ -108,7 +111,7 @@ func (r *postgresTournamentRepository) List(ctx context.Context, filter ListTour
 		SELECT
 			id, name, description, sport_id, format_id, organizer_id,
 			reg_date, start_date, end_date, location, status, max_participants, created_at, logo_key,
-			overall_winner_participant_id
+			overall_winner_participant_id, organization_id, seed_by_rating
 		FROM tournaments
 		WHERE 1=1`
 
@@ -130,6 +133,11 @@ func (r *postgresTournamentRepository) List(ctx context.Context, filter ListTour
 		args = append(args, *filter.OrganizerID)
 		argID++
 	}
+	if filter.OrganizationID != nil {
+		query += fmt.Sprintf(" AND organization_id = $%d", argID)
+		args = append(args, *filter.OrganizationID)
+		argID++
+	}
 	if filter.Status != nil {
 		query += fmt.Sprintf(" AND status = $%d", argID)
 		args = append(args, *filter.Status)
@@ -161,7 +169,7 @@ func (r *postgresTournamentRepository) List(ctx context.Context, filter ListTour
 		if scanErr := rows.Scan(
 			&t.ID, &t.Name, &t.Description, &t.SportID, &t.FormatID, &t.OrganizerID,
 			&t.RegDate, &t.StartDate, &t.EndDate, &t.Location, &t.Status, &t.MaxParticipants, &t.CreatedAt, &t.LogoKey,
-			&t.OverallWinnerParticipantID, // Added scan
+			&t.OverallWinnerParticipantID, &t.OrganizationID, &t.SeedByRating,
 		); scanErr != nil {
 			return nil, scanErr
 		}
@@ -190,13 +198,15 @@ func (r *postgresTournamentRepository) Update(ctx context.Context, t *models.Tou
 			end_date = $8,
 			location = $9,
 			status = $10,
-			max_participants = $11
+			max_participants = $11,
+			organization_id = $12,
+			seed_by_rating = $13
 			-- overall_winner_participant_id is NOT updated here by default
-		WHERE id = $12`
+		WHERE id = $14`
 
 	result, err := executor.ExecContext(ctx, query,
 		t.Name, t.Description, t.SportID, t.FormatID, t.OrganizerID,
-		t.RegDate, t.StartDate, t.EndDate, t.Location, t.Status, t.MaxParticipants,
+		t.RegDate, t.StartDate, t.EndDate, t.Location, t.Status, t.MaxParticipants, t.OrganizationID, t.SeedByRating,
 		t.ID,
 	)
 
@@ -256,7 +266,7 @@ func (r *postgresTournamentRepository) GetTournamentsForAutoStatusUpdate(ctx con
 		SELECT
 			id, name, description, sport_id, format_id, organizer_id,
 			reg_date, start_date, end_date, location, status, max_participants, created_at, logo_key,
-			overall_winner_participant_id
+			overall_winner_participant_id, organization_id, seed_by_rating
 		FROM tournaments
 		WHERE status NOT IN ($1, $2) 
 		AND (
@@ -286,7 +296,7 @@ func (r *postgresTournamentRepository) GetTournamentsForAutoStatusUpdate(ctx con
 		if scanErr := rows.Scan(
 			&t.ID, &t.Name, &t.Description, &t.SportID, &t.FormatID, &t.OrganizerID,
 			&t.RegDate, &t.StartDate, &t.EndDate, &t.Location, &t.Status, &t.MaxParticipants, &t.CreatedAt, &t.LogoKey,
-			&t.OverallWinnerParticipantID, // Added scan
+			&t.OverallWinnerParticipantID, &t.OrganizationID, &t.SeedByRating,
 		); scanErr != nil {
 			return nil, fmt.Errorf("failed to scan tournament for auto status update: %w", scanErr)
 		}
@@ -316,6 +326,8 @@ func (r *postgresTournamentRepository) handleTournamentError(err error) error {
 				return ErrTournamentInvalidFormat
 			case "tournaments_organizer_id_fkey":
 				return ErrTournamentInvalidOrg
+			case "fk_tournaments_organization":
+				return ErrTournamentInvalidOrganization
 			case "fk_tournaments_overall_winner": // If a non-existent participant ID is used
 				return ErrParticipantNotFound // Or a more specific error like ErrWinnerParticipantInvalid
 			default:
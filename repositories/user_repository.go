@@ -21,7 +21,7 @@ var (
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, user *models.User) error
+	Create(ctx context.Context, exec SQLExecutor, user *models.User) error
 	GetByID(ctx context.Context, id int) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	GetByConfirmationToken(ctx context.Context, token string) (*models.User, error)
@@ -32,7 +32,7 @@ type UserRepository interface {
 	ListByTeamID(ctx context.Context, teamID int) ([]models.User, error)
 	List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error)
 	Count(ctx context.Context, filters map[string]interface{}) (int, error)
-	SetPasswordResetToken(ctx context.Context, userID int, token string, expiresAt time.Time) error
+	SetPasswordResetToken(ctx context.Context, exec SQLExecutor, userID int, token string, expiresAt time.Time) error
 	GetByPasswordResetToken(ctx context.Context, token string) (*models.User, error)
 }
 
@@ -44,12 +44,19 @@ func NewPostgresUserRepository(db *sql.DB) UserRepository {
 	return &postgresUserRepository{db: db}
 }
 
-func (r *postgresUserRepository) Create(ctx context.Context, user *models.User) error {
+func (r *postgresUserRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+func (r *postgresUserRepository) Create(ctx context.Context, exec SQLExecutor, user *models.User) error {
 	query := `
-		INSERT INTO users (first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, email_confirmed, email_confirmation_token)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, created_at`
-	err := r.db.QueryRowContext(ctx, query,
+		INSERT INTO users (first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, email_confirmed, email_confirmation_token, locale, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE(NULLIF($11, ''), 'ru'), COALESCE(NULLIF($12, ''), 'UTC'))
+		RETURNING id, created_at, locale, timezone`
+	err := r.getExecutor(exec).QueryRowContext(ctx, query,
 		user.FirstName,
 		user.LastName,
 		user.Nickname,
@@ -60,7 +67,9 @@ func (r *postgresUserRepository) Create(ctx context.Context, user *models.User)
 		user.LogoKey,
 		user.EmailConfirmed,
 		user.EmailConfirmationToken,
-	).Scan(&user.ID, &user.CreatedAt)
+		user.Locale,
+		user.Timezone,
+	).Scan(&user.ID, &user.CreatedAt, &user.Locale, &user.Timezone)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == "23505" && strings.Contains(pqErr.Message, "email") {
@@ -76,6 +85,7 @@ func (r *postgresUserRepository) GetByID(ctx context.Context, id int) (*models.U
 	query := `
 		SELECT
 			u.id, u.first_name, u.last_name, u.nickname, u.email, u.password_hash, u.role, u.team_id, u.logo_key, u.created_at,
+			u.email_confirmed, u.locale, u.timezone,
 			t.id, t.name, t.captain_id, t.sport_id, t.logo_key, t.created_at
 		FROM users u
 		LEFT JOIN teams t ON u.team_id = t.id
@@ -102,6 +112,9 @@ func (r *postgresUserRepository) GetByID(ctx context.Context, id int) (*models.U
 		&user.TeamID,
 		&user.LogoKey,
 		&user.CreatedAt,
+		&user.EmailConfirmed,
+		&user.Locale,
+		&user.Timezone,
 		&teamID,
 		&teamName,
 		&teamCaptainID,
@@ -131,14 +144,14 @@ func (r *postgresUserRepository) GetByID(ctx context.Context, id int) (*models.U
 
 func (r *postgresUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-  SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed
+  SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed, locale, timezone
   FROM users
   WHERE email = $1`
 	return scanUserRow(ctx, r.db, query, email)
 }
 
 func (r *postgresUserRepository) GetByConfirmationToken(ctx context.Context, token string) (*models.User, error) {
-	query := `SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed FROM users WHERE email_confirmation_token = $1`
+	query := `SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed, locale, timezone FROM users WHERE email_confirmation_token = $1`
 	return scanUserRow(ctx, r.db, query, token)
 }
 
@@ -170,8 +183,10 @@ func (r *postgresUserRepository) Update(ctx context.Context, user *models.User)
 			team_id = $7,
 			logo_key = $8,
 			email_confirmed = $9,
-			email_confirmation_token = $10
-		WHERE id = $11`
+			email_confirmation_token = $10,
+			locale = COALESCE(NULLIF($11, ''), locale),
+			timezone = COALESCE(NULLIF($12, ''), timezone)
+		WHERE id = $13`
 	result, err := r.db.ExecContext(ctx, query,
 		user.FirstName,
 		user.LastName,
@@ -183,6 +198,8 @@ func (r *postgresUserRepository) Update(ctx context.Context, user *models.User)
 		user.LogoKey,
 		user.EmailConfirmed,
 		user.EmailConfirmationToken,
+		user.Locale,
+		user.Timezone,
 		user.ID,
 	)
 	if err != nil {
@@ -232,7 +249,8 @@ func (r *postgresUserRepository) Delete(ctx context.Context, id int) error {
 
 func (r *postgresUserRepository) ListByTeamID(ctx context.Context, teamID int) ([]models.User, error) {
 	query := `
-		SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at
+		SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at,
+		       email_confirmed, locale, timezone
 		FROM users
 		WHERE team_id = $1
 		ORDER BY nickname ASC`
@@ -255,6 +273,9 @@ func (r *postgresUserRepository) ListByTeamID(ctx context.Context, teamID int) (
 			&user.TeamID,
 			&user.LogoKey,
 			&user.CreatedAt,
+			&user.EmailConfirmed,
+			&user.Locale,
+			&user.Timezone,
 		)
 		if err != nil {
 			return nil, err
@@ -281,6 +302,8 @@ func scanUserRow(ctx context.Context, db *sql.DB, query string, args ...interfac
 		&user.LogoKey,
 		&user.CreatedAt,
 		&user.EmailConfirmed,
+		&user.Locale,
+		&user.Timezone,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -292,7 +315,7 @@ func scanUserRow(ctx context.Context, db *sql.DB, query string, args ...interfac
 }
 
 func (r *postgresUserRepository) GetByPasswordResetToken(ctx context.Context, token string) (*models.User, error) {
-	query := `SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed, password_reset_token, password_reset_expires_at FROM users WHERE password_reset_token = $1`
+	query := `SELECT id, first_name, last_name, nickname, email, password_hash, role, team_id, logo_key, created_at, email_confirmed, locale, timezone, password_reset_token, password_reset_expires_at FROM users WHERE password_reset_token = $1`
 	return scanUserRowWithReset(ctx, r.db, query, token)
 }
 
@@ -314,6 +337,8 @@ func scanUserRowWithReset(ctx context.Context, db *sql.DB, query string, args ..
 		&user.LogoKey,
 		&user.CreatedAt,
 		&user.EmailConfirmed,
+		&user.Locale,
+		&user.Timezone,
 		&passwordResetToken,
 		&passwordResetExpiresAt,
 	)
@@ -432,9 +457,9 @@ func (r *postgresUserRepository) Count(ctx context.Context, filters map[string]i
 	return count, err
 }
 
-func (r *postgresUserRepository) SetPasswordResetToken(ctx context.Context, userID int, token string, expiresAt time.Time) error {
+func (r *postgresUserRepository) SetPasswordResetToken(ctx context.Context, exec SQLExecutor, userID int, token string, expiresAt time.Time) error {
 	query := `UPDATE users SET password_reset_token = $1, password_reset_expires_at = $2 WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, token, expiresAt, userID)
+	result, err := r.getExecutor(exec).ExecContext(ctx, query, token, expiresAt, userID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,323 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id int) (*models.Webhook, error)
+	ListByOwner(ctx context.Context, userID int) ([]*models.Webhook, error)
+	ListByOrganization(ctx context.Context, organizationID int) ([]*models.Webhook, error)
+	// ListActiveForTournament — активные подписки организатора турнира и его организации.
+	ListActiveForTournament(ctx context.Context, organizerID int, organizationID *int) ([]*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id int) error
+
+	EnqueueDelivery(ctx context.Context, exec SQLExecutor, delivery *models.WebhookDelivery) error
+	// ClaimDueDeliveries забирает готовые к отправке доставки и откладывает их на lease (см. EmailOutboxRepository.ClaimDue).
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	MarkFailed(ctx context.Context, id int64, responseStatus *int, lastError string, nextAttemptAt time.Time, dead bool) error
+	GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID int, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int, error)
+}
+
+type postgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) WebhookRepository {
+	return &postgresWebhookRepository{db: db}
+}
+
+func (r *postgresWebhookRepository) getExecutor(exec SQLExecutor) SQLExecutor {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+const webhookColumns = `id, owner_user_id, organization_id, url, secret, events, is_active, created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event, payload, status, attempts, max_attempts, next_attempt_at,
+		response_status, last_error, redelivery_of, created_at, updated_at, delivered_at`
+
+func (r *postgresWebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (owner_user_id, organization_id, url, secret, events, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + webhookColumns
+	row := r.db.QueryRowContext(ctx, query,
+		webhook.OwnerUserID, webhook.OrganizationID, webhook.URL, webhook.Secret,
+		pq.Array(webhookEventsToStrings(webhook.Events)), webhook.IsActive, webhook.CreatedBy,
+	)
+	if err := scanWebhook(row, webhook); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			if pqErr.Constraint == "webhooks_organization_id_fkey" {
+				return ErrOrganizationNotFound
+			}
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresWebhookRepository) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id), &webhook)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook %d: %w", id, err)
+	}
+	return &webhook, nil
+}
+
+func (r *postgresWebhookRepository) ListByOwner(ctx context.Context, userID int) ([]*models.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE owner_user_id = $1 ORDER BY id`, userID)
+}
+
+func (r *postgresWebhookRepository) ListByOrganization(ctx context.Context, organizationID int) ([]*models.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE organization_id = $1 ORDER BY id`, organizationID)
+}
+
+func (r *postgresWebhookRepository) ListActiveForTournament(ctx context.Context, organizerID int, organizationID *int) ([]*models.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + ` FROM webhooks
+		WHERE is_active AND (owner_user_id = $1 OR ($2::int IS NOT NULL AND organization_id = $2))
+		ORDER BY id`
+	return r.list(ctx, query, organizerID, organizationID)
+}
+
+func (r *postgresWebhookRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*models.Webhook, 0)
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *postgresWebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $2, events = $3, is_active = $4, secret = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at`
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.ID, webhook.URL, pq.Array(webhookEventsToStrings(webhook.Events)), webhook.IsActive, webhook.Secret,
+	).Scan(&webhook.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to update webhook %d: %w", webhook.ID, err)
+	}
+	return nil
+}
+
+func (r *postgresWebhookRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %d: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrWebhookNotFound)
+}
+
+func (r *postgresWebhookRepository) EnqueueDelivery(ctx context.Context, exec SQLExecutor, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, max_attempts, redelivery_of)
+		VALUES ($1, $2, $3, COALESCE(NULLIF($4, 0), 8), $5)
+		RETURNING ` + webhookDeliveryColumns
+	row := r.getExecutor(exec).QueryRowContext(ctx, query,
+		delivery.WebhookID, delivery.Event, []byte(delivery.Payload), delivery.MaxAttempts, delivery.RedeliveryOf,
+	)
+	if err := scanWebhookDelivery(row, delivery); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to enqueue %s delivery for webhook %d: %w", delivery.Event, delivery.WebhookID, err)
+	}
+	return nil
+}
+
+func (r *postgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *postgresWebhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, response_status = $2, last_error = NULL,
+		    delivered_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, responseStatus)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery %d as delivered: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrWebhookDeliveryNotFound)
+}
+
+func (r *postgresWebhookRepository) MarkFailed(ctx context.Context, id int64, responseStatus *int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := models.WebhookDeliveryPending
+	if dead {
+		status = models.WebhookDeliveryDead
+	}
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4,
+		    next_attempt_at = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, status, responseStatus, lastError, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery %d as failed: %w", id, err)
+	}
+	return checkAffectedRows(result, ErrWebhookDeliveryNotFound)
+}
+
+func (r *postgresWebhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	if err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id), &delivery); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery %d: %w", id, err)
+	}
+	return &delivery, nil
+}
+
+func (r *postgresWebhookRepository) ListDeliveries(ctx context.Context, webhookID int, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int, error) {
+	where := ` WHERE webhook_id = $1`
+	args := []interface{}{webhookID}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deliveries of webhook %d: %w", webhookID, err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries` + where +
+		fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list deliveries of webhook %d: %w", webhookID, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, total, rows.Err()
+}
+
+func webhookEventsToStrings(events []models.WebhookEvent) []string {
+	result := make([]string, len(events))
+	for i, e := range events {
+		result[i] = string(e)
+	}
+	return result
+}
+
+func scanWebhook(rowScanner interface {
+	Scan(dest ...interface{}) error
+}, webhook *models.Webhook) error {
+	var events pq.StringArray
+	if err := rowScanner.Scan(
+		&webhook.ID, &webhook.OwnerUserID, &webhook.OrganizationID, &webhook.URL, &webhook.Secret, &events,
+		&webhook.IsActive, &webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	webhook.Events = make([]models.WebhookEvent, len(events))
+	for i, e := range events {
+		webhook.Events[i] = models.WebhookEvent(e)
+	}
+	return nil
+}
+
+func scanWebhookDelivery(rowScanner interface {
+	Scan(dest ...interface{}) error
+}, delivery *models.WebhookDelivery) error {
+	var payload []byte
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+	if err := rowScanner.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.Event, &payload, &delivery.Status, &delivery.Attempts, &delivery.MaxAttempts,
+		&delivery.NextAttemptAt, &delivery.ResponseStatus, &lastError, &delivery.RedeliveryOf, &delivery.CreatedAt, &delivery.UpdatedAt, &deliveredAt,
+	); err != nil {
+		return err
+	}
+	delivery.Payload = payload
+	delivery.LastError = nil
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	delivery.DeliveredAt = nil
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
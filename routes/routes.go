@@ -22,10 +22,21 @@ func SetupRoutes(
 	formatHandler *handlers.FormatHandler,
 	adminHandler *handlers.AdminUserHandler,
 	dashboardHandler *handlers.DashboardHandler,
+	organizationHandler *handlers.OrganizationHandler,
+	seasonHandler *handlers.SeasonHandler,
+	ratingHandler *handlers.RatingHandler,
+	careerHandler *handlers.CareerHandler,
+	sseHandler *handlers.SSEHandler,
+	emailOutboxHandler *handlers.EmailOutboxHandler,
+	notificationHandler *handlers.NotificationHandler,
+	webhookHandler *handlers.WebhookHandler,
+	calendarHandler *handlers.CalendarHandler,
+	storageHandler *handlers.StorageHandler,
 ) {
 	router.Use(chiMiddleware.Logger)
 	router.Use(chiMiddleware.Recoverer)
 	router.Use(chiMiddleware.RequestID)
+	router.Use(middleware.PeerAddr)
 	router.Use(chiMiddleware.RealIP)
 
 	router.Route("/auth", func(r chi.Router) {
@@ -38,6 +49,10 @@ func SetupRoutes(
 
 	router.Route("/users", func(r chi.Router) {
 		r.Get("/{id}", userHandler.GetUserByID)
+		r.Get("/{id}/stats", careerHandler.GetUserStats)
+		r.Get("/{id}/matches", careerHandler.ListUserMatches)
+		r.Get("/{id}/tournaments", careerHandler.ListUserTournaments)
+		r.Get("/{id}/head-to-head/{opponentID}", careerHandler.GetUserHeadToHead)
 
 		r.Group(func(authRouter chi.Router) {
 			authRouter.Use(middleware.Authenticate)
@@ -49,6 +64,11 @@ func SetupRoutes(
 	router.Route("/teams", func(r chi.Router) {
 		r.Get("/{teamID}", teamHandler.GetTeamByID)
 		r.Get("/{teamID}/members", teamHandler.ListTeamMembers)
+		r.Get("/{teamID}/stats", careerHandler.GetTeamStats)
+		r.Get("/{teamID}/matches", careerHandler.ListTeamMatches)
+		r.Get("/{teamID}/tournaments", careerHandler.ListTeamTournaments)
+		r.Get("/{teamID}/head-to-head/{opponentTeamID}", careerHandler.GetTeamHeadToHead)
+		r.Get("/{teamID}/calendar.ics", calendarHandler.TeamCalendar)
 
 		r.Group(func(authRouter chi.Router) {
 			authRouter.Use(middleware.Authenticate)
@@ -85,6 +105,7 @@ func SetupRoutes(
 
 	router.Route("/formats", func(r chi.Router) {
 		r.Get("/", formatHandler.GetAllFormats)
+		r.Get("/bracket-types", formatHandler.ListBracketTypes)
 		r.Get("/{formatID}", formatHandler.GetFormatByID)
 
 		r.Group(func(adminRouter chi.Router) {
@@ -101,9 +122,13 @@ func SetupRoutes(
 		r.Get("/{tournamentID}", tournamentHandler.GetByIDHandler)
 		r.Get("/{tournamentID}/matches/solo", tournamentHandler.ListTournamentSoloMatchesHandler)
 		r.Get("/{tournamentID}/matches/team", tournamentHandler.ListTournamentTeamMatchesHandler)
+		r.Get("/{tournamentID}/matches/solo/{matchID}/events", tournamentHandler.ListMatchEventsHandler(models.RatingMatchSolo))
+		r.Get("/{tournamentID}/matches/team/{matchID}/events", tournamentHandler.ListMatchEventsHandler(models.RatingMatchTeam))
 		r.Get("/{tournamentID}/participants", participantHandler.ListApplications)
 
 		r.Get("/{tournamentID}/bracket", tournamentHandler.GetTournamentBracketHandler)
+		r.Get("/{tournamentID}/events", sseHandler.StreamTournamentEvents)
+		r.Get("/{tournamentID}/calendar.ics", calendarHandler.TournamentCalendar)
 
 		r.Group(func(authRouter chi.Router) {
 			authRouter.Use(middleware.Authenticate)
@@ -112,22 +137,112 @@ func SetupRoutes(
 			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/status", tournamentHandler.UpdateStatusHandler)
 			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Delete("/{tournamentID}", tournamentHandler.DeleteHandler)
 			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/logo", tournamentHandler.UploadTournamentLogoHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/bracket/preview", tournamentHandler.PreviewBracketHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Get("/{tournamentID}/bracket/lock", tournamentHandler.GetLockedBracketPreviewHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Put("/{tournamentID}/bracket/lock", tournamentHandler.LockBracketPreviewHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Delete("/{tournamentID}/bracket/lock", tournamentHandler.UnlockBracketPreviewHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/bracket/swap", tournamentHandler.SwapBracketSlotsHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/bracket/replace", tournamentHandler.ReplaceBracketParticipantHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/bracket/bye", tournamentHandler.SetBracketByeHandler)
 
 			authRouter.Post("/{tournamentID}/register/solo", participantHandler.RegisterSolo)
 			authRouter.Post("/{tournamentID}/register/team", participantHandler.RegisterTeam)
 
 			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/solo/{matchID}/result", tournamentHandler.UpdateSoloMatchResultHandler)
 			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/team/{matchID}/result", tournamentHandler.UpdateTeamMatchResultHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/matches/solo/{matchID}/start", tournamentHandler.StartSoloMatchHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/matches/team/{matchID}/start", tournamentHandler.StartTeamMatchHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/solo/{matchID}/schedule", tournamentHandler.RescheduleSoloMatchHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/team/{matchID}/schedule", tournamentHandler.RescheduleTeamMatchHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/solo/{matchID}/live-score", tournamentHandler.UpdateSoloLiveScoreHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/{tournamentID}/matches/team/{matchID}/live-score", tournamentHandler.UpdateTeamLiveScoreHandler)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/matches/solo/{matchID}/events", tournamentHandler.AddMatchEventHandler(models.RatingMatchSolo))
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/{tournamentID}/matches/team/{matchID}/events", tournamentHandler.AddMatchEventHandler(models.RatingMatchTeam))
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Delete("/{tournamentID}/matches/solo/{matchID}/events/{eventID}", tournamentHandler.DeleteMatchEventHandler(models.RatingMatchSolo))
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Delete("/{tournamentID}/matches/team/{matchID}/events/{eventID}", tournamentHandler.DeleteMatchEventHandler(models.RatingMatchTeam))
 		})
 	})
 
+	router.Route("/organizations", func(r chi.Router) {
+		r.Get("/", organizationHandler.ListOrganizations)
+		r.Get("/{orgID}", organizationHandler.GetOrganizationProfile)
+		r.Get("/{orgID}/tournaments", organizationHandler.ListOrganizationTournaments)
+
+		r.Group(func(authRouter chi.Router) {
+			authRouter.Use(middleware.Authenticate)
+			authRouter.Get("/my", organizationHandler.ListMyOrganizations)
+			authRouter.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Post("/", organizationHandler.CreateOrganization)
+			authRouter.Put("/{orgID}", organizationHandler.UpdateOrganization)
+			authRouter.Delete("/{orgID}", organizationHandler.DeleteOrganization)
+
+			authRouter.Post("/{orgID}/members", organizationHandler.AddMember)
+			authRouter.Put("/{orgID}/members/{userID}", organizationHandler.UpdateMemberRole)
+			authRouter.Delete("/{orgID}/members/{userID}", organizationHandler.RemoveMember)
+
+			authRouter.Post("/{orgID}/teams/{teamID}", organizationHandler.AttachTeam)
+			authRouter.Delete("/{orgID}/teams/{teamID}", organizationHandler.DetachTeam)
+		})
+	})
+
+	router.Route("/seasons", func(r chi.Router) {
+		r.Get("/", seasonHandler.ListSeasons)
+		r.Get("/{seasonID}", seasonHandler.GetSeasonByID)
+		r.Get("/{seasonID}/leaderboard", seasonHandler.GetLeaderboard)
+
+		r.Group(func(authRouter chi.Router) {
+			authRouter.Use(middleware.Authenticate)
+			authRouter.Use(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin))
+			authRouter.Post("/", seasonHandler.CreateSeason)
+			authRouter.Put("/{seasonID}", seasonHandler.UpdateSeason)
+			authRouter.Delete("/{seasonID}", seasonHandler.DeleteSeason)
+
+			authRouter.Post("/{seasonID}/tournaments/{tournamentID}", seasonHandler.AddTournament)
+			authRouter.Delete("/{seasonID}/tournaments/{tournamentID}", seasonHandler.RemoveTournament)
+		})
+	})
+
+	router.Route("/ratings", func(r chi.Router) {
+		r.Get("/leaderboard", ratingHandler.GetLeaderboard)
+		r.Get("/users/{userID}", ratingHandler.GetUserRatings)
+		r.Get("/users/{userID}/history", ratingHandler.GetUserRatingHistory)
+		r.Get("/teams/{teamID}", ratingHandler.GetTeamRatings)
+		r.Get("/teams/{teamID}/history", ratingHandler.GetTeamRatingHistory)
+	})
+
 	router.Route("/participants/{participantID}", func(r chi.Router) {
 		r.Use(middleware.Authenticate)
 		r.Delete("/cancel", participantHandler.CancelRegistration)
 		r.With(middleware.Authorize(models.RoleOrganizer, models.RoleAdmin)).Patch("/status", participantHandler.UpdateApplicationStatus)
 	})
 
+	router.Route("/notifications", func(r chi.Router) {
+		r.Use(middleware.Authenticate)
+		r.Get("/", notificationHandler.ListNotifications)
+		r.Post("/read-all", notificationHandler.MarkAllNotificationsRead)
+		r.Post("/{id}/read", notificationHandler.MarkNotificationRead)
+		r.Get("/preferences", notificationHandler.GetNotificationPreferences)
+		r.Put("/preferences", notificationHandler.UpdateNotificationPreferences)
+	})
+
+	router.Route("/calendar", func(r chi.Router) {
+		r.Get("/{token}.ics", calendarHandler.UserCalendar)
+		r.With(middleware.Authenticate).Get("/me", calendarHandler.GetMyCalendarLink)
+		r.With(middleware.Authenticate).Post("/me/reset", calendarHandler.ResetMyCalendarLink)
+	})
+
+	router.Route("/webhooks", func(r chi.Router) {
+		r.Use(middleware.Authenticate)
+		r.Get("/", webhookHandler.ListWebhooks)
+		r.Post("/", webhookHandler.CreateWebhook)
+		r.Get("/{id}", webhookHandler.GetWebhook)
+		r.Put("/{id}", webhookHandler.UpdateWebhook)
+		r.Delete("/{id}", webhookHandler.DeleteWebhook)
+		r.Get("/{id}/deliveries", webhookHandler.ListWebhookDeliveries)
+		r.Post("/{id}/deliveries/{deliveryID}/redeliver", webhookHandler.RedeliverWebhook)
+	})
+
 	router.With(middleware.Authenticate).Get("/ws/tournaments/{tournamentID}", webSocketHandler.ServeWs)
+	router.Get("/ws", webSocketHandler.ServeWsProtocol)
 
 	router.Route("/admin", func(r chi.Router) {
 		r.Use(middleware.Authenticate)
@@ -138,6 +253,16 @@ func SetupRoutes(
 			r.Delete("/{id}", adminHandler.DeleteUser)
 			r.Get("/dashboard", dashboardHandler.Stats)
 		})
+
+		r.Post("/ratings/rebuild", ratingHandler.RebuildRatings)
+
+		r.Route("/emails", func(r chi.Router) {
+			r.Get("/", emailOutboxHandler.ListEmails)
+			r.Get("/{id}", emailOutboxHandler.GetEmail)
+			r.Post("/{id}/resend", emailOutboxHandler.ResendEmail)
+		})
+
+		r.Post("/storage/gc", storageHandler.CollectGarbage)
 	})
 
 	router.Get("/confirm-email", authHandler.ConfirmEmail)
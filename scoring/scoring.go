@@ -0,0 +1,317 @@
+// Package scoring разбирает и проверяет счёт матча по правилам вида спорта
+// и определяет по нему победителя.
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+var (
+	ErrInvalidScore    = errors.New("invalid score")
+	ErrDrawNotAllowed  = errors.New("draw is not allowed in this sport")
+	ErrIncompleteScore = errors.New("score does not decide the match")
+	ErrInvalidRules    = errors.New("invalid scoring rules")
+)
+
+type Winner int
+
+const (
+	WinnerNone   Winner = iota // Ничья (или матч ещё не решён)
+	WinnerFirst                // P1/T1
+	WinnerSecond               // P2/T2
+)
+
+// Set — один сет/партия схемы sets.
+type Set struct {
+	Score1   int  `json:"score1"`
+	Score2   int  `json:"score2"`
+	Tiebreak *int `json:"tiebreak,omitempty"` // Очки проигравшего тай-брейк: 7-6(5)
+}
+
+// Result — разобранный счёт.
+// Score1/Score2 — очки, выигранные сеты или время в миллисекундах (в зависимости от схемы).
+type Result struct {
+	Score1 int
+	Score2 int
+	Sets   []Set
+	Winner Winner
+}
+
+var setPattern = regexp.MustCompile(`^(\d+)-(\d+)(?:\((\d+)\))?$`)
+
+// Parse разбирает счёт по правилам. final == false — промежуточный счёт идущего матча:
+// проверяется формат и то, что счёт ещё может быть достигнут, но не то, что матч решён.
+func Parse(rules models.ScoringRules, score string, final bool) (*Result, error) {
+	score = strings.TrimSpace(score)
+	if score == "" {
+		return nil, fmt.Errorf("%w: score is empty", ErrInvalidScore)
+	}
+
+	var res *Result
+	var err error
+	switch rules.Schema {
+	case models.ScoreSchemaPoints, "":
+		res, err = parsePoints(rules, score, final)
+	case models.ScoreSchemaSets:
+		res, err = parseSets(rules, score, final)
+	case models.ScoreSchemaTime:
+		res, err = parseTime(rules, score)
+	default:
+		return nil, fmt.Errorf("%w: unknown score schema '%s'", ErrInvalidRules, rules.Schema)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if final && res.Winner == WinnerNone && !rules.DrawAllowed {
+		return nil, fmt.Errorf("%w: '%s'", ErrDrawNotAllowed, score)
+	}
+	return res, nil
+}
+
+// Summarize — пара чисел для таблиц и табло, когда правила спорта под рукой нет:
+// пробует очки, затем сеты (число выигранных), затем время.
+func Summarize(score string) (int, int, bool) {
+	for _, schema := range []models.ScoreSchema{models.ScoreSchemaPoints, models.ScoreSchemaSets, models.ScoreSchemaTime} {
+		if res, err := Parse(models.ScoringRules{Schema: schema, DrawAllowed: true}, score, false); err == nil {
+			return res.Score1, res.Score2, true
+		}
+	}
+	return 0, 0, false
+}
+
+// ValidateRules проверяет согласованность настроек спорта.
+func ValidateRules(rules models.ScoringRules) error {
+	if rules.RaceTo < 0 || rules.SetsToWin < 0 || rules.PointsPerSet < 0 || rules.DecidingSetPoints < 0 || rules.WinBy < 0 || rules.TiebreakAt < 0 {
+		return fmt.Errorf("%w: values cannot be negative", ErrInvalidRules)
+	}
+	setOptions := rules.SetsToWin > 0 || rules.PointsPerSet > 0 || rules.DecidingSetPoints > 0 || rules.WinBy > 0 || rules.TiebreakAt > 0
+
+	switch rules.Schema {
+	case models.ScoreSchemaPoints:
+		if setOptions {
+			return fmt.Errorf("%w: set options require the sets schema", ErrInvalidRules)
+		}
+		if rules.RaceTo > 0 && (rules.DrawAllowed || rules.LowerIsBetter) {
+			return fmt.Errorf("%w: race_to cannot be combined with draws or lower_is_better", ErrInvalidRules)
+		}
+	case models.ScoreSchemaSets:
+		if rules.RaceTo > 0 || rules.LowerIsBetter {
+			return fmt.Errorf("%w: race_to and lower_is_better are not supported for sets", ErrInvalidRules)
+		}
+		if rules.SetsToWin > 0 && rules.DrawAllowed {
+			return fmt.Errorf("%w: a best-of match cannot end in a draw", ErrInvalidRules)
+		}
+		if rules.PointsPerSet == 0 && (rules.DecidingSetPoints > 0 || rules.WinBy > 0 || rules.TiebreakAt > 0) {
+			return fmt.Errorf("%w: points_per_set is required for set length options", ErrInvalidRules)
+		}
+		if rules.DecidingSetPoints > 0 && rules.SetsToWin == 0 {
+			return fmt.Errorf("%w: deciding_set_points requires sets_to_win", ErrInvalidRules)
+		}
+	case models.ScoreSchemaTime:
+		if setOptions || rules.RaceTo > 0 {
+			return fmt.Errorf("%w: time schema has no sets or race_to", ErrInvalidRules)
+		}
+	default:
+		return fmt.Errorf("%w: unknown score schema '%s'", ErrInvalidRules, rules.Schema)
+	}
+	return nil
+}
+
+func parsePoints(rules models.ScoringRules, score string, final bool) (*Result, error) {
+	s1, s2, err := splitPair(score, strconv.Atoi)
+	if err != nil {
+		return nil, err
+	}
+	if s1 < 0 || s2 < 0 {
+		return nil, fmt.Errorf("%w: scores cannot be negative in '%s'", ErrInvalidScore, score)
+	}
+
+	if rules.RaceTo > 0 {
+		if s1 > rules.RaceTo || s2 > rules.RaceTo || (s1 == rules.RaceTo && s2 == rules.RaceTo) {
+			return nil, fmt.Errorf("%w: match is played to %d in '%s'", ErrInvalidScore, rules.RaceTo, score)
+		}
+		if final && s1 != rules.RaceTo && s2 != rules.RaceTo {
+			return nil, fmt.Errorf("%w: one side must reach %d", ErrIncompleteScore, rules.RaceTo)
+		}
+	}
+	return &Result{Score1: s1, Score2: s2, Winner: compare(s1, s2, rules.LowerIsBetter)}, nil
+}
+
+func parseSets(rules models.ScoringRules, score string, final bool) (*Result, error) {
+	parts := strings.Fields(strings.ReplaceAll(score, ",", " "))
+	res := &Result{Sets: make([]Set, 0, len(parts))}
+
+	for i, part := range parts {
+		m := setPattern.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("%w: set '%s' must look like '6-4' or '7-6(5)'", ErrInvalidScore, part)
+		}
+		set := Set{}
+		set.Score1, _ = strconv.Atoi(m[1])
+		set.Score2, _ = strconv.Atoi(m[2])
+		if m[3] != "" {
+			tb, _ := strconv.Atoi(m[3])
+			set.Tiebreak = &tb
+		}
+
+		if rules.SetsToWin > 0 && (res.Score1 == rules.SetsToWin || res.Score2 == rules.SetsToWin) {
+			return nil, fmt.Errorf("%w: set %d is played after the match was decided", ErrInvalidScore, i+1)
+		}
+
+		// Последний сет идущего матча может быть не доигран
+		inProgress := !final && i == len(parts)-1
+		complete, err := checkSet(rules, set, i, inProgress)
+		if err != nil {
+			return nil, fmt.Errorf("%w: set %d ('%s'): %v", ErrInvalidScore, i+1, part, err)
+		}
+		if complete {
+			if set.Score1 > set.Score2 {
+				res.Score1++
+			} else {
+				res.Score2++
+			}
+		}
+		res.Sets = append(res.Sets, set)
+	}
+
+	if final && rules.SetsToWin > 0 && res.Score1 != rules.SetsToWin && res.Score2 != rules.SetsToWin {
+		return nil, fmt.Errorf("%w: a side must win %d sets", ErrIncompleteScore, rules.SetsToWin)
+	}
+	res.Winner = compare(res.Score1, res.Score2, false)
+	return res, nil
+}
+
+// checkSet проверяет счёт сета; complete == false — сет ещё идёт.
+func checkSet(rules models.ScoringRules, set Set, index int, inProgress bool) (bool, error) {
+	hi, lo := set.Score1, set.Score2
+	if lo > hi {
+		hi, lo = lo, hi
+	}
+	target := rules.PointsPerSet
+	if rules.DecidingSetPoints > 0 && rules.SetsToWin > 0 && index == 2*rules.SetsToWin-2 {
+		target = rules.DecidingSetPoints
+	}
+	winBy := rules.WinBy
+	if winBy == 0 {
+		winBy = 1
+	}
+	isTiebreak := rules.TiebreakAt > 0 && hi == rules.TiebreakAt+1 && lo == rules.TiebreakAt
+
+	// Без заданной длины сета нотация тай-брейка принимается как есть
+	if set.Tiebreak != nil && !isTiebreak && rules.PointsPerSet > 0 {
+		return false, errors.New("tiebreak is only possible in a tiebreak set")
+	}
+
+	var complete bool
+	var err error
+	switch {
+	case target == 0:
+		complete = hi != lo
+		if !complete {
+			err = errors.New("set cannot end level")
+		}
+	case isTiebreak:
+		complete = true
+	case hi < target:
+		err = fmt.Errorf("set is played to %d", target)
+	case hi == target:
+		complete = hi-lo >= winBy
+		if !complete {
+			err = fmt.Errorf("set must be won by %d", winBy)
+		}
+	default: // Затянувшийся сет: заканчивается, как только отрыв достигает winBy
+		// Такой счёт недостижим даже в идущем сете
+		if hi-lo > winBy || rules.TiebreakAt > 0 && (hi > rules.TiebreakAt+1 || lo > rules.TiebreakAt) {
+			return false, fmt.Errorf("set over %d must end with a margin of exactly %d", target, winBy)
+		}
+		complete = hi-lo == winBy
+		if !complete {
+			err = fmt.Errorf("set must be won by %d", winBy)
+		}
+	}
+
+	if err != nil && !inProgress {
+		return false, err
+	}
+	return complete, nil
+}
+
+func parseTime(rules models.ScoringRules, score string) (*Result, error) {
+	t1, t2, err := splitPair(score, parseDurationMillis)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Score1: t1, Score2: t2, Winner: compare(t1, t2, rules.LowerIsBetter)}, nil
+}
+
+// parseDurationMillis разбирает "h:mm:ss.fff", "m:ss.fff" или "ss.fff" в миллисекунды.
+func parseDurationMillis(s string) (int, error) {
+	secPart := s
+	var prefix []string
+	if idx := strings.LastIndex(s, ":"); idx >= 0 {
+		prefix = strings.Split(s[:idx], ":")
+		secPart = s[idx+1:]
+	}
+	if len(prefix) > 2 {
+		return 0, fmt.Errorf("too many ':' in '%s'", s)
+	}
+
+	whole, frac, hasFrac := strings.Cut(secPart, ".")
+	secs, err := strconv.Atoi(whole)
+	if err != nil || secs < 0 || (len(prefix) > 0 && (len(whole) != 2 || secs >= 60)) {
+		return 0, fmt.Errorf("invalid seconds in '%s'", s)
+	}
+	millis := secs * 1000
+	if hasFrac {
+		if frac == "" || len(frac) > 3 {
+			return 0, fmt.Errorf("invalid fraction in '%s'", s)
+		}
+		f, err := strconv.Atoi(frac + strings.Repeat("0", 3-len(frac)))
+		if err != nil || f < 0 {
+			return 0, fmt.Errorf("invalid fraction in '%s'", s)
+		}
+		millis += f
+	}
+
+	unit := 60 * 1000
+	for i := len(prefix) - 1; i >= 0; i-- {
+		v, err := strconv.Atoi(prefix[i])
+		if err != nil || v < 0 || (i > 0 && (len(prefix[i]) != 2 || v >= 60)) {
+			return 0, fmt.Errorf("invalid time '%s'", s)
+		}
+		millis += v * unit
+		unit *= 60
+	}
+	return millis, nil
+}
+
+func splitPair(score string, parse func(string) (int, error)) (int, int, error) {
+	parts := strings.Split(score, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%w: '%s' must be in 'S1-S2' format", ErrInvalidScore, score)
+	}
+	s1, err1 := parse(strings.TrimSpace(parts[0]))
+	s2, err2 := parse(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("%w: could not parse '%s'", ErrInvalidScore, score)
+	}
+	return s1, s2, nil
+}
+
+func compare(s1, s2 int, lowerIsBetter bool) Winner {
+	switch {
+	case s1 == s2:
+		return WinnerNone
+	case (s1 > s2) != lowerIsBetter:
+		return WinnerFirst
+	default:
+		return WinnerSecond
+	}
+}
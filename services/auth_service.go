@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"time"
 
 	"github.com/Dosada05/tournament-system/models"
@@ -31,6 +33,8 @@ type RegisterInput struct {
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
 	Password  string `json:"password"`
+	Locale    string `json:"locale"`   // Необязателен, по умолчанию ru
+	Timezone  string `json:"timezone"` // IANA, например Europe/Moscow; по умолчанию UTC
 }
 
 type LoginInput struct {
@@ -39,12 +43,26 @@ type LoginInput struct {
 }
 
 type authService struct {
-	userRepo repositories.UserRepository
+	db            *sql.DB
+	userRepo      repositories.UserRepository
+	emailService  *EmailService
+	outboxService EmailOutboxService
+	logger        *slog.Logger
 }
 
-func NewAuthService(userRepo repositories.UserRepository) AuthService {
+func NewAuthService(
+	db *sql.DB,
+	userRepo repositories.UserRepository,
+	emailService *EmailService,
+	outboxService EmailOutboxService,
+	logger *slog.Logger,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
+		db:            db,
+		userRepo:      userRepo,
+		emailService:  emailService,
+		outboxService: outboxService,
+		logger:        logger,
 	}
 }
 
@@ -65,9 +83,25 @@ func (s *authService) Register(ctx context.Context, input RegisterInput) (*model
 		Role:                   models.RolePlayer,
 		EmailConfirmed:         false,
 		EmailConfirmationToken: confirmationToken,
+		Locale:                 models.DefaultLocale,
+		Timezone:               models.DefaultTimezone,
+	}
+	if _, err := applyLocaleSettings(user, input.Locale, input.Timezone); err != nil {
+		return nil, "", err
 	}
 
-	err = s.userRepo.Create(ctx, user)
+	welcomeEmail, err := s.emailService.BuildWelcomeEmail(RecipientFromUser(user), confirmationToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Пользователь и приветственное письмо сохраняются вместе: письмо не потеряется и не уйдёт без пользователя
+	err = runInTransaction(ctx, s.db, s.logger, func(tx repositories.SQLExecutor) error {
+		if err := s.userRepo.Create(ctx, tx, user); err != nil {
+			return err
+		}
+		return s.outboxService.Enqueue(ctx, tx, welcomeEmail)
+	})
 	if err != nil {
 		if errors.Is(err, ErrAuthEmailTaken) {
 			return nil, "", ErrAuthEmailTaken
@@ -120,8 +154,17 @@ func (s *authService) GeneratePasswordResetToken(ctx context.Context, email stri
 		return "", nil
 	}
 	resetToken := generateRandomToken(32)
-	// Сохраняем токен и время его создания в БД
-	err = s.userRepo.SetPasswordResetToken(ctx, user.ID, resetToken, time.Now().Add(1*time.Hour))
+	resetEmail, err := s.emailService.BuildPasswordResetEmail(RecipientFromUser(user), resetToken)
+	if err != nil {
+		return "", err
+	}
+	// Сохраняем токен и время его создания в БД вместе с письмом
+	err = runInTransaction(ctx, s.db, s.logger, func(tx repositories.SQLExecutor) error {
+		if err := s.userRepo.SetPasswordResetToken(ctx, tx, user.ID, resetToken, time.Now().Add(1*time.Hour)); err != nil {
+			return err
+		}
+		return s.outboxService.Enqueue(ctx, tx, resetEmail)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog" // Switched to slog
+	"math/rand/v2"
 	"time"
 
 	"github.com/Dosada05/tournament-system/brackets"
@@ -23,9 +27,29 @@ type TournamentWinnerPayload struct {
 	ParticipantIds []int               `json:"participant_ids"`
 }
 
+var (
+	ErrBracketPreviewNotFound              = repositories.ErrBracketPreviewNotFound
+	ErrBracketPreviewInvalidOrder          = errors.New("participant order must list every confirmed participant exactly once")
+	ErrBracketPreviewStale                 = errors.New("locked bracket preview no longer matches confirmed participants or format")
+	ErrBracketPreviewNotAllowed            = errors.New("bracket preview is only available before the tournament starts")
+	ErrBracketPreviewNotEnoughParticipants = errors.New("not enough confirmed participants to build a bracket")
+)
+
+// BracketPreviewInput задаёт порядок участников для пробной генерации.
+// Без порядка и перемешивания используется тот же порядок, что и при активации.
+type BracketPreviewInput struct {
+	ParticipantOrder []int `json:"participant_order,omitempty"` // ID участников (participants.id)
+	Seeded           bool  `json:"seeded,omitempty"`            // Порядок — это посев (1-й против последнего)
+	Shuffle          bool  `json:"shuffle,omitempty"`
+}
+
 type BracketService interface {
 	GenerateAndSaveBracket(ctx context.Context, exec repositories.SQLExecutor, tournament *models.Tournament) (interface{}, error)
 	GetFullTournamentData(ctx context.Context, tournamentID int, formatID int) (*models.Tournament, error) // Kept for now
+	PreviewBracket(ctx context.Context, tournament *models.Tournament, input BracketPreviewInput) (*models.BracketPreview, error)
+	LockBracketPreview(ctx context.Context, tournament *models.Tournament, userID int, input BracketPreviewInput) (*models.BracketPreview, error)
+	GetLockedBracketPreview(ctx context.Context, tournamentID int) (*models.BracketPreview, error)
+	UnlockBracketPreview(ctx context.Context, tournamentID int) error
 }
 
 type bracketService struct {
@@ -34,7 +58,9 @@ type bracketService struct {
 	soloMatchRepo   repositories.SoloMatchRepository
 	teamMatchRepo   repositories.TeamMatchRepository
 	standingRepo    repositories.TournamentStandingRepository // Added
-	logger          *slog.Logger                              // Added
+	previewRepo     repositories.BracketPreviewRepository
+	ratingService   RatingService
+	logger          *slog.Logger // Added
 }
 
 func NewBracketService(
@@ -43,6 +69,8 @@ func NewBracketService(
 	soloMatchRepo repositories.SoloMatchRepository,
 	teamMatchRepo repositories.TeamMatchRepository,
 	standingRepo repositories.TournamentStandingRepository, // Added
+	previewRepo repositories.BracketPreviewRepository,
+	ratingService RatingService,
 	logger *slog.Logger, // Added
 ) BracketService {
 	return &bracketService{
@@ -51,7 +79,9 @@ func NewBracketService(
 		soloMatchRepo:   soloMatchRepo,
 		teamMatchRepo:   teamMatchRepo,
 		standingRepo:    standingRepo, // Added
-		logger:          logger,       // Added
+		previewRepo:     previewRepo,
+		ratingService:   ratingService,
+		logger:          logger, // Added
 	}
 }
 
@@ -83,26 +113,26 @@ func (s *bracketService) GenerateAndSaveBracket(ctx context.Context, exec reposi
 		return nil, fmt.Errorf("GenerateAndSaveBracket: not enough participants (found %d, min 2)", len(dbParticipants))
 	}
 
-	var bracketGenerator brackets.BracketGenerator
-	switch tournament.Format.BracketType {
-	case "SingleElimination":
-		bracketGenerator = brackets.NewSingleEliminationGenerator()
-	case "RoundRobin":
-		bracketGenerator = brackets.NewRoundRobinGenerator()
+	var generatedBracketMatches []*brackets.BracketMatch
+	lockedPreview, lockErr := s.previewRepo.GetByTournament(ctx, exec, tournament.ID)
+	switch {
+	case lockErr == nil:
+		// Организатор зафиксировал предпросмотр — используем ровно эту раскладку
+		if !previewMatchesTournament(lockedPreview, tournament.Format, dbParticipants) {
+			s.logger.WarnContext(ctx, "GenerateAndSaveBracket: locked preview is stale", slog.Int("tournament_id", tournament.ID))
+			return nil, ErrBracketPreviewStale
+		}
+		generatedBracketMatches = fromPreviewMatches(lockedPreview.Matches)
+		s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Using locked bracket preview", slog.Int("tournament_id", tournament.ID))
+	case errors.Is(lockErr, repositories.ErrBracketPreviewNotFound):
+		var genErr error
+		generatedBracketMatches, _, _, genErr = s.buildBracket(ctx, tournament, dbParticipants, BracketPreviewInput{})
+		if genErr != nil {
+			return nil, genErr
+		}
 	default:
-		s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: unsupported bracket type", slog.String("bracket_type", tournament.Format.BracketType))
-		return nil, fmt.Errorf("unsupported bracket type '%s'", tournament.Format.BracketType)
-	}
-	s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Using generator", slog.String("generator_name", bracketGenerator.GetName()))
-
-	params := brackets.GenerateBracketParams{
-		Tournament:   tournament,
-		Participants: dbParticipants,
-	}
-	generatedBracketMatches, genErr := bracketGenerator.GenerateBracket(ctx, params)
-	if genErr != nil {
-		s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: failed to generate bracket structure", slog.Any("error", genErr))
-		return nil, fmt.Errorf("GenerateAndSaveBracket: failed to generate structure: %w", genErr)
+		s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: failed to load locked preview", slog.Int("tournament_id", tournament.ID), slog.Any("error", lockErr))
+		return nil, fmt.Errorf("GenerateAndSaveBracket: failed to load locked preview: %w", lockErr)
 	}
 	if len(generatedBracketMatches) == 0 && len(dbParticipants) >= 2 && tournament.Format.BracketType != "RoundRobin" { // RoundRobin might have 0 matches if only 1 participant (though we check for <2)
 		s.logger.WarnContext(ctx, "GenerateAndSaveBracket: no matches generated", slog.Int("participant_count", len(dbParticipants)))
@@ -266,10 +296,246 @@ func (s *bracketService) GenerateAndSaveBracket(ctx context.Context, exec reposi
 		s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Standings initialized", slog.Int("tournament_id", tournament.ID), slog.Int("standings_count", len(standingsToCreate)))
 	}
 
+	if lockedPreview != nil {
+		if err := s.previewRepo.Delete(ctx, exec, tournament.ID); err != nil && !errors.Is(err, repositories.ErrBracketPreviewNotFound) {
+			return nil, fmt.Errorf("GenerateAndSaveBracket: failed to release locked preview: %w", err)
+		}
+	}
+
 	s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Bracket processing completed successfully.", slog.Int("tournament_id", tournament.ID))
 	return createdDBMatchEntities, nil
 }
 
+// buildBracket упорядочивает участников и запускает генератор сетки без сохранения в БД.
+func (s *bracketService) buildBracket(ctx context.Context, tournament *models.Tournament, dbParticipants []*models.Participant, input BracketPreviewInput) ([]*brackets.BracketMatch, []*models.Participant, bool, error) {
+	var bracketGenerator brackets.BracketGenerator
+	switch tournament.Format.BracketType {
+	case "SingleElimination":
+		bracketGenerator = brackets.NewSingleEliminationGenerator()
+	case "RoundRobin":
+		bracketGenerator = brackets.NewRoundRobinGenerator()
+	default:
+		s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: unsupported bracket type", slog.String("bracket_type", tournament.Format.BracketType))
+		return nil, nil, false, fmt.Errorf("unsupported bracket type '%s'", tournament.Format.BracketType)
+	}
+	s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Using generator", slog.String("generator_name", bracketGenerator.GetName()))
+
+	params := brackets.GenerateBracketParams{
+		Tournament:   tournament,
+		Participants: dbParticipants,
+	}
+	switch {
+	case len(input.ParticipantOrder) > 0:
+		if input.Shuffle {
+			return nil, nil, false, fmt.Errorf("%w: shuffle cannot be combined with an explicit order", ErrBracketPreviewInvalidOrder)
+		}
+		ordered, err := orderParticipants(dbParticipants, input.ParticipantOrder)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		params.Participants = ordered
+		params.Seeded = input.Seeded
+	case input.Shuffle:
+		shuffled := make([]*models.Participant, len(dbParticipants))
+		copy(shuffled, dbParticipants)
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		params.Participants = shuffled
+	case tournament.SeedByRating && s.ratingService != nil:
+		seeded, seedErr := s.ratingService.SeedParticipants(ctx, tournament.SportID, dbParticipants)
+		if seedErr != nil {
+			s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: failed to seed participants by rating", slog.Int("tournament_id", tournament.ID), slog.Any("error", seedErr))
+			return nil, nil, false, fmt.Errorf("GenerateAndSaveBracket: failed to seed participants: %w", seedErr)
+		}
+		params.Participants = seeded
+		params.Seeded = true
+		s.logger.InfoContext(ctx, "GenerateAndSaveBracket: Participants seeded by rating", slog.Int("tournament_id", tournament.ID))
+	}
+	generatedBracketMatches, genErr := bracketGenerator.GenerateBracket(ctx, params)
+	if genErr != nil {
+		s.logger.ErrorContext(ctx, "GenerateAndSaveBracket: failed to generate bracket structure", slog.Any("error", genErr))
+		return nil, nil, false, fmt.Errorf("GenerateAndSaveBracket: failed to generate structure: %w", genErr)
+	}
+	return generatedBracketMatches, params.Participants, params.Seeded, nil
+}
+
+// PreviewBracket прогоняет генератор по текущим подтверждённым участникам и ничего не сохраняет.
+func (s *bracketService) PreviewBracket(ctx context.Context, tournament *models.Tournament, input BracketPreviewInput) (*models.BracketPreview, error) {
+	if tournament.Format == nil {
+		format, err := s.formatRepo.GetByID(ctx, tournament.FormatID)
+		if err != nil {
+			return nil, fmt.Errorf("PreviewBracket: failed to load format %d: %w", tournament.FormatID, err)
+		}
+		tournament.Format = format
+	}
+
+	statusConfirmed := models.StatusParticipant
+	dbParticipants, err := s.participantRepo.ListByTournament(ctx, tournament.ID, &statusConfirmed, true)
+	if err != nil {
+		return nil, fmt.Errorf("PreviewBracket: failed to list participants for tournament %d: %w", tournament.ID, err)
+	}
+	if len(dbParticipants) < 2 {
+		return nil, fmt.Errorf("%w: found %d, min 2", ErrBracketPreviewNotEnoughParticipants, len(dbParticipants))
+	}
+
+	settings, err := tournament.Format.ParsedSettings()
+	if err != nil {
+		return nil, fmt.Errorf("PreviewBracket: format %d: %w", tournament.Format.ID, err)
+	}
+
+	generated, ordered, seeded, err := s.buildBracket(ctx, tournament, dbParticipants, input)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.BracketPreview{
+		TournamentID:   tournament.ID,
+		BracketType:    tournament.Format.BracketType,
+		FormatSettings: settings,
+		ParticipantIDs: make([]int, len(ordered)),
+		Seeded:         seeded,
+		Matches:        toPreviewMatches(generated),
+		Participants:   make([]models.Participant, len(ordered)),
+	}
+	for i, p := range ordered {
+		preview.ParticipantIDs[i] = p.ID
+		preview.Participants[i] = *p
+	}
+	return preview, nil
+}
+
+// LockBracketPreview фиксирует раскладку: при активации турнира будет использована именно она.
+func (s *bracketService) LockBracketPreview(ctx context.Context, tournament *models.Tournament, userID int, input BracketPreviewInput) (*models.BracketPreview, error) {
+	preview, err := s.PreviewBracket(ctx, tournament, input)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 {
+		preview.LockedBy = &userID
+	}
+	if err := s.previewRepo.Save(ctx, nil, preview); err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "LockBracketPreview: failed to save preview for tournament %d", tournament.ID)
+	}
+	s.logger.InfoContext(ctx, "Bracket preview locked", slog.Int("tournament_id", tournament.ID), slog.Int("user_id", userID))
+	return preview, nil
+}
+
+func (s *bracketService) GetLockedBracketPreview(ctx context.Context, tournamentID int) (*models.BracketPreview, error) {
+	preview, err := s.previewRepo.GetByTournament(ctx, nil, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrBracketPreviewNotFound, "GetLockedBracketPreview: tournament %d", tournamentID)
+	}
+	return preview, nil
+}
+
+func (s *bracketService) UnlockBracketPreview(ctx context.Context, tournamentID int) error {
+	if err := s.previewRepo.Delete(ctx, nil, tournamentID); err != nil {
+		return handleRepositoryError(err, ErrBracketPreviewNotFound, "UnlockBracketPreview: tournament %d", tournamentID)
+	}
+	return nil
+}
+
+// orderParticipants переставляет участников в заданном порядке; порядок должен
+// содержать каждого подтверждённого участника ровно один раз.
+func orderParticipants(participants []*models.Participant, order []int) ([]*models.Participant, error) {
+	if len(order) != len(participants) {
+		return nil, fmt.Errorf("%w: expected %d participants, got %d", ErrBracketPreviewInvalidOrder, len(participants), len(order))
+	}
+	byID := make(map[int]*models.Participant, len(participants))
+	for _, p := range participants {
+		byID[p.ID] = p
+	}
+	ordered := make([]*models.Participant, 0, len(order))
+	for _, id := range order {
+		p, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: participant %d is not confirmed or listed twice", ErrBracketPreviewInvalidOrder, id)
+		}
+		delete(byID, id)
+		ordered = append(ordered, p)
+	}
+	return ordered, nil
+}
+
+// previewMatchesTournament проверяет, что зафиксированный предпросмотр построен для того же
+// типа сетки с теми же настройками формата и для того же набора подтверждённых участников.
+func previewMatchesTournament(preview *models.BracketPreview, format *models.Format, participants []*models.Participant) bool {
+	if preview.BracketType != format.BracketType || len(preview.ParticipantIDs) != len(participants) {
+		return false
+	}
+	if !previewSettingsMatch(preview, format) {
+		return false
+	}
+	current := make(map[int]bool, len(participants))
+	for _, p := range participants {
+		current[p.ID] = true
+	}
+	for _, id := range preview.ParticipantIDs {
+		if !current[id] {
+			return false
+		}
+		delete(current, id)
+	}
+	return len(current) == 0
+}
+
+// previewSettingsMatch сравнивает настройки через JSON: после хранения в JSONB числа
+// возвращаются как float64, а json.Marshal сортирует ключи.
+func previewSettingsMatch(preview *models.BracketPreview, format *models.Format) bool {
+	if preview.FormatSettings == nil {
+		return false
+	}
+	current, err := format.ParsedSettings()
+	if err != nil {
+		return false
+	}
+	locked, err := json.Marshal(preview.FormatSettings)
+	if err != nil {
+		return false
+	}
+	actual, err := json.Marshal(current)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(locked, actual)
+}
+
+func toPreviewMatches(matches []*brackets.BracketMatch) []models.BracketPreviewMatch {
+	result := make([]models.BracketPreviewMatch, 0, len(matches))
+	for _, bm := range matches {
+		result = append(result, models.BracketPreviewMatch{
+			UID:              bm.UID,
+			Round:            bm.Round,
+			OrderInRound:     bm.OrderInRound,
+			Participant1ID:   bm.Participant1ID,
+			Participant2ID:   bm.Participant2ID,
+			SourceMatch1UID:  bm.SourceMatch1UID,
+			SourceMatch2UID:  bm.SourceMatch2UID,
+			IsBye:            bm.IsBye,
+			ByeParticipantID: bm.ByeParticipantID,
+		})
+	}
+	return result
+}
+
+func fromPreviewMatches(matches []models.BracketPreviewMatch) []*brackets.BracketMatch {
+	result := make([]*brackets.BracketMatch, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, &brackets.BracketMatch{
+			UID:              m.UID,
+			Round:            m.Round,
+			OrderInRound:     m.OrderInRound,
+			Participant1ID:   m.Participant1ID,
+			Participant2ID:   m.Participant2ID,
+			SourceMatch1UID:  m.SourceMatch1UID,
+			SourceMatch2UID:  m.SourceMatch2UID,
+			IsPlaceholder:    m.SourceMatch1UID != nil || m.SourceMatch2UID != nil,
+			IsBye:            m.IsBye,
+			ByeParticipantID: m.ByeParticipantID,
+		})
+	}
+	return result
+}
+
 // GetFullTournamentData остается без изменений, он не использует транзакции этого сервиса
 // и предназначен для чтения полного состояния сетки для отображения.
 func (s *bracketService) GetFullTournamentData(ctx context.Context, tournamentID int, formatID int) (*models.Tournament, error) {
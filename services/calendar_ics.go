@@ -0,0 +1,50 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsMaxLineOctets — предел длины строки по RFC 5545; длинные строки переносятся.
+const icsMaxLineOctets = 75
+
+const icsTimeFormat = "20060102T150405Z"
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icsWriter формирует iCalendar-документ: CRLF в конце строк и перенос строк длиннее 75 байт.
+type icsWriter struct {
+	buf bytes.Buffer
+}
+
+// prop записывает свойство как есть; значение должно быть уже экранировано.
+func (w *icsWriter) prop(name, value string) {
+	line := name + ":" + value
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1 // Строка продолжения начинается с пробела
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+func (w *icsWriter) text(name, value string) {
+	w.prop(name, icsTextEscaper.Replace(value))
+}
+
+func (w *icsWriter) time(name string, t time.Time) {
+	w.prop(name, t.UTC().Format(icsTimeFormat))
+}
+
+func (w *icsWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+const (
+	// calendarMatchDuration — длительность события: время окончания матча в расписании не хранится.
+	calendarMatchDuration = time.Hour
+	calendarTokenBytes    = 24
+	calendarProductID     = "-//tournament-system//Match Calendar//EN"
+	calendarRefresh       = "PT1H"
+)
+
+var ErrCalendarTokenNotFound = repositories.ErrCalendarTokenNotFound
+
+type CalendarService interface {
+	// TournamentFeed — все матчи турнира в формате iCalendar.
+	TournamentFeed(ctx context.Context, tournamentID int) ([]byte, error)
+	TeamFeed(ctx context.Context, teamID int) ([]byte, error)
+	// UserFeed — персональная лента по секретному токену: одиночные матчи и матчи команды пользователя.
+	UserFeed(ctx context.Context, token string) ([]byte, error)
+	// UserFeedToken возвращает токен персональной ленты, выпуская его при первом обращении.
+	UserFeedToken(ctx context.Context, userID int) (string, error)
+	// ResetUserFeedToken перевыпускает токен; старая ссылка перестаёт работать.
+	ResetUserFeedToken(ctx context.Context, userID int) (string, error)
+}
+
+type calendarService struct {
+	calendarRepo   repositories.CalendarRepository
+	tournamentRepo repositories.TournamentRepository
+	teamRepo       repositories.TeamRepository
+	userRepo       repositories.UserRepository
+	publicURL      string
+	uidDomain      string
+}
+
+func NewCalendarService(
+	calendarRepo repositories.CalendarRepository,
+	tournamentRepo repositories.TournamentRepository,
+	teamRepo repositories.TeamRepository,
+	userRepo repositories.UserRepository,
+	publicURL string,
+) CalendarService {
+	// UID событий привязаны к домену сайта, чтобы не пересекаться с событиями других календарей
+	uidDomain := "tournament-system"
+	if u, err := url.Parse(publicURL); err == nil && u.Hostname() != "" {
+		uidDomain = u.Hostname()
+	}
+	return &calendarService{
+		calendarRepo:   calendarRepo,
+		tournamentRepo: tournamentRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		publicURL:      strings.TrimRight(publicURL, "/"),
+		uidDomain:      uidDomain,
+	}
+}
+
+func (s *calendarService) TournamentFeed(ctx context.Context, tournamentID int) ([]byte, error) {
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	matches, err := s.calendarRepo.ListByTournament(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(tournament.Name, models.DefaultLocale, matches), nil
+}
+
+func (s *calendarService) TeamFeed(ctx context.Context, teamID int) ([]byte, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTeamNotFound, "failed to get team %d", teamID)
+	}
+	matches, err := s.calendarRepo.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf(calendarTexts[models.DefaultLocale].TeamCalendar, team.Name)
+	return s.render(name, models.DefaultLocale, matches), nil
+}
+
+func (s *calendarService) UserFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarTokenNotFound
+	}
+	userID, err := s.calendarRepo.GetUserIDByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrUserNotFound, "failed to get user %d", userID)
+	}
+	matches, err := s.calendarRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	locale := models.DefaultLocale
+	if l, ok := models.NormalizeLocale(user.Locale); ok {
+		locale = l
+	}
+	return s.render(calendarTexts[locale].UserCalendar, locale, matches), nil
+}
+
+func (s *calendarService) UserFeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := s.calendarRepo.GetToken(ctx, userID)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, repositories.ErrCalendarTokenNotFound) {
+		return "", err
+	}
+	return s.ResetUserFeedToken(ctx, userID)
+}
+
+func (s *calendarService) ResetUserFeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := generateSecureToken(calendarTokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	if err := s.calendarRepo.SetToken(ctx, userID, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *calendarService) render(name, locale string, matches []models.CalendarMatch) []byte {
+	texts, ok := calendarTexts[locale]
+	if !ok {
+		texts = calendarTexts[models.DefaultLocale]
+	}
+	now := time.Now()
+
+	var w icsWriter
+	w.prop("BEGIN", "VCALENDAR")
+	w.prop("VERSION", "2.0")
+	w.prop("PRODID", calendarProductID)
+	w.prop("CALSCALE", "GREGORIAN")
+	w.prop("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", name)
+	w.prop("REFRESH-INTERVAL;VALUE=DURATION", calendarRefresh)
+	w.prop("X-PUBLISHED-TTL", calendarRefresh)
+
+	for _, m := range matches {
+		side1, side2 := texts.TBD, texts.TBD
+		if m.Side1Name != nil && *m.Side1Name != "" {
+			side1 = *m.Side1Name
+		}
+		if m.Side2Name != nil && *m.Side2Name != "" {
+			side2 = *m.Side2Name
+		}
+
+		details := []string{m.TournamentName}
+		if m.Round != nil {
+			details = append(details, fmt.Sprintf(texts.Round, *m.Round))
+		}
+		if m.Score != nil && *m.Score != "" {
+			details = append(details, fmt.Sprintf(texts.Score, *m.Score))
+		}
+
+		// UID не зависит от времени и соперников: при переносе календарь обновляет событие, а не создаёт новое
+		w.prop("BEGIN", "VEVENT")
+		w.prop("UID", fmt.Sprintf("%s-match-%d@%s", m.MatchType, m.MatchID, s.uidDomain))
+		w.time("DTSTAMP", now)
+		w.time("DTSTART", m.MatchTime)
+		w.time("DTEND", m.MatchTime.Add(calendarMatchDuration))
+		w.text("SUMMARY", fmt.Sprintf("%s — %s · %s", side1, side2, m.TournamentName))
+		w.text("DESCRIPTION", strings.Join(details, "\n"))
+		if m.Location != nil && *m.Location != "" {
+			w.text("LOCATION", *m.Location)
+		}
+		if s.publicURL != "" {
+			w.prop("URL", fmt.Sprintf("%s/tournaments/%d", s.publicURL, m.TournamentID))
+		}
+		w.prop("STATUS", calendarEventStatus(m))
+		w.prop("END", "VEVENT")
+	}
+
+	w.prop("END", "VCALENDAR")
+	return w.Bytes()
+}
+
+func calendarEventStatus(m models.CalendarMatch) string {
+	switch {
+	case m.Status == models.MatchStatusCanceled || m.TournamentStatus == models.StatusCanceled:
+		return "CANCELLED"
+	case m.Side1Name == nil || m.Side2Name == nil:
+		return "TENTATIVE" // Соперники ещё не определены
+	default:
+		return "CONFIRMED"
+	}
+}
+
+type calendarText struct {
+	TBD          string
+	Round        string // Формат для fmt.Sprintf
+	Score        string // Формат для fmt.Sprintf
+	TeamCalendar string // Формат для fmt.Sprintf
+	UserCalendar string
+}
+
+var calendarTexts = map[string]calendarText{
+	models.LocaleRussian: {TBD: "Соперник не определён", Round: "Раунд %d", Score: "Счёт: %s", TeamCalendar: "Матчи команды %s", UserCalendar: "Мои матчи"},
+	models.LocaleEnglish: {TBD: "TBD", Round: "Round %d", Score: "Score: %s", TeamCalendar: "%s team matches", UserCalendar: "My matches"},
+}
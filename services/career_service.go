@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/scoring"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var (
+	ErrCareerSameOpponent = errors.New("opponent must differ from the requested player or team")
+)
+
+type CareerMatchFilter struct {
+	SportID *int
+	Limit   int
+	Offset  int
+}
+
+type CareerService interface {
+	GetUserStats(ctx context.Context, userID int) ([]models.CareerSportStats, error)
+	GetTeamStats(ctx context.Context, teamID int) ([]models.CareerSportStats, error)
+	ListUserMatches(ctx context.Context, userID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error)
+	ListTeamMatches(ctx context.Context, teamID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error)
+	ListUserTournaments(ctx context.Context, userID int, limit, offset int) ([]models.CareerTournament, int, error)
+	ListTeamTournaments(ctx context.Context, teamID int, limit, offset int) ([]models.CareerTournament, int, error)
+	GetUserHeadToHead(ctx context.Context, userID, opponentUserID int, limit, offset int) (*models.HeadToHeadRecord, error)
+	GetTeamHeadToHead(ctx context.Context, teamID, opponentTeamID int, limit, offset int) (*models.HeadToHeadRecord, error)
+}
+
+type careerService struct {
+	careerRepo repositories.CareerRepository
+	userRepo   repositories.UserRepository
+	teamRepo   repositories.TeamRepository
+	uploader   storage.FileUploader
+}
+
+func NewCareerService(
+	careerRepo repositories.CareerRepository,
+	userRepo repositories.UserRepository,
+	teamRepo repositories.TeamRepository,
+	uploader storage.FileUploader,
+) CareerService {
+	return &careerService{
+		careerRepo: careerRepo,
+		userRepo:   userRepo,
+		teamRepo:   teamRepo,
+		uploader:   uploader,
+	}
+}
+
+func (s *careerService) GetUserStats(ctx context.Context, userID int) ([]models.CareerSportStats, error) {
+	return s.getStats(ctx, false, userID)
+}
+
+func (s *careerService) GetTeamStats(ctx context.Context, teamID int) ([]models.CareerSportStats, error) {
+	return s.getStats(ctx, true, teamID)
+}
+
+func (s *careerService) ListUserMatches(ctx context.Context, userID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error) {
+	return s.listMatches(ctx, false, userID, nil, filter)
+}
+
+func (s *careerService) ListTeamMatches(ctx context.Context, teamID int, filter CareerMatchFilter) ([]models.CareerMatch, int, error) {
+	return s.listMatches(ctx, true, teamID, nil, filter)
+}
+
+func (s *careerService) ListUserTournaments(ctx context.Context, userID int, limit, offset int) ([]models.CareerTournament, int, error) {
+	return s.listTournaments(ctx, false, userID, limit, offset)
+}
+
+func (s *careerService) ListTeamTournaments(ctx context.Context, teamID int, limit, offset int) ([]models.CareerTournament, int, error) {
+	return s.listTournaments(ctx, true, teamID, limit, offset)
+}
+
+func (s *careerService) GetUserHeadToHead(ctx context.Context, userID, opponentUserID int, limit, offset int) (*models.HeadToHeadRecord, error) {
+	return s.getHeadToHead(ctx, false, userID, opponentUserID, limit, offset)
+}
+
+func (s *careerService) GetTeamHeadToHead(ctx context.Context, teamID, opponentTeamID int, limit, offset int) (*models.HeadToHeadRecord, error) {
+	return s.getHeadToHead(ctx, true, teamID, opponentTeamID, limit, offset)
+}
+
+// ensureEntityExists проверяет, что игрок (teams=false) или команда (teams=true) существует.
+func (s *careerService) ensureEntityExists(ctx context.Context, teams bool, entityID int) error {
+	if teams {
+		if _, err := s.teamRepo.GetByID(ctx, entityID); err != nil {
+			return handleRepositoryError(err, ErrTeamNotFound, "failed to get team %d", entityID)
+		}
+		return nil
+	}
+	if _, err := s.userRepo.GetByID(ctx, entityID); err != nil {
+		return handleRepositoryError(err, ErrUserNotFound, "failed to get user %d", entityID)
+	}
+	return nil
+}
+
+func (s *careerService) getStats(ctx context.Context, teams bool, entityID int) ([]models.CareerSportStats, error) {
+	if err := s.ensureEntityExists(ctx, teams, entityID); err != nil {
+		return nil, err
+	}
+	stats, err := s.careerRepo.ListSportStats(ctx, teams, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get career stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (s *careerService) listMatches(ctx context.Context, teams bool, entityID int, opponentID *int, filter CareerMatchFilter) ([]models.CareerMatch, int, error) {
+	if err := s.ensureEntityExists(ctx, teams, entityID); err != nil {
+		return nil, 0, err
+	}
+
+	matches, total, err := s.careerRepo.ListMatches(ctx, teams, entityID, repositories.CareerMatchFilter{
+		SportID:    filter.SportID,
+		OpponentID: opponentID,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list career matches: %w", err)
+	}
+	for i := range matches {
+		populateCareerMatchResultFunc(&matches[i])
+	}
+	return matches, total, nil
+}
+
+func (s *careerService) listTournaments(ctx context.Context, teams bool, entityID int, limit, offset int) ([]models.CareerTournament, int, error) {
+	if err := s.ensureEntityExists(ctx, teams, entityID); err != nil {
+		return nil, 0, err
+	}
+
+	tournaments, total, err := s.careerRepo.ListTournaments(ctx, teams, entityID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list career tournaments: %w", err)
+	}
+	if s.uploader != nil {
+		for i := range tournaments {
+			if tournaments[i].LogoKey != nil && *tournaments[i].LogoKey != "" {
+				tournaments[i].LogoURL, tournaments[i].LogoURLs = logoURLsFunc(s.uploader, tournaments[i].LogoKey)
+			}
+		}
+	}
+	return tournaments, total, nil
+}
+
+func (s *careerService) getHeadToHead(ctx context.Context, teams bool, entityID, opponentID int, limit, offset int) (*models.HeadToHeadRecord, error) {
+	if entityID == opponentID {
+		return nil, ErrCareerSameOpponent
+	}
+	if err := s.ensureEntityExists(ctx, teams, opponentID); err != nil {
+		return nil, err
+	}
+
+	matches, total, err := s.listMatches(ctx, teams, entityID, &opponentID, CareerMatchFilter{Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+
+	h2h, err := s.careerRepo.GetHeadToHead(ctx, teams, entityID, opponentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head-to-head record: %w", err)
+	}
+	h2h.Matches = matches
+	h2h.TotalMatches = total
+	return h2h, nil
+}
+
+// populateCareerMatchResultFunc переводит счёт матча (P1-P2) в счёт с точки зрения участника.
+func populateCareerMatchResultFunc(m *models.CareerMatch) {
+	if m.Status != models.MatchStatusCompleted {
+		return
+	}
+
+	switch {
+	case m.WinnerParticipantID == nil:
+		m.Result = models.MatchResultDraw
+	case *m.WinnerParticipantID == m.ParticipantID:
+		m.Result = models.MatchResultWin
+	default:
+		m.Result = models.MatchResultLoss
+	}
+
+	if m.Score == nil {
+		return
+	}
+	s1, s2, ok := scoring.Summarize(*m.Score)
+	if !ok {
+		return
+	}
+	if !m.IsFirstSlot {
+		s1, s2 = s2, s1
+	}
+	m.ScoreFor = &s1
+	m.ScoreAgainst = &s2
+}
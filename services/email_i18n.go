@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+// EmailRecipient — адрес и языковые настройки получателя письма.
+type EmailRecipient struct {
+	Email    string
+	Locale   string
+	Timezone string
+}
+
+func RecipientFromUser(user *models.User) EmailRecipient {
+	return EmailRecipient{Email: user.Email, Locale: user.Locale, Timezone: user.Timezone}
+}
+
+func (r EmailRecipient) locale() string {
+	if locale, ok := models.NormalizeLocale(r.Locale); ok {
+		return locale
+	}
+	return models.DefaultLocale
+}
+
+func (r EmailRecipient) location() *time.Location {
+	if r.Timezone != "" {
+		if loc, err := time.LoadLocation(r.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// Темы писем; %s подставляется так же, как в fmt.Sprintf.
+var emailSubjects = map[string]map[models.OutboxEmailKind]string{
+	models.LocaleRussian: {
+		models.EmailKindWelcome:          "Добро пожаловать в Tournament System!",
+		models.EmailKindPasswordReset:    "Сброс пароля для Heartbit",
+		models.EmailKindTeamInvite:       "Приглашение в команду %s",
+		models.EmailKindTournamentStatus: "Турнир '%s': %s",
+	},
+	models.LocaleEnglish: {
+		models.EmailKindWelcome:          "Welcome to Tournament System!",
+		models.EmailKindPasswordReset:    "Heartbit password reset",
+		models.EmailKindTeamInvite:       "Invitation to join team %s",
+		models.EmailKindTournamentStatus: "Tournament '%s': %s",
+	},
+}
+
+var tournamentStatusLabels = map[string]map[models.TournamentStatus]string{
+	models.LocaleRussian: {
+		models.StatusSoon:         "скоро",
+		models.StatusRegistration: "открыта регистрация",
+		models.StatusActive:       "идёт",
+		models.StatusCompleted:    "завершён",
+		models.StatusCanceled:     "отменён",
+	},
+	models.LocaleEnglish: {
+		models.StatusSoon:         "coming soon",
+		models.StatusRegistration: "registration open",
+		models.StatusActive:       "in progress",
+		models.StatusCompleted:    "completed",
+		models.StatusCanceled:     "canceled",
+	},
+}
+
+var monthNames = map[string][12]string{
+	models.LocaleRussian: {"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	models.LocaleEnglish: {"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+}
+
+func emailSubject(locale string, kind models.OutboxEmailKind, args ...interface{}) string {
+	format, ok := emailSubjects[locale][kind]
+	if !ok {
+		format = emailSubjects[models.DefaultLocale][kind]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func tournamentStatusLabel(locale string, status models.TournamentStatus) string {
+	if label, ok := tournamentStatusLabels[locale][status]; ok {
+		return label
+	}
+	if label, ok := tournamentStatusLabels[models.DefaultLocale][status]; ok {
+		return label
+	}
+	return string(status)
+}
+
+// formatEmailDateTime выводит дату в часовом поясе получателя:
+// "5 марта 2026, 18:30 (MSK)" или "March 5, 2026, 6:30 PM (MSK)".
+func formatEmailDateTime(t time.Time, recipient EmailRecipient) string {
+	locale := recipient.locale()
+	local := t.In(recipient.location())
+	month := monthNames[locale][local.Month()-1]
+	zone := local.Format("MST")
+
+	if locale == models.LocaleEnglish {
+		return fmt.Sprintf("%s %d, %d, %s (%s)", month, local.Day(), local.Year(), local.Format("3:04 PM"), zone)
+	}
+	return fmt.Sprintf("%d %s %d, %s (%s)", local.Day(), month, local.Year(), local.Format("15:04"), zone)
+}
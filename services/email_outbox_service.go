@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+const (
+	outboxBatchSize   = 20
+	outboxLease       = 5 * time.Minute
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = 1 * time.Hour
+	outboxMaxErrorLen = 1000
+)
+
+var (
+	ErrOutboxEmailNotFound     = repositories.ErrOutboxEmailNotFound
+	ErrOutboxEmailAlreadySent  = errors.New("email has already been sent")
+	ErrOutboxEmailInvalidInput = errors.New("email must have recipients, subject and body")
+)
+
+// EmailSender — транспорт, через который воркер доставляет письма из очереди.
+type EmailSender interface {
+	SendEmail(ctx context.Context, to []string, subject string, body string) error
+}
+
+type EmailOutboxService interface {
+	// Enqueue ставит письмо в очередь; exec позволяет сделать это в транзакции бизнес-операции.
+	Enqueue(ctx context.Context, exec repositories.SQLExecutor, email *models.OutboxEmail) error
+	// ProcessDue отправляет письма, у которых подошло время попытки. Возвращает число отправленных.
+	ProcessDue(ctx context.Context) (int, error)
+	List(ctx context.Context, filter models.OutboxEmailFilter) ([]*models.OutboxEmail, int, error)
+	GetByID(ctx context.Context, id int64) (*models.OutboxEmail, error)
+	Resend(ctx context.Context, id int64) (*models.OutboxEmail, error)
+}
+
+type emailOutboxService struct {
+	outboxRepo repositories.EmailOutboxRepository
+	sender     EmailSender
+	logger     *slog.Logger
+}
+
+func NewEmailOutboxService(outboxRepo repositories.EmailOutboxRepository, sender EmailSender, logger *slog.Logger) EmailOutboxService {
+	return &emailOutboxService{
+		outboxRepo: outboxRepo,
+		sender:     sender,
+		logger:     logger,
+	}
+}
+
+func (s *emailOutboxService) Enqueue(ctx context.Context, exec repositories.SQLExecutor, email *models.OutboxEmail) error {
+	if email == nil || len(email.Recipients) == 0 || email.Subject == "" || email.HTMLBody == "" {
+		return ErrOutboxEmailInvalidInput
+	}
+	return s.outboxRepo.Enqueue(ctx, exec, email)
+}
+
+func (s *emailOutboxService) ProcessDue(ctx context.Context) (int, error) {
+	emails, err := s.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		if s.deliver(ctx, email) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *emailOutboxService) deliver(ctx context.Context, email *models.OutboxEmail) bool {
+	logger := s.logger.With(slog.Int64("email_id", email.ID), slog.String("kind", string(email.Kind)))
+
+	sendErr := s.sender.SendEmail(ctx, email.Recipients, email.Subject, email.HTMLBody)
+	if sendErr == nil {
+		if err := s.outboxRepo.MarkSent(ctx, email.ID); err != nil {
+			logger.ErrorContext(ctx, "Email sent but failed to mark as sent", slog.Any("error", err))
+		}
+		return true
+	}
+
+	attempt := email.Attempts + 1
+	dead := attempt >= email.MaxAttempts
+	nextAttemptAt := time.Now().Add(outboxBackoff(attempt))
+	if err := s.outboxRepo.MarkFailed(ctx, email.ID, truncateError(sendErr.Error()), nextAttemptAt, dead); err != nil {
+		logger.ErrorContext(ctx, "Failed to record email delivery failure", slog.Any("error", err))
+	}
+	if dead {
+		logger.ErrorContext(ctx, "Email moved to dead letters", slog.Int("attempts", attempt), slog.Any("error", sendErr))
+	} else {
+		logger.WarnContext(ctx, "Email delivery failed, will retry",
+			slog.Int("attempts", attempt), slog.Time("next_attempt_at", nextAttemptAt), slog.Any("error", sendErr))
+	}
+	return false
+}
+
+func (s *emailOutboxService) List(ctx context.Context, filter models.OutboxEmailFilter) ([]*models.OutboxEmail, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.outboxRepo.List(ctx, filter)
+}
+
+func (s *emailOutboxService) GetByID(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	return s.outboxRepo.GetByID(ctx, id)
+}
+
+// Resend возвращает письмо в очередь; уже отправленные письма повторно не отправляются.
+func (s *emailOutboxService) Resend(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	email, err := s.outboxRepo.Requeue(ctx, id)
+	if err == nil {
+		return email, nil
+	}
+	if !errors.Is(err, repositories.ErrOutboxEmailNotFound) {
+		return nil, err
+	}
+	existing, getErr := s.outboxRepo.GetByID(ctx, id)
+	if getErr != nil {
+		return nil, getErr
+	}
+	if existing.Status == models.OutboxEmailSent {
+		return nil, ErrOutboxEmailAlreadySent
+	}
+	return nil, fmt.Errorf("failed to requeue email %d: %w", id, err)
+}
+
+// outboxBackoff — экспоненциальная задержка перед попыткой attempt+1: 30s, 1m, 2m, ... не больше часа.
+func outboxBackoff(attempt int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+func truncateError(msg string) string {
+	if len(msg) > outboxMaxErrorLen {
+		return strings.ToValidUTF8(msg[:outboxMaxErrorLen], "")
+	}
+	return msg
+}
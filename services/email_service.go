@@ -2,97 +2,54 @@ package services
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
-	"net/smtp"
+	"io/fs"
+	"os"
+	"path/filepath"
 
 	"github.com/Dosada05/tournament-system/config"
+	"github.com/Dosada05/tournament-system/mailer"
+	"github.com/Dosada05/tournament-system/models"
 )
 
+const emailTemplatesDir = "templates/emails"
+
 type EmailService struct {
-	cfg *config.Config
+	cfg       *config.Config
+	transport mailer.Transport
 }
 
-func NewEmailService(cfg *config.Config) *EmailService {
-	return &EmailService{cfg: cfg}
+func NewEmailService(cfg *config.Config, transport mailer.Transport) *EmailService {
+	return &EmailService{cfg: cfg, transport: transport}
 }
 
-func (s *EmailService) SendEmail(to []string, subject string, body string) error {
-	auth := smtp.PlainAuth("", s.cfg.SMTPUser, s.cfg.SMTPPass, s.cfg.SMTPHost)
-
-	msg := []byte("To: " + to[0] + "\r\n" +
-		"From: " + s.cfg.SMTPFrom + "\r\n" +
-		"Subject: " + subject + "\r\n" +
-		"MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\r\n" +
-		"\r\n" +
-		body + "\r\n")
-
-	addr := fmt.Sprintf("%s:%d", s.cfg.SMTPHost, s.cfg.SMTPPort)
-
-	tlsconfig := &tls.Config{
-		InsecureSkipVerify: true,
-		ServerName:         s.cfg.SMTPHost,
-	}
-
-	var client *smtp.Client
-	if s.cfg.SMTPPort == 465 {
-		// Прямое TLS-соединение (обычно порт 465)
-		conn, err := tls.Dial("tcp", addr, tlsconfig)
-		if err != nil {
-			return fmt.Errorf("ошибка TLS соединения: %w", err)
-		}
-		defer conn.Close()
-		client, err = smtp.NewClient(conn, s.cfg.SMTPHost)
-		if err != nil {
-			return fmt.Errorf("ошибка создания SMTP клиента: %w", err)
-		}
-	} else {
-		// STARTTLS (обычно порт 587)
-		c, err := smtp.Dial(addr)
-		if err != nil {
-			return fmt.Errorf("ошибка соединения SMTP: %w", err)
-		}
-		client = c
-		if err = client.StartTLS(tlsconfig); err != nil {
-			client.Close()
-			return fmt.Errorf("ошибка команды STARTTLS: %w", err)
-		}
-	}
-	defer client.Quit()
-
-	if err := client.Auth(auth); err != nil {
-		return fmt.Errorf("ошибка аутентификации SMTP: %w", err)
-	}
-
-	if err := client.Mail(s.cfg.SMTPFrom); err != nil {
-		return fmt.Errorf("ошибка MAIL FROM: %w", err)
-	}
-	for _, addr := range to {
-		if err := client.Rcpt(addr); err != nil {
-			return fmt.Errorf("ошибка RCPT TO: %w", err)
-		}
-	}
-
-	w, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("ошибка команды DATA: %w", err)
+// SendEmail отправляет одно письмо всем получателям через настроенный транспорт.
+// Текстовая часть строится из HTML автоматически.
+func (s *EmailService) SendEmail(ctx context.Context, to []string, subject string, body string) error {
+	msg := &mailer.Message{
+		From:     s.cfg.SMTPFrom,
+		To:       to,
+		ReplyTo:  s.cfg.SMTPReplyTo,
+		Subject:  subject,
+		HTMLBody: body,
 	}
-
-	_, err = w.Write(msg)
-	if err != nil {
-		return fmt.Errorf("ошибка записи сообщения: %w", err)
+	if err := s.transport.Send(ctx, msg); err != nil {
+		return fmt.Errorf("ошибка отправки письма: %w", err)
 	}
-
-	err = w.Close()
-	if err != nil {
-		return fmt.Errorf("ошибка закрытия DATA: %w", err)
-	}
-
 	return nil
 }
 
-func (s *EmailService) GenerateEmailBody(templatePath string, data interface{}) (string, error) {
+// GenerateEmailBody рендерит шаблон templates/emails/<locale>/<name>.
+// Если для локали шаблона нет, используется локаль по умолчанию.
+func (s *EmailService) GenerateEmailBody(locale string, name string, data interface{}) (string, error) {
+	templatePath := filepath.Join(emailTemplatesDir, locale, name)
+	if _, err := os.Stat(templatePath); errors.Is(err, fs.ErrNotExist) {
+		templatePath = filepath.Join(emailTemplatesDir, models.DefaultLocale, name)
+	}
+
 	t, err := template.ParseFiles(templatePath)
 	if err != nil {
 		return "", fmt.Errorf("ошибка парсинга шаблона %s: %w", templatePath, err)
@@ -106,88 +63,110 @@ func (s *EmailService) GenerateEmailBody(templatePath string, data interface{})
 	return body.String(), nil
 }
 
-func (s *EmailService) SendWelcomeEmail(userEmail string, confirmationToken string) error {
-	subject := "Добро пожаловать в Tournament System!"
+func (s *EmailService) BuildWelcomeEmail(recipient EmailRecipient, confirmationToken string) (*models.OutboxEmail, error) {
+	locale := recipient.locale()
 	templateData := struct {
 		Email            string
 		ConfirmationLink string
 	}{
-		Email: userEmail,
-		// Используем ваш домен heartbit.live и предполагаем, что порт будет стандартным для HTTPS (443) или HTTP (80)
-		// и будет обрабатываться прокси-сервером (например, Nginx), поэтому порт в ссылке не указываем явно.
-		// Если ваше приложение напрямую слушает на s.cfg.ServerPort и доступно извне на этом порту,
-		// то можно вернуть порт в ссылку: fmt.Sprintf("https://heartbit.live:%d/confirm-email?token=%s", s.cfg.ServerPort, confirmationToken)
-		// или fmt.Sprintf("http://heartbit.live:%d/confirm-email?token=%s", s.cfg.ServerPort, confirmationToken)
-		// В зависимости от того, используется ли HTTPS. Для продакшена всегда рекомендуется HTTPS.
+		Email:            recipient.Email,
 		ConfirmationLink: fmt.Sprintf("%s/confirm-email?token=%s", s.cfg.PublicURL, confirmationToken),
 	}
 
-	htmlBody, err := s.GenerateEmailBody("templates/emails/welcome_email.html", templateData)
+	htmlBody, err := s.GenerateEmailBody(locale, "welcome_email.html", templateData)
 	if err != nil {
-		return fmt.Errorf("ошибка генерации тела приветственного письма: %w", err)
+		return nil, fmt.Errorf("ошибка генерации тела приветственного письма: %w", err)
 	}
 
-	return s.SendEmail([]string{userEmail}, subject, htmlBody)
+	subject := emailSubject(locale, models.EmailKindWelcome)
+	return newOutboxEmail(models.EmailKindWelcome, []string{recipient.Email}, subject, htmlBody), nil
 }
 
-func (s *EmailService) SendPasswordResetEmail(userEmail string, resetToken string) error {
-	subject := "Сброс пароля для Heartbit"
-	resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.PublicURL, resetToken)
+func (s *EmailService) BuildPasswordResetEmail(recipient EmailRecipient, resetToken string) (*models.OutboxEmail, error) {
+	locale := recipient.locale()
 	templateData := struct {
 		Email     string
 		ResetLink string
 	}{
-		Email:     userEmail,
-		ResetLink: resetLink,
+		Email:     recipient.Email,
+		ResetLink: fmt.Sprintf("%s/reset-password?token=%s", s.cfg.PublicURL, resetToken),
 	}
 
-	htmlBody, err := s.GenerateEmailBody("templates/emails/password_reset_email.html", templateData)
+	htmlBody, err := s.GenerateEmailBody(locale, "password_reset_email.html", templateData)
 	if err != nil {
-		return fmt.Errorf("ошибка генерации тела письма для сброса пароля: %w", err)
+		return nil, fmt.Errorf("ошибка генерации тела письма для сброса пароля: %w", err)
 	}
 
-	return s.SendEmail([]string{userEmail}, subject, htmlBody)
+	subject := emailSubject(locale, models.EmailKindPasswordReset)
+	return newOutboxEmail(models.EmailKindPasswordReset, []string{recipient.Email}, subject, htmlBody), nil
 }
 
-func (s *EmailService) SendTeamInviteEmail(userEmail, teamName, inviteLink string) error {
-	subject := fmt.Sprintf("Приглашение в команду %s", teamName)
+func (s *EmailService) BuildTeamInviteEmail(recipient EmailRecipient, teamName, inviteToken string) (*models.OutboxEmail, error) {
+	locale := recipient.locale()
 	data := struct {
 		TeamName   string
 		InviteLink string
 	}{
 		TeamName:   teamName,
-		InviteLink: inviteLink,
+		InviteLink: s.cfg.PublicURL + "/invites/join/" + inviteToken,
 	}
-	htmlBody, err := s.GenerateEmailBody("templates/emails/team_invite_email.html", data)
+	htmlBody, err := s.GenerateEmailBody(locale, "team_invite_email.html", data)
 	if err != nil {
-		return fmt.Errorf("ошибка генерации тела письма-приглашения: %w", err)
+		return nil, fmt.Errorf("ошибка генерации тела письма-приглашения: %w", err)
 	}
-	return s.SendEmail([]string{userEmail}, subject, htmlBody)
+	subject := emailSubject(locale, models.EmailKindTeamInvite, teamName)
+	return newOutboxEmail(models.EmailKindTeamInvite, []string{recipient.Email}, subject, htmlBody), nil
 }
 
-func (s *EmailService) SendTournamentStatusEmail(userEmail, tournamentName, status, link string) error {
-	subject := fmt.Sprintf("Турнир '%s': %s", tournamentName, status)
+// BuildTournamentStatusEmail готовит письмо о смене статуса турнира; даты выводятся
+// на языке и в часовом поясе получателя.
+func (s *EmailService) BuildTournamentStatusEmail(recipient EmailRecipient, tournament *models.Tournament, link string) (*models.OutboxEmail, error) {
+	locale := recipient.locale()
+	status := tournamentStatusLabel(locale, tournament.Status)
 	data := struct {
 		TournamentName string
 		Status         string
+		StartsAt       string
+		EndsAt         string
 		Link           string
 	}{
-		TournamentName: tournamentName,
+		TournamentName: tournament.Name,
 		Status:         status,
+		StartsAt:       formatEmailDateTime(tournament.StartDate, recipient),
+		EndsAt:         formatEmailDateTime(tournament.EndDate, recipient),
 		Link:           link,
 	}
-	htmlBody, err := s.GenerateEmailBody("templates/emails/tournament_status_email.html", data)
+	htmlBody, err := s.GenerateEmailBody(locale, "tournament_status_email.html", data)
 	if err != nil {
-		return fmt.Errorf("ошибка генерации тела письма о статусе турнира: %w", err)
+		return nil, fmt.Errorf("ошибка генерации тела письма о статусе турнира: %w", err)
 	}
-	return s.SendEmail([]string{userEmail}, subject, htmlBody)
+	subject := emailSubject(locale, models.EmailKindTournamentStatus, tournament.Name, status)
+	return newOutboxEmail(models.EmailKindTournamentStatus, []string{recipient.Email}, subject, htmlBody), nil
 }
 
-func (s *EmailService) SendSystemNotificationEmail(emails []string, subject, message string) error {
-	for _, email := range emails {
-		if err := s.SendEmail([]string{email}, subject, message); err != nil {
-			return fmt.Errorf("ошибка отправки системного уведомления %s: %w", email, err)
-		}
+// BuildNotificationEmail готовит письмо-копию уведомления; title и body уже переведены на язык получателя.
+func (s *EmailService) BuildNotificationEmail(recipient EmailRecipient, title, body, link string) (*models.OutboxEmail, error) {
+	data := struct {
+		Title string
+		Body  string
+		Link  string
+	}{
+		Title: title,
+		Body:  body,
+		Link:  link,
+	}
+	htmlBody, err := s.GenerateEmailBody(recipient.locale(), "notification_email.html", data)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка генерации тела письма-уведомления: %w", err)
+	}
+	return newOutboxEmail(models.EmailKindNotification, []string{recipient.Email}, title, htmlBody), nil
+}
+
+func newOutboxEmail(kind models.OutboxEmailKind, to []string, subject, htmlBody string) *models.OutboxEmail {
+	return &models.OutboxEmail{
+		Kind:       kind,
+		Recipients: to,
+		Subject:    subject,
+		HTMLBody:   htmlBody,
 	}
-	return nil
 }
@@ -34,13 +34,14 @@ var (
 	ErrUserMustBeCaptain      = errors.New("only the team captain can register the team")
 
 	// Ошибки, специфичные для сущностей (могут дублировать ErrNotFound, но дают больше контекста)
-	ErrUserNotFound        = errors.New("user not found")
-	ErrTeamNotFound        = errors.New("team not found")
-	ErrSportNotFound       = errors.New("sport not found")
-	ErrFormatNotFound      = errors.New("format not found")
-	ErrTournamentNotFound  = errors.New("tournament not found")
-	ErrParticipantNotFound = errors.New("participant registration not found")
-	ErrInviteNotFound      = errors.New("invite not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrSportNotFound        = errors.New("sport not found")
+	ErrFormatNotFound       = errors.New("format not found")
+	ErrTournamentNotFound   = errors.New("tournament not found")
+	ErrParticipantNotFound  = errors.New("participant registration not found")
+	ErrInviteNotFound       = errors.New("invite not found")
+	ErrOrganizationNotFound = errors.New("organization not found")
 
 	// Ошибки турниров (примеры)
 	ErrTournamentInvalidRegDate          = errors.New("tournament registration end date must be after start date")
@@ -12,22 +12,37 @@ import (
 )
 
 var (
-	ErrFormatNameRequired        = errors.New("format name is required")
-	ErrFormatNameConflict        = errors.New("format name already exists")
-	ErrFormatInUse               = errors.New("format cannot be deleted as it is currently in use")
-	ErrFormatCreationFailed      = errors.New("failed to create format")
-	ErrFormatUpdateFailed        = errors.New("failed to update format")
-	ErrFormatDeleteFailed        = errors.New("failed to delete format")
-	ErrInvalidBracketType        = errors.New("invalid bracket type specified")
-	ErrInvalidRoundRobinSettings = errors.New("invalid settings for RoundRobin format")
+	ErrFormatNameRequired    = errors.New("format name is required")
+	ErrFormatNameConflict    = errors.New("format name already exists")
+	ErrFormatInUse           = errors.New("format cannot be deleted as it is currently in use")
+	ErrFormatCreationFailed  = errors.New("failed to create format")
+	ErrFormatUpdateFailed    = errors.New("failed to update format")
+	ErrFormatDeleteFailed    = errors.New("failed to delete format")
+	ErrInvalidBracketType    = errors.New("invalid bracket type specified")
+	ErrInvalidFormatSettings = errors.New("invalid format settings")
 )
 
+// FormatSettingsError — ошибки валидации settings_json по схеме типа сетки, по полям.
+type FormatSettingsError struct {
+	BracketType string
+	Fields      map[string]string
+}
+
+func (e *FormatSettingsError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", ErrInvalidFormatSettings, e.BracketType, e.Fields)
+}
+
+func (e *FormatSettingsError) Unwrap() error {
+	return ErrInvalidFormatSettings
+}
+
 type FormatService interface {
 	CreateFormat(ctx context.Context, input CreateFormatInput) (*models.Format, error)
 	GetFormatByID(ctx context.Context, id int) (*models.Format, error)
 	GetAllFormats(ctx context.Context) ([]models.Format, error)
 	UpdateFormat(ctx context.Context, id int, input UpdateFormatInput) (*models.Format, error)
 	DeleteFormat(ctx context.Context, id int) error
+	ListBracketTypes() []models.BracketTypeInfo
 }
 
 type CreateFormatInput struct {
@@ -61,34 +76,13 @@ func (s *formatService) CreateFormat(ctx context.Context, input CreateFormatInpu
 		return nil, ErrFormatNameRequired
 	}
 
-	if input.BracketType != "SingleElimination" && input.BracketType != "RoundRobin" {
+	if !models.IsValidBracketType(input.BracketType) {
 		return nil, fmt.Errorf("%w: %s. Supported types are 'SingleElimination', 'RoundRobin'", ErrInvalidBracketType, input.BracketType)
 	}
 
-	var settingsStrPointer *string
-	if len(input.SettingsJSON) > 0 && string(input.SettingsJSON) != "null" {
-		parsedSettings := make(map[string]interface{})
-		if err := json.Unmarshal(input.SettingsJSON, &parsedSettings); err != nil {
-			return nil, fmt.Errorf("invalid settings_json format: %w", err)
-		}
-
-		if input.BracketType == "RoundRobin" {
-			var rrSettings models.RoundRobinSettings
-			if err := json.Unmarshal(input.SettingsJSON, &rrSettings); err != nil {
-				return nil, fmt.Errorf("%w: could not parse RoundRobin settings: %v", ErrInvalidRoundRobinSettings, err)
-			}
-			if rrSettings.NumberOfRounds < 1 || rrSettings.NumberOfRounds > 2 { // Example validation
-				return nil, fmt.Errorf("%w: NumberOfRounds must be 1 or 2, got %d", ErrInvalidRoundRobinSettings, rrSettings.NumberOfRounds)
-			}
-			// Re-marshal validated/defaulted settings if necessary, or just store the original valid JSON
-			validJsonBytes, _ := json.Marshal(rrSettings) // Assuming rrSettings might have defaults applied
-			s := string(validJsonBytes)
-			settingsStrPointer = &s
-		} else {
-			// For other bracket types, just store the provided JSON if it's valid
-			sJSON := string(input.SettingsJSON)
-			settingsStrPointer = &sJSON
-		}
+	settingsStrPointer, err := validateFormatSettings(input.BracketType, input.SettingsJSON)
+	if err != nil {
+		return nil, err
 	}
 
 	format := &models.Format{
@@ -98,7 +92,7 @@ func (s *formatService) CreateFormat(ctx context.Context, input CreateFormatInpu
 		SettingsJSON:    settingsStrPointer,
 	}
 
-	err := s.formatRepo.Create(ctx, format)
+	err = s.formatRepo.Create(ctx, format)
 	if err != nil {
 		if errors.Is(err, repositories.ErrFormatNameConflict) {
 			return nil, ErrFormatNameConflict
@@ -151,14 +145,12 @@ func (s *formatService) UpdateFormat(ctx context.Context, id int, input UpdateFo
 		}
 	}
 
-	currentBracketType := formatToUpdate.BracketType
 	if input.BracketType != nil {
-		if *input.BracketType != "SingleElimination" && *input.BracketType != "RoundRobin" {
+		if !models.IsValidBracketType(*input.BracketType) {
 			return nil, fmt.Errorf("%w: %s. Supported types are 'SingleElimination', 'RoundRobin'", ErrInvalidBracketType, *input.BracketType)
 		}
 		if *input.BracketType != formatToUpdate.BracketType {
 			formatToUpdate.BracketType = *input.BracketType
-			currentBracketType = *input.BracketType // Update for settings validation
 			updated = true
 		}
 	}
@@ -168,36 +160,21 @@ func (s *formatService) UpdateFormat(ctx context.Context, id int, input UpdateFo
 		updated = true
 	}
 
-	if input.SettingsJSON != nil {
-		if len(*input.SettingsJSON) == 0 || string(*input.SettingsJSON) == "null" {
-			if formatToUpdate.SettingsJSON != nil {
-				formatToUpdate.SettingsJSON = nil
-				updated = true
-			}
-		} else {
-			parsedSettings := make(map[string]interface{})
-			if errJson := json.Unmarshal(*input.SettingsJSON, &parsedSettings); errJson != nil {
-				return nil, fmt.Errorf("invalid settings_json format for update: %w", errJson)
-			}
-
-			newSettingsStr := string(*input.SettingsJSON)
-
-			if currentBracketType == "RoundRobin" {
-				var rrSettings models.RoundRobinSettings
-				if errJson := json.Unmarshal(*input.SettingsJSON, &rrSettings); errJson != nil {
-					return nil, fmt.Errorf("%w: could not parse RoundRobin settings for update: %v", ErrInvalidRoundRobinSettings, errJson)
-				}
-				if rrSettings.NumberOfRounds < 1 || rrSettings.NumberOfRounds > 2 {
-					return nil, fmt.Errorf("%w: NumberOfRounds must be 1 or 2, got %d", ErrInvalidRoundRobinSettings, rrSettings.NumberOfRounds)
-				}
-				validJsonBytes, _ := json.Marshal(rrSettings)
-				newSettingsStr = string(validJsonBytes)
-			}
-
-			if formatToUpdate.SettingsJSON == nil || *formatToUpdate.SettingsJSON != newSettingsStr {
-				formatToUpdate.SettingsJSON = &newSettingsStr
-				updated = true
-			}
+	// Настройки проверяются и при смене типа сетки: старые могут не подходить новой схеме
+	if input.SettingsJSON != nil || input.BracketType != nil {
+		var raw json.RawMessage
+		if input.SettingsJSON != nil {
+			raw = *input.SettingsJSON
+		} else if formatToUpdate.SettingsJSON != nil {
+			raw = json.RawMessage(*formatToUpdate.SettingsJSON)
+		}
+		newSettings, errSettings := validateFormatSettings(formatToUpdate.BracketType, raw)
+		if errSettings != nil {
+			return nil, errSettings
+		}
+		if !equalSettings(formatToUpdate.SettingsJSON, newSettings) {
+			formatToUpdate.SettingsJSON = newSettings
+			updated = true
 		}
 	}
 
@@ -233,3 +210,33 @@ func (s *formatService) DeleteFormat(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+func (s *formatService) ListBracketTypes() []models.BracketTypeInfo {
+	return models.BracketTypes()
+}
+
+// validateFormatSettings проверяет settings_json по схеме типа сетки и возвращает
+// нормализованный JSON с подставленными значениями по умолчанию.
+func validateFormatSettings(bracketType string, raw json.RawMessage) (*string, error) {
+	schema, ok := models.SettingsSchemaFor(bracketType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBracketType, bracketType)
+	}
+	settings, fieldErrors := schema.Validate(raw)
+	if len(fieldErrors) > 0 {
+		return nil, &FormatSettingsError{BracketType: bracketType, Fields: fieldErrors}
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode format settings: %w", err)
+	}
+	result := string(data)
+	return &result, nil
+}
+
+func equalSettings(current, next *string) bool {
+	if current == nil || next == nil {
+		return current == next
+	}
+	return *current == *next
+}
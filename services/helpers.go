@@ -3,8 +3,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -146,19 +148,13 @@ func dereferenceMatchViews(slice []*MatchView) []MatchView {
 
 func populateTournamentLogoURLFunc(tournament *models.Tournament, uploader storage.FileUploader) {
 	if tournament != nil && tournament.LogoKey != nil && *tournament.LogoKey != "" && uploader != nil {
-		url := uploader.GetPublicURL(*tournament.LogoKey)
-		if url != "" {
-			tournament.LogoURL = &url
-		}
+		tournament.LogoURL, tournament.LogoURLs = logoURLsFunc(uploader, tournament.LogoKey)
 	}
 }
 
 func populateSportLogoURLFunc(sport *models.Sport, uploader storage.FileUploader) {
 	if sport != nil && sport.LogoKey != nil && *sport.LogoKey != "" && uploader != nil {
-		url := uploader.GetPublicURL(*sport.LogoKey)
-		if url != "" {
-			sport.LogoURL = &url
-		}
+		sport.LogoURL, sport.LogoURLs = logoURLsFunc(uploader, sport.LogoKey)
 	}
 }
 
@@ -168,10 +164,7 @@ func populateUserDetailsFunc(user *models.User, uploader storage.FileUploader) {
 	}
 	user.PasswordHash = "" // Важно для безопасности
 	if user.LogoKey != nil && *user.LogoKey != "" && uploader != nil {
-		url := uploader.GetPublicURL(*user.LogoKey)
-		if url != "" {
-			user.LogoURL = &url
-		}
+		user.LogoURL, user.LogoURLs = logoURLsFunc(uploader, user.LogoKey)
 	}
 }
 
@@ -187,10 +180,7 @@ func populateParticipantListDetailsFunc(participants []*models.Participant, uplo
 			populateUserDetailsFunc(p.User, uploader) // Вызов обновленной функции
 		}
 		if p.Team != nil && p.Team.LogoKey != nil && *p.Team.LogoKey != "" {
-			url := uploader.GetPublicURL(*p.Team.LogoKey)
-			if url != "" {
-				p.Team.LogoURL = &url
-			}
+			p.Team.LogoURL, p.Team.LogoURLs = logoURLsFunc(uploader, p.Team.LogoKey)
 		}
 	}
 }
@@ -232,18 +222,12 @@ func participantToParticipantViewFunc(p *models.Participant, uploader storage.Fi
 	if p.User != nil {
 		view.Type = "user"
 		if p.User.LogoKey != nil && uploader != nil {
-			logoURL := uploader.GetPublicURL(*p.User.LogoKey)
-			if logoURL != "" {
-				view.LogoURL = &logoURL
-			}
+			view.LogoURL, view.LogoURLs = logoURLsFunc(uploader, p.User.LogoKey)
 		}
 	} else if p.Team != nil {
 		view.Type = "team"
 		if p.Team.LogoKey != nil && uploader != nil {
-			logoURL := uploader.GetPublicURL(*p.Team.LogoKey)
-			if logoURL != "" {
-				view.LogoURL = &logoURL
-			}
+			view.LogoURL, view.LogoURLs = logoURLsFunc(uploader, p.Team.LogoKey)
 		}
 	}
 	return view
@@ -384,30 +368,168 @@ func populateTournamentDetailsFunc(
 	}
 }
 
-// GetExtensionFromContentType (из services/user_service.go, можно сделать общим)
-func GetExtensionFromContentType(contentType string) (string, error) {
-	switch contentType {
-	case "image/jpeg", "image/jpg":
-		return ".jpg", nil
-	case "image/png":
-		return ".png", nil
-	case "image/gif":
-		return ".gif", nil
-	case "image/webp":
-		return ".webp", nil
-	default:
-		parts := strings.Split(contentType, "/")
-		if len(parts) == 2 && strings.HasPrefix(parts[0], "image") && parts[1] != "" {
-			// Убираем возможные суффиксы типа "+xml" (например, "image/svg+xml")
-			ext := "." + strings.Split(parts[1], "+")[0]
-			// Проверяем на известные, но не стандартные случаи
-			if ext == ".svg" {
-				return ext, nil
-			} // Явно разрешаем svg, если нужно
-			// Можно добавить другие проверки или просто вернуть то, что получилось
-			// return "", fmt.Errorf("unsupported image content type for extension: %s", contentType) // Если строгая проверка
-			return ext, nil // Если более мягкая
-		}
-		return "", fmt.Errorf("could not determine file extension from content type: '%s'", contentType)
+// canManageTournamentFunc проверяет права на управление турниром: организатор турнира
+// либо owner/admin организации, которой принадлежит турнир.
+func canManageTournamentFunc(ctx context.Context, orgRepo repositories.OrganizationRepository, tournament *models.Tournament, userID int) (bool, error) {
+	if tournament.OrganizerID == userID {
+		return true, nil
+	}
+	if tournament.OrganizationID == nil || orgRepo == nil {
+		return false, nil
+	}
+	member, err := orgRepo.GetMember(ctx, *tournament.OrganizationID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check organization membership for user %d: %w", userID, err)
+	}
+	return member.Role.CanManage(), nil
+}
+
+// matchOutcome — минимальные данные матча, нужные для расчёта итоговых мест.
+type matchOutcome struct {
+	Round  int
+	P1     *int
+	P2     *int
+	Winner *int
+}
+
+// computeEliminationPlacements считает места в олимпийской системе по глубине вылета:
+// финалист — 2 место, проигравшие в 1/2 — 3, в 1/4 — 5 и т.д.
+func computeEliminationPlacements(outcomes []matchOutcome, championPID *int) map[int]int {
+	placements := make(map[int]int)
+	maxRound := 0
+	for _, o := range outcomes {
+		if o.Round > maxRound {
+			maxRound = o.Round
+		}
+	}
+	for _, o := range outcomes {
+		if o.Winner == nil || o.P1 == nil || o.P2 == nil {
+			continue // Незавершённый матч или bye
+		}
+		loser := *o.P1
+		if *o.Winner == *o.P1 {
+			loser = *o.P2
+		}
+		placements[loser] = (1 << (maxRound - o.Round)) + 1
+		if o.Round == maxRound && championPID == nil {
+			winner := *o.Winner
+			championPID = &winner
+		}
+	}
+	if championPID != nil {
+		placements[*championPID] = 1
+	}
+	return placements
+}
+
+// computeRoundRobinPlacements считает места по отсортированной турнирной таблице;
+// при полном равенстве показателей участники делят место.
+func computeRoundRobinPlacements(standings []*models.TournamentStanding) map[int]int {
+	placements := make(map[int]int, len(standings))
+	for i, st := range standings {
+		place := i + 1
+		if i > 0 {
+			prev := standings[i-1]
+			if prev.Points == st.Points && prev.ScoreDifference == st.ScoreDifference && prev.ScoreFor == st.ScoreFor {
+				place = placements[prev.ParticipantID]
+			}
+		}
+		placements[st.ParticipantID] = place
 	}
+	return placements
+}
+
+// computeTournamentPlacementsFunc возвращает итоговые места участников турнира (participant_id -> место).
+func computeTournamentPlacementsFunc(
+	ctx context.Context,
+	exec repositories.SQLExecutor,
+	tournament *models.Tournament,
+	championPID *int,
+	soloMatchRepo repositories.SoloMatchRepository,
+	teamMatchRepo repositories.TeamMatchRepository,
+	standingRepo repositories.TournamentStandingRepository,
+) (map[int]int, error) {
+	if tournament.Format == nil {
+		return nil, fmt.Errorf("format is not loaded for tournament %d", tournament.ID)
+	}
+
+	if tournament.Format.BracketType == "RoundRobin" {
+		standings, err := standingRepo.ListByTournament(ctx, exec, tournament.ID, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list standings for tournament %d: %w", tournament.ID, err)
+		}
+		return computeRoundRobinPlacements(standings), nil
+	}
+
+	var outcomes []matchOutcome
+	if tournament.Format.ParticipantType == models.FormatParticipantSolo {
+		matches, err := soloMatchRepo.ListByTournament(ctx, tournament.ID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list solo matches for tournament %d: %w", tournament.ID, err)
+		}
+		for _, m := range matches {
+			if m.Round == nil || m.Status != models.MatchStatusCompleted {
+				continue
+			}
+			outcomes = append(outcomes, matchOutcome{Round: *m.Round, P1: m.P1ParticipantID, P2: m.P2ParticipantID, Winner: m.WinnerParticipantID})
+		}
+	} else {
+		matches, err := teamMatchRepo.ListByTournament(ctx, tournament.ID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list team matches for tournament %d: %w", tournament.ID, err)
+		}
+		for _, m := range matches {
+			if m.Round == nil || m.Status != models.MatchStatusCompleted {
+				continue
+			}
+			outcomes = append(outcomes, matchOutcome{Round: *m.Round, P1: m.T1ParticipantID, P2: m.T2ParticipantID, Winner: m.WinnerParticipantID})
+		}
+	}
+	return computeEliminationPlacements(outcomes, championPID), nil
+}
+
+// buildTournamentPlacementsFunc собирает итоговые места всех подтверждённых участников турнира.
+// Участники, не сыгравшие ни одного матча, получают последнее место.
+func buildTournamentPlacementsFunc(
+	ctx context.Context,
+	exec repositories.SQLExecutor,
+	tournament *models.Tournament,
+	championPID *int,
+	participantRepo repositories.ParticipantRepository,
+	soloMatchRepo repositories.SoloMatchRepository,
+	teamMatchRepo repositories.TeamMatchRepository,
+	standingRepo repositories.TournamentStandingRepository,
+) ([]models.TournamentPlacement, error) {
+	placementsMap, err := computeTournamentPlacementsFunc(ctx, exec, tournament, championPID, soloMatchRepo, teamMatchRepo, standingRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed := models.StatusParticipant
+	participants, err := participantRepo.ListByTournament(ctx, tournament.ID, &confirmed, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participants of tournament %d: %w", tournament.ID, err)
+	}
+
+	placements := make([]models.TournamentPlacement, 0, len(participants))
+	for _, p := range participants {
+		placement, ok := placementsMap[p.ID]
+		if !ok {
+			placement = len(participants)
+		}
+		placements = append(placements, models.TournamentPlacement{
+			TournamentID:  tournament.ID,
+			ParticipantID: p.ID,
+			Placement:     placement,
+			UserID:        p.UserID,
+			TeamID:        p.TeamID,
+		})
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		return placements[i].Placement < placements[j].Placement
+	})
+	return placements, nil
 }
@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/Dosada05/tournament-system/models"
@@ -29,6 +31,8 @@ var (
 
 type InviteService interface {
 	CreateOrRenewInvite(ctx context.Context, teamID int, currentUserID int) (*models.Invite, error)
+	// InviteByEmail обновляет приглашение команды и ставит письмо с ним в очередь в одной транзакции.
+	InviteByEmail(ctx context.Context, teamID int, currentUserID int, email string) (*models.Invite, error)
 	ValidateAndJoinTeam(ctx context.Context, token string, joiningUserID int) (*models.Team, error)
 	GetTeamInvite(ctx context.Context, teamID int, currentUserID int) (*models.Invite, error)
 	RevokeInvite(ctx context.Context, teamID int, currentUserID int) error
@@ -36,24 +40,103 @@ type InviteService interface {
 }
 
 type inviteService struct {
-	inviteRepo repositories.InviteRepository
-	teamRepo   repositories.TeamRepository
-	userRepo   repositories.UserRepository
+	db            *sql.DB
+	inviteRepo    repositories.InviteRepository
+	teamRepo      repositories.TeamRepository
+	userRepo      repositories.UserRepository
+	emailService  *EmailService
+	outboxService EmailOutboxService
+	notifier      NotificationService
+	logger        *slog.Logger
 }
 
 func NewInviteService(
+	db *sql.DB,
 	ir repositories.InviteRepository,
 	tr repositories.TeamRepository,
 	ur repositories.UserRepository,
+	emailService *EmailService,
+	outboxService EmailOutboxService,
+	notifier NotificationService,
+	logger *slog.Logger,
 ) InviteService {
 	return &inviteService{
-		inviteRepo: ir,
-		teamRepo:   tr,
-		userRepo:   ur,
+		db:            db,
+		inviteRepo:    ir,
+		teamRepo:      tr,
+		userRepo:      ur,
+		emailService:  emailService,
+		outboxService: outboxService,
+		notifier:      notifier,
+		logger:        logger,
 	}
 }
 
 func (s *inviteService) CreateOrRenewInvite(ctx context.Context, teamID int, currentUserID int) (*models.Invite, error) {
+	if _, err := s.getCaptainTeam(ctx, teamID, currentUserID); err != nil {
+		return nil, err
+	}
+	return s.createOrRenewInvite(ctx, nil, teamID)
+}
+
+func (s *inviteService) InviteByEmail(ctx context.Context, teamID int, currentUserID int, email string) (*models.Invite, error) {
+	team, err := s.getCaptainTeam(ctx, teamID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	recipient, invitee := s.inviteRecipient(ctx, email, currentUserID)
+
+	// Зарегистрированный пользователь мог отключить письма о приглашениях;
+	// незарегистрированному адресу письмо отправляется всегда.
+	sendEmail := true
+	if invitee != nil && s.notifier != nil {
+		sendEmail = s.notifier.EmailEnabled(ctx, invitee.ID, models.NotificationTeamInvite)
+	}
+
+	var invite *models.Invite
+	err = runInTransaction(ctx, s.db, s.logger, func(tx repositories.SQLExecutor) error {
+		var txErr error
+		invite, txErr = s.createOrRenewInvite(ctx, tx, teamID)
+		if txErr != nil || !sendEmail {
+			return txErr
+		}
+		inviteEmail, txErr := s.emailService.BuildTeamInviteEmail(recipient, team.Name, invite.Token)
+		if txErr != nil {
+			return txErr
+		}
+		return s.outboxService.Enqueue(ctx, tx, inviteEmail)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if invitee != nil && s.notifier != nil {
+		s.notifier.Notify(ctx, NotificationEvent{
+			Type:        models.NotificationTeamInvite,
+			TeamID:      team.ID,
+			TeamName:    team.Name,
+			InviteToken: invite.Token,
+			InAppOnly:   true,
+		}, invitee.ID)
+	}
+	return invite, nil
+}
+
+// inviteRecipient берёт язык письма у зарегистрированного получателя (он же возвращается вторым значением),
+// а если адрес ещё не зарегистрирован — у капитана, отправившего приглашение.
+func (s *inviteService) inviteRecipient(ctx context.Context, email string, captainID int) (EmailRecipient, *models.User) {
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return RecipientFromUser(user), user
+	}
+	recipient := EmailRecipient{Email: email}
+	if captain, err := s.userRepo.GetByID(ctx, captainID); err == nil {
+		recipient.Locale = captain.Locale
+		recipient.Timezone = captain.Timezone
+	}
+	return recipient, nil
+}
+
+func (s *inviteService) getCaptainTeam(ctx context.Context, teamID int, currentUserID int) (*models.Team, error) {
 	team, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrTeamNotFound) {
@@ -65,7 +148,10 @@ func (s *inviteService) CreateOrRenewInvite(ctx context.Context, teamID int, cur
 	if team.CaptainID != currentUserID {
 		return nil, fmt.Errorf("%w: only team captain can manage invites", ErrForbiddenOperation)
 	}
+	return team, nil
+}
 
+func (s *inviteService) createOrRenewInvite(ctx context.Context, exec repositories.SQLExecutor, teamID int) (*models.Invite, error) {
 	existingInvite, err := s.inviteRepo.GetValidByTeamID(ctx, teamID)
 	if err != nil && !errors.Is(err, repositories.ErrInviteNotFound) {
 		return nil, fmt.Errorf("%w: failed to check existing invite: %w", ErrInviteCreateOrRenew, err)
@@ -80,7 +166,7 @@ func (s *inviteService) CreateOrRenewInvite(ctx context.Context, teamID int, cur
 	if existingInvite != nil {
 		existingInvite.Token = newToken
 		existingInvite.ExpiresAt = expiresAt
-		err = s.inviteRepo.Update(ctx, existingInvite)
+		err = s.inviteRepo.Update(ctx, exec, existingInvite)
 		if err != nil {
 			if errors.Is(err, repositories.ErrInviteTokenConflict) {
 				return nil, fmt.Errorf("%w: token conflict during update, try again: %w", ErrInviteCreateOrRenew, err)
@@ -95,7 +181,7 @@ func (s *inviteService) CreateOrRenewInvite(ctx context.Context, teamID int, cur
 		Token:     newToken,
 		ExpiresAt: expiresAt,
 	}
-	err = s.inviteRepo.Create(ctx, newInvite)
+	err = s.inviteRepo.Create(ctx, exec, newInvite)
 	if err != nil {
 		if errors.Is(err, repositories.ErrInviteTokenConflict) {
 			return nil, fmt.Errorf("%w: token conflict during create, try again: %w", ErrInviteCreateOrRenew, err)
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/Dosada05/tournament-system/imaging"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var ErrLogoTooLarge = errors.New("logo image is too large")
+
+// uploadLogoFunc проверяет и обрабатывает изображение, затем загружает оригинал и все
+// варианты в директорию dir. Возвращает ключ оригинала — его сохраняют в logo_key.
+// Если загрузка одного из файлов не удалась, уже загруженные удаляются.
+func uploadLogoFunc(ctx context.Context, uploader storage.FileUploader, processor *imaging.Processor, dir string, file io.Reader) (string, error) {
+	if uploader == nil {
+		return "", errors.New("file uploader is not configured")
+	}
+	if processor == nil {
+		processor = imaging.NewProcessor(imaging.DefaultConfig())
+	}
+
+	files, err := processor.Process(file)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			return "", fmt.Errorf("%w: %w", ErrLogoTooLarge, err)
+		case errors.Is(err, imaging.ErrUnsupportedFormat), errors.Is(err, imaging.ErrMalformedImage):
+			return "", fmt.Errorf("%w: %w", ErrInvalidLogoFormat, err)
+		}
+		return "", err
+	}
+
+	uploaded := make([]string, 0, len(files))
+	for _, f := range files {
+		key := dir + "/" + f.Name
+		if _, err := uploader.Upload(ctx, key, f.ContentType, bytes.NewReader(f.Data)); err != nil {
+			for _, k := range uploaded {
+				if delErr := uploader.Delete(context.Background(), k); delErr != nil {
+					slog.Warn("failed to clean up partially uploaded logo", slog.String("key", k), slog.Any("error", delErr))
+				}
+			}
+			return "", fmt.Errorf("%w: %s: %w", ErrLogoUploadFailed, key, err)
+		}
+		uploaded = append(uploaded, key)
+	}
+	// Process всегда возвращает оригинал первым.
+	return uploaded[0], nil
+}
+
+// deleteLogoFunc удаляет оригинал логотипа и все его варианты.
+func deleteLogoFunc(ctx context.Context, uploader storage.FileUploader, key string) error {
+	if uploader == nil || key == "" {
+		return nil
+	}
+	var errs []error
+	for _, formats := range imaging.VariantKeys(key, imaging.DefaultVariants) {
+		for _, variantKey := range formats {
+			if err := uploader.Delete(ctx, variantKey); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := uploader.Delete(ctx, key); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// logoURLsFunc строит публичные ссылки на оригинал и варианты логотипа по ключу из logo_key.
+func logoURLsFunc(uploader storage.FileUploader, key *string) (*string, *models.LogoURLs) {
+	if uploader == nil || key == nil || *key == "" {
+		return nil, nil
+	}
+	url := uploader.GetPublicURL(*key)
+	if url == "" {
+		return nil, nil
+	}
+	urls := &models.LogoURLs{Original: url}
+	variants := imaging.VariantKeys(*key, imaging.DefaultVariants)
+	if formats, ok := variants[imaging.VariantMedium]; ok {
+		urls.Medium = &models.ImageVariantURLs{
+			WebP: uploader.GetPublicURL(formats[imaging.FormatWebP]),
+			PNG:  uploader.GetPublicURL(formats[imaging.FormatPNG]),
+		}
+	}
+	if formats, ok := variants[imaging.VariantThumbnail]; ok {
+		urls.Thumbnail = &models.ImageVariantURLs{
+			WebP: uploader.GetPublicURL(formats[imaging.FormatWebP]),
+			PNG:  uploader.GetPublicURL(formats[imaging.FormatPNG]),
+		}
+	}
+	return &url, urls
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Dosada05/tournament-system/db"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+type MatchReminderService interface {
+	// SendDueReminders рассылает напоминания о матчах, которые начнутся в ближайшие lead минут.
+	// Возвращает число пользователей, получивших напоминание.
+	SendDueReminders(ctx context.Context) (int, error)
+}
+
+type matchReminderService struct {
+	db           *sql.DB
+	reminderRepo repositories.MatchReminderRepository
+	notifier     NotificationService
+	lead         time.Duration
+	logger       *slog.Logger
+}
+
+func NewMatchReminderService(
+	db *sql.DB,
+	reminderRepo repositories.MatchReminderRepository,
+	notifier NotificationService,
+	lead time.Duration,
+	logger *slog.Logger,
+) MatchReminderService {
+	return &matchReminderService{
+		db:           db,
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+		lead:         lead,
+		logger:       logger,
+	}
+}
+
+func (s *matchReminderService) SendDueReminders(ctx context.Context) (int, error) {
+	if s.lead <= 0 {
+		return 0, nil
+	}
+
+	// Отметки об отправке фиксируются под advisory-lock планировщика, поэтому при нескольких
+	// экземплярах каждое напоминание получает ровно один из них. Рассылка идёт после коммита:
+	// при сбое между коммитом и отправкой напоминание будет потеряно, но не продублировано.
+	var toSend []models.MatchReminder
+	err := runInTransaction(ctx, s.db, s.logger, func(exec repositories.SQLExecutor) error {
+		tx, ok := exec.(*sql.Tx)
+		if !ok {
+			return fmt.Errorf("match reminders: unexpected executor %T", exec)
+		}
+		acquired, err := db.TryAcquireTransactionalLock(ctx, tx, db.SchedulerAdvisoryLockID, s.logger)
+		if err != nil {
+			return fmt.Errorf("match reminders: failed to acquire lock: %w", err)
+		}
+		if !acquired {
+			s.logger.InfoContext(ctx, "Match reminders: lock is held by another instance, skipping cycle")
+			return nil
+		}
+
+		due, err := s.reminderRepo.ListDue(ctx, tx, time.Now(), s.lead)
+		if err != nil {
+			return err
+		}
+		for _, reminder := range due {
+			inserted, err := s.reminderRepo.MarkSent(ctx, tx, reminder)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				toSend = append(toSend, reminder)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, reminder := range toSend {
+		s.notifier.Notify(ctx, NotificationEvent{
+			Type:       models.NotificationMatchReminder,
+			Tournament: &models.Tournament{ID: reminder.TournamentID, Name: reminder.TournamentName},
+			MatchType:  reminder.MatchType,
+			MatchID:    reminder.MatchID,
+			MatchTime:  reminder.MatchTime,
+		}, reminder.UserID)
+	}
+	return len(toSend), nil
+}
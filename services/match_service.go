@@ -6,12 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Dosada05/tournament-system/brackets"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/scoring"
 )
 
 // Определения ошибок на уровне пакета
@@ -25,18 +26,53 @@ var (
 	ErrSoloMatchNotFound             = repositories.ErrSoloMatchNotFound
 	ErrTeamMatchNotFound             = repositories.ErrTeamMatchNotFound
 	ErrScoreParsingFailed            = errors.New("failed to parse score string")
+	ErrMatchNotReady                 = errors.New("match is not ready, participants not set")
+	ErrMatchAlreadyStarted           = errors.New("match has already started")
+	ErrMatchNotStarted               = errors.New("match is not in progress")
+	ErrInvalidMatchTime              = errors.New("match time must be set and not in the past")
 )
 
+// liveScoreStart — счёт, с которым матч переводится в in_progress.
+const liveScoreStart = "0-0"
+
 type UpdateMatchResultInput struct {
 	Score               *string `json:"score" validate:"omitempty,max=50"` // e.g., "3-1", "2-2"
 	WinnerParticipantID *int    `json:"winner_participant_id,omitempty"`   // Pointer to allow nil for draws
 }
 
+// RescheduleMatchInput — новое время запланированного матча.
+type RescheduleMatchInput struct {
+	MatchTime time.Time `json:"match_time"`
+}
+
+// LiveScoreInput — текущий счёт идущего матча ("S1-S2" с точки зрения P1/T1).
+type LiveScoreInput struct {
+	Score string `json:"score"`
+}
+
 type MatchService interface {
 	ListSoloMatchesByTournament(ctx context.Context, tournamentID int) ([]*models.SoloMatch, error)
 	ListTeamMatchesByTournament(ctx context.Context, tournamentID int) ([]*models.TeamMatch, error)
 	UpdateSoloMatchResult(ctx context.Context, matchID int, tournamentID int, input UpdateMatchResultInput, currentUserID int) (*models.SoloMatch, error)
 	UpdateTeamMatchResult(ctx context.Context, matchID int, tournamentID int, input UpdateMatchResultInput, currentUserID int) (*models.TeamMatch, error)
+
+	// StartSoloMatch / StartTeamMatch переводят запланированный матч в in_progress со счётом 0-0.
+	StartSoloMatch(ctx context.Context, matchID int, tournamentID int, currentUserID int) (*models.SoloMatch, error)
+	StartTeamMatch(ctx context.Context, matchID int, tournamentID int, currentUserID int) (*models.TeamMatch, error)
+	// UpdateSoloLiveScore / UpdateTeamLiveScore обновляют счёт идущего матча.
+	// Итог фиксируется через UpdateSoloMatchResult / UpdateTeamMatchResult.
+	UpdateSoloLiveScore(ctx context.Context, matchID int, tournamentID int, input LiveScoreInput, currentUserID int) (*models.SoloMatch, error)
+	UpdateTeamLiveScore(ctx context.Context, matchID int, tournamentID int, input LiveScoreInput, currentUserID int) (*models.TeamMatch, error)
+	// RescheduleSoloMatch / RescheduleTeamMatch переносят ещё не начавшийся матч и уведомляют его участников.
+	RescheduleSoloMatch(ctx context.Context, matchID int, tournamentID int, input RescheduleMatchInput, currentUserID int) (*models.SoloMatch, error)
+	RescheduleTeamMatch(ctx context.Context, matchID int, tournamentID int, input RescheduleMatchInput, currentUserID int) (*models.TeamMatch, error)
+
+	// Хронология матча (голы, карточки, замены, раунды, тайм-ауты); события вносятся, пока матч идёт.
+	ListMatchEvents(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int) ([]models.MatchEvent, error)
+	// ListTournamentMatchEvents — события всех матчей турнира по ID матча (для сетки).
+	ListTournamentMatchEvents(ctx context.Context, tournamentID int, matchType models.RatingMatchType) (map[int][]models.MatchEvent, error)
+	AddMatchEvent(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int, input MatchEventInput, currentUserID int) (*models.MatchEvent, error)
+	DeleteMatchEvent(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int, eventID int, currentUserID int) error
 }
 
 type matchService struct {
@@ -47,6 +83,12 @@ type matchService struct {
 	participantRepo repositories.ParticipantRepository
 	formatRepo      repositories.FormatRepository             // Added
 	standingRepo    repositories.TournamentStandingRepository // Added
+	orgRepo         repositories.OrganizationRepository
+	sportRepo       repositories.SportRepository
+	matchEventRepo  repositories.MatchEventRepository
+	ratingService   RatingService
+	notifier        NotificationService
+	webhooks        WebhookService
 	hub             *brackets.Hub
 	logger          *slog.Logger // Added
 }
@@ -59,6 +101,12 @@ func NewMatchService(
 	participantRepo repositories.ParticipantRepository,
 	formatRepo repositories.FormatRepository, // Added
 	standingRepo repositories.TournamentStandingRepository, // Added
+	orgRepo repositories.OrganizationRepository,
+	sportRepo repositories.SportRepository,
+	matchEventRepo repositories.MatchEventRepository,
+	ratingService RatingService,
+	notifier NotificationService,
+	webhooks WebhookService,
 	hub *brackets.Hub,
 	logger *slog.Logger, // Added
 ) MatchService {
@@ -70,34 +118,44 @@ func NewMatchService(
 		participantRepo: participantRepo,
 		formatRepo:      formatRepo,   // Added
 		standingRepo:    standingRepo, // Added
+		orgRepo:         orgRepo,
+		sportRepo:       sportRepo,
+		matchEventRepo:  matchEventRepo,
+		ratingService:   ratingService,
+		notifier:        notifier,
+		webhooks:        webhooks,
 		hub:             hub,
 		logger:          logger, // Added
 	}
 }
 
 func (s *matchService) withTransaction(ctx context.Context, fn func(tx repositories.SQLExecutor) error) error {
-	dbTx, err := s.db.BeginTx(ctx, nil)
+	return runInTransaction(ctx, s.db, s.logger, fn)
+}
+
+// runInTransaction выполняет fn в транзакции: коммит при успехе, откат при ошибке или панике.
+func runInTransaction(ctx context.Context, db *sql.DB, logger *slog.Logger, fn func(tx repositories.SQLExecutor) error) (opErr error) {
+	dbTx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "Failed to begin transaction", slog.Any("error", err))
+		logger.ErrorContext(ctx, "Failed to begin transaction", slog.Any("error", err))
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	var opErr error
 	defer func() {
 		if p := recover(); p != nil {
-			s.logger.ErrorContext(ctx, "Recovered from panic in transaction", slog.Any("panic_value", p))
+			logger.ErrorContext(ctx, "Recovered from panic in transaction", slog.Any("panic_value", p))
 			_ = dbTx.Rollback()
 			panic(p)
 		} else if opErr != nil {
-			s.logger.ErrorContext(ctx, "Transaction error, rolling back", slog.Any("operation_error", opErr))
+			logger.ErrorContext(ctx, "Transaction error, rolling back", slog.Any("operation_error", opErr))
 			if rbErr := dbTx.Rollback(); rbErr != nil {
-				s.logger.ErrorContext(ctx, "Transaction rollback failed", slog.Any("rollback_error", rbErr))
+				logger.ErrorContext(ctx, "Transaction rollback failed", slog.Any("rollback_error", rbErr))
 			}
 		} else {
 			if cErr := dbTx.Commit(); cErr != nil {
-				s.logger.ErrorContext(ctx, "Failed to commit transaction", slog.Any("commit_error", cErr))
+				logger.ErrorContext(ctx, "Failed to commit transaction", slog.Any("commit_error", cErr))
 				opErr = fmt.Errorf("failed to commit transaction: %w", cErr)
 			} else {
-				s.logger.DebugContext(ctx, "Transaction committed successfully")
+				logger.DebugContext(ctx, "Transaction committed successfully")
 			}
 		}
 	}()
@@ -105,25 +163,48 @@ func (s *matchService) withTransaction(ctx context.Context, fn func(tx repositor
 	return opErr
 }
 
-// parseScore expects a score string like "S1-S2" (e.g., "3-1", "0-0").
-// It returns scoreP1, scoreP2, isDraw, error.
-func parseScore(scoreStr string) (int, int, bool, error) {
-	if scoreStr == "" {
-		return 0, 0, false, fmt.Errorf("%w: score string is empty", ErrScoreParsingFailed)
+// resolveMatchResult проверяет итоговый счёт по правилам спорта и определяет по нему победителя.
+// WinnerParticipantID из запроса необязателен, но если передан — должен совпадать с победителем по счёту.
+func resolveMatchResult(rules models.ScoringRules, format *models.Format, first, second int, input *UpdateMatchResultInput) (*scoring.Result, error) {
+	if format != nil && format.BracketType == "SingleElimination" {
+		rules.DrawAllowed = false // В сетке на выбывание нужен победитель
+	}
+	if input.Score == nil || strings.TrimSpace(*input.Score) == "" {
+		return nil, fmt.Errorf("%w: score string is required", ErrScoreParsingFailed)
+	}
+	result, err := scoring.Parse(rules, *input.Score, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrScoreParsingFailed, err)
 	}
-	parts := strings.Split(scoreStr, "-")
-	if len(parts) != 2 {
-		return 0, 0, false, fmt.Errorf("%w: score string '%s' must be in 'S1-S2' format", ErrScoreParsingFailed, scoreStr)
+
+	var winner *int
+	switch result.Winner {
+	case scoring.WinnerFirst:
+		winner = &first
+	case scoring.WinnerSecond:
+		winner = &second
 	}
-	s1, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
-	s2, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
-	if err1 != nil || err2 != nil {
-		return 0, 0, false, fmt.Errorf("%w: could not parse scores from '%s': %v, %v", ErrScoreParsingFailed, scoreStr, err1, err2)
+	if input.WinnerParticipantID != nil && (winner == nil || *input.WinnerParticipantID != *winner) {
+		return nil, fmt.Errorf("%w: participant %d is not the winner by score '%s'", ErrMatchInvalidWinner, *input.WinnerParticipantID, *input.Score)
 	}
-	if s1 < 0 || s2 < 0 {
-		return 0, 0, false, fmt.Errorf("%w: scores cannot be negative in '%s'", ErrScoreParsingFailed, scoreStr)
+	input.WinnerParticipantID = winner
+	return result, nil
+}
+
+// addStandingScores учитывает счёт матча в забитых/пропущенных и разнице. Таблица сортируется
+// по score_difference и score_for по убыванию, поэтому для видов спорта, где меньше — лучше
+// (гольф), и для времени в миллисекундах счёт в таблицу не идёт: места определяются только
+// очками за победы и ничьи.
+func addStandingScores(rules models.ScoringRules, p1Stand, p2Stand *models.TournamentStanding, score1, score2 int) {
+	if rules.LowerIsBetter || rules.Schema == models.ScoreSchemaTime {
+		return
 	}
-	return s1, s2, s1 == s2, nil
+	p1Stand.ScoreFor += score1
+	p1Stand.ScoreAgainst += score2
+	p2Stand.ScoreFor += score2
+	p2Stand.ScoreAgainst += score1
+	p1Stand.ScoreDifference = p1Stand.ScoreFor - p1Stand.ScoreAgainst
+	p2Stand.ScoreDifference = p2Stand.ScoreFor - p2Stand.ScoreAgainst
 }
 
 func (s *matchService) ListSoloMatchesByTournament(ctx context.Context, tournamentID int) ([]*models.SoloMatch, error) {
@@ -135,6 +216,13 @@ func (s *matchService) ListSoloMatchesByTournament(ctx context.Context, tourname
 	if matches == nil {
 		return []*models.SoloMatch{}, nil
 	}
+	events, err := s.ListTournamentMatchEvents(ctx, tournamentID, models.RatingMatchSolo)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to load solo match events", slog.Int("tournament_id", tournamentID), slog.Any("error", err))
+	}
+	for _, m := range matches {
+		m.Events = events[m.ID]
+	}
 	return matches, nil
 }
 
@@ -147,6 +235,13 @@ func (s *matchService) ListTeamMatchesByTournament(ctx context.Context, tourname
 	if matches == nil {
 		return []*models.TeamMatch{}, nil
 	}
+	events, err := s.ListTournamentMatchEvents(ctx, tournamentID, models.RatingMatchTeam)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to load team match events", slog.Int("tournament_id", tournamentID), slog.Any("error", err))
+	}
+	for _, m := range matches {
+		m.Events = events[m.ID]
+	}
 	return matches, nil
 }
 
@@ -165,7 +260,11 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 		tournament.Format = format
 	}
 
-	if tournament.OrganizerID != currentUserID {
+	canManage, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
 		s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Forbidden", slog.Int("organizer_id", tournament.OrganizerID), slog.Int("current_user_id", currentUserID))
 		return nil, ErrMatchUpdateForbidden
 	}
@@ -195,47 +294,38 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 		return nil, fmt.Errorf("match %d is not ready, participants not set (P1: %v, P2: %v)", matchID, currentMatch.P1ParticipantID, currentMatch.P2ParticipantID)
 	}
 
-	// Score and Winner validation based on format type
-	isDraw := false
-	var scoreP1, scoreP2 int
-	if input.Score != nil && *input.Score != "" {
-		var parseErr error
-		scoreP1, scoreP2, isDraw, parseErr = parseScore(*input.Score)
-		if parseErr != nil {
-			s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Score parsing failed", slog.String("score_string", *input.Score), slog.Any("error", parseErr))
-			return nil, parseErr
-		}
-	} else {
-		s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Score string is missing")
-		return nil, fmt.Errorf("%w: score string is required", ErrScoreParsingFailed)
-	}
-
-	// Validate WinnerParticipantID against participants and draw status
-	if isDraw {
-		if input.WinnerParticipantID != nil {
-			s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Winner specified for a draw", slog.Any("winner_id", input.WinnerParticipantID))
-			return nil, fmt.Errorf("cannot specify winner for a draw match (score: %s)", *input.Score)
-		}
-	} else {
-		if input.WinnerParticipantID == nil {
-			s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Winner not specified for a non-draw match")
-			return nil, errors.New("winner must be specified if the match is not a draw")
-		}
-		if *input.WinnerParticipantID != *currentMatch.P1ParticipantID && *input.WinnerParticipantID != *currentMatch.P2ParticipantID {
-			s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Invalid winner ID",
-				slog.Any("winner_id", input.WinnerParticipantID),
-				slog.Any("p1_id", currentMatch.P1ParticipantID),
-				slog.Any("p2_id", currentMatch.P2ParticipantID))
-			return nil, fmt.Errorf("%w: winner ID %d is not P1 (%d) or P2 (%d) of match %d",
-				ErrMatchInvalidWinner, *input.WinnerParticipantID, *currentMatch.P1ParticipantID, *currentMatch.P2ParticipantID, matchID)
-		}
+	sport, err := s.getTournamentSport(ctx, tournament)
+	if err != nil {
+		return nil, err
 	}
+	var scoreP1, scoreP2 int
+	var isDraw bool
 
 	var updatedMatch *models.SoloMatch
 	var nextMatchToNotify *models.SoloMatch
 	isFinalMatchForBranch := currentMatch.NextMatchDBID == nil
 
 	opErr := s.withTransaction(ctx, func(tx repositories.SQLExecutor) error {
+		// Строка матча блокируется до подсчёта голов: гол, который добавляют параллельно, либо уже
+		// учтён в счёте, либо его транзакция упадёт на завершённом матче.
+		if err := s.soloMatchRepo.LockUnfinished(ctx, tx, matchID); err != nil {
+			if errors.Is(err, repositories.ErrSoloMatchStatusConflict) {
+				return ErrMatchAlreadyCompleted
+			}
+			return fmt.Errorf("failed to lock solo match %d: %w", matchID, err)
+		}
+		// Для видов спорта, где счёт ведётся по голам, итог берётся из хронологии
+		if err := s.resolveScoreFromGoals(ctx, tx, sport, models.RatingMatchSolo, matchID, *currentMatch.P1ParticipantID, *currentMatch.P2ParticipantID, &input); err != nil {
+			return err
+		}
+		// Счёт проверяется по правилам спорта, победитель определяется по нему
+		result, err := resolveMatchResult(sport.ScoringRules, tournament.Format, *currentMatch.P1ParticipantID, *currentMatch.P2ParticipantID, &input)
+		if err != nil {
+			s.logger.WarnContext(ctx, "UpdateSoloMatchResult: Invalid result", slog.Any("score", input.Score), slog.Any("error", err))
+			return err
+		}
+		scoreP1, scoreP2, isDraw = result.Score1, result.Score2, result.Winner == scoring.WinnerNone
+
 		var txInternalErr error
 		txInternalErr = s.soloMatchRepo.UpdateScoreStatusWinner(ctx, tx, matchID, input.Score, models.MatchStatusCompleted, input.WinnerParticipantID)
 		if txInternalErr != nil {
@@ -257,12 +347,7 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 
 			p1Stand.GamesPlayed++
 			p2Stand.GamesPlayed++
-			p1Stand.ScoreFor += scoreP1
-			p1Stand.ScoreAgainst += scoreP2
-			p2Stand.ScoreFor += scoreP2
-			p2Stand.ScoreAgainst += scoreP1
-			p1Stand.ScoreDifference = p1Stand.ScoreFor - p1Stand.ScoreAgainst
-			p2Stand.ScoreDifference = p2Stand.ScoreFor - p2Stand.ScoreAgainst
+			addStandingScores(sport.ScoringRules, p1Stand, p2Stand, scoreP1, scoreP2)
 
 			if isDraw {
 				p1Stand.Points++
@@ -322,6 +407,12 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 				}
 			}
 		}
+
+		if s.ratingService != nil {
+			if ratingErr := s.ratingService.ApplyMatchResult(ctx, tx, models.RatingMatchSolo, matchID); ratingErr != nil {
+				return fmt.Errorf("failed to update ratings for solo match %d: %w", matchID, ratingErr)
+			}
+		}
 		return nil
 	})
 
@@ -347,26 +438,28 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 
 	// WebSocket Notifications
 	if s.hub != nil && updatedMatch != nil {
-		roomID := "tournament_" + strconv.Itoa(tournamentID)
-		s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "MATCH_UPDATED", Payload: updatedMatch, RoomID: roomID})
-		s.logger.InfoContext(ctx, "Sent MATCH_UPDATED", slog.Int("match_id", updatedMatch.ID), slog.String("room_id", roomID))
+		topic := brackets.TournamentTopic(tournamentID)
+		s.hub.Publish(topic, brackets.EventMatchUpdated, updatedMatch)
+		s.hub.Publish(brackets.MatchTopic(models.RatingMatchSolo, updatedMatch.ID), brackets.EventMatchUpdated, updatedMatch)
+		s.logger.InfoContext(ctx, "Sent MATCH_UPDATED", slog.Int("match_id", updatedMatch.ID), slog.String("topic", topic))
 
 		if tournament.Format.BracketType == "RoundRobin" {
 			standings, listErr := s.standingRepo.ListByTournament(ctx, s.db, tournamentID, true) // Use main db for read
 			if listErr == nil {
 				// Ideally, transform standings to a view model before sending
-				s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "STANDINGS_UPDATED", Payload: standings, RoomID: roomID})
-				s.logger.InfoContext(ctx, "Sent STANDINGS_UPDATED", slog.Int("tournament_id", tournamentID), slog.String("room_id", roomID))
+				s.hub.Publish(topic, brackets.EventStandingsUpdated, standings)
+				s.logger.InfoContext(ctx, "Sent STANDINGS_UPDATED", slog.Int("tournament_id", tournamentID), slog.String("topic", topic))
 			} else {
 				s.logger.ErrorContext(ctx, "Failed to list standings for WebSocket broadcast", slog.Int("tournament_id", tournamentID), slog.Any("error", listErr))
 			}
 		} else if tournament.Format.BracketType == "SingleElimination" {
 			if updatedMatch.NextMatchDBID != nil && updatedMatch.WinnerToSlot != nil && updatedMatch.WinnerParticipantID != nil {
-				advPayload := map[string]interface{}{"advancing_participant_db_id": *updatedMatch.WinnerParticipantID, "source_match_id": updatedMatch.ID, "next_match_id": *updatedMatch.NextMatchDBID, "next_match_slot": *updatedMatch.WinnerToSlot, "tournament_id": tournamentID}
-				s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "PARTICIPANT_ADVANCED", Payload: advPayload, RoomID: roomID})
+				advPayload := brackets.ParticipantAdvancedPayload{TournamentID: tournamentID, SourceMatchID: updatedMatch.ID, AdvancingParticipantDBID: *updatedMatch.WinnerParticipantID, NextMatchID: *updatedMatch.NextMatchDBID, NextMatchSlot: *updatedMatch.WinnerToSlot}
+				s.hub.Publish(topic, brackets.EventParticipantAdvanced, advPayload)
 				s.logger.InfoContext(ctx, "Sent PARTICIPANT_ADVANCED", slog.Int("match_id", updatedMatch.ID))
 				if nextMatchToNotify != nil && (nextMatchToNotify.P1ParticipantID != nil && nextMatchToNotify.P2ParticipantID != nil) && nextMatchToNotify.Status == models.StatusScheduled {
-					s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "MATCH_UPDATED", Payload: nextMatchToNotify, RoomID: roomID})
+					s.hub.Publish(topic, brackets.EventMatchUpdated, nextMatchToNotify)
+					s.hub.Publish(brackets.MatchTopic(models.RatingMatchSolo, nextMatchToNotify.ID), brackets.EventMatchUpdated, nextMatchToNotify)
 					s.logger.InfoContext(ctx, "Sent MATCH_UPDATED for next match", slog.Int("next_match_id", nextMatchToNotify.ID))
 				}
 			}
@@ -382,11 +475,21 @@ func (s *matchService) UpdateSoloMatchResult(ctx context.Context, matchID int, t
 			// This might require querying how many matches are in the highest round, or if this match has no further next_match_db_id set by any other match.
 			// For simplicity, we assume if NextMatchDBID is nil, it's a candidate for final match.
 			// The actual finalization decision is better handled by TournamentService.FinalizeTournament.
-			finalMatchPayload := map[string]interface{}{"match_id": updatedMatch.ID, "tournament_id": tournamentID, "winner_participant_id": *updatedMatch.WinnerParticipantID, "is_tournament_final_match": true /* This flag needs more robust logic */}
-			s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "TOURNAMENT_FINAL_MATCH_COMPLETED", Payload: finalMatchPayload, RoomID: roomID})
+			finalMatchPayload := brackets.FinalMatchCompletedPayload{TournamentID: tournamentID, MatchID: updatedMatch.ID, WinnerParticipantID: *updatedMatch.WinnerParticipantID, IsTournamentFinalMatch: true} // This flag needs more robust logic
+			s.hub.Publish(topic, brackets.EventTournamentFinalMatchCompleted, finalMatchPayload)
 			s.logger.InfoContext(ctx, "Sent TOURNAMENT_FINAL_MATCH_COMPLETED (candidate)", slog.Int("match_id", updatedMatch.ID))
 		}
 	}
+
+	if updatedMatch != nil {
+		s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchResult, Tournament: tournament, MatchType: models.RatingMatchSolo, MatchID: updatedMatch.ID, Score: derefString(updatedMatch.Score)},
+			updatedMatch.P1ParticipantID, updatedMatch.P2ParticipantID)
+		s.emitWebhook(ctx, models.WebhookMatchCompleted, tournament, webhookMatchData{MatchType: models.RatingMatchSolo, Match: updatedMatch})
+	}
+	if nextMatchToNotify != nil && nextMatchToNotify.P1ParticipantID != nil && nextMatchToNotify.P2ParticipantID != nil && nextMatchToNotify.Status == models.StatusScheduled {
+		s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchScheduled, Tournament: tournament, MatchType: models.RatingMatchSolo, MatchID: nextMatchToNotify.ID, MatchTime: nextMatchToNotify.MatchTime},
+			nextMatchToNotify.P1ParticipantID, nextMatchToNotify.P2ParticipantID)
+	}
 	return updatedMatch, nil
 }
 
@@ -406,7 +509,11 @@ func (s *matchService) UpdateTeamMatchResult(ctx context.Context, matchID int, t
 		tournament.Format = format
 	}
 
-	if tournament.OrganizerID != currentUserID {
+	canManage, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
 		s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Forbidden", slog.Int("organizer_id", tournament.OrganizerID), slog.Int("current_user_id", currentUserID))
 		return nil, ErrMatchUpdateForbidden
 	}
@@ -436,45 +543,38 @@ func (s *matchService) UpdateTeamMatchResult(ctx context.Context, matchID int, t
 		return nil, fmt.Errorf("match %d is not ready, participants not set (T1: %v, T2: %v)", matchID, currentMatch.T1ParticipantID, currentMatch.T2ParticipantID)
 	}
 
-	isDraw := false
-	var scoreT1, scoreT2 int
-	if input.Score != nil && *input.Score != "" {
-		var parseErr error
-		scoreT1, scoreT2, isDraw, parseErr = parseScore(*input.Score)
-		if parseErr != nil {
-			s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Score parsing failed", slog.String("score_string", *input.Score), slog.Any("error", parseErr))
-			return nil, parseErr
-		}
-	} else {
-		s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Score string is missing")
-		return nil, fmt.Errorf("%w: score string is required", ErrScoreParsingFailed)
-	}
-
-	if isDraw {
-		if input.WinnerParticipantID != nil {
-			s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Winner specified for a draw")
-			return nil, fmt.Errorf("cannot specify winner for a draw match (score: %s)", *input.Score)
-		}
-	} else {
-		if input.WinnerParticipantID == nil {
-			s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Winner not specified for a non-draw")
-			return nil, errors.New("winner must be specified if the match is not a draw")
-		}
-		if *input.WinnerParticipantID != *currentMatch.T1ParticipantID && *input.WinnerParticipantID != *currentMatch.T2ParticipantID {
-			s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Invalid winner ID",
-				slog.Any("winner_id", input.WinnerParticipantID),
-				slog.Any("t1_id", currentMatch.T1ParticipantID),
-				slog.Any("t2_id", currentMatch.T2ParticipantID))
-			return nil, fmt.Errorf("%w: winner ID %d is not T1 (%d) or T2 (%d) of match %d",
-				ErrMatchInvalidWinner, *input.WinnerParticipantID, *currentMatch.T1ParticipantID, *currentMatch.T2ParticipantID, matchID)
-		}
+	sport, err := s.getTournamentSport(ctx, tournament)
+	if err != nil {
+		return nil, err
 	}
+	var scoreT1, scoreT2 int
+	var isDraw bool
 
 	var updatedMatch *models.TeamMatch
 	var nextMatchToNotify *models.TeamMatch
 	isFinalMatchForBranch := currentMatch.NextMatchDBID == nil
 
 	opErr := s.withTransaction(ctx, func(tx repositories.SQLExecutor) error {
+		// Строка матча блокируется до подсчёта голов: гол, который добавляют параллельно, либо уже
+		// учтён в счёте, либо его транзакция упадёт на завершённом матче.
+		if err := s.teamMatchRepo.LockUnfinished(ctx, tx, matchID); err != nil {
+			if errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+				return ErrMatchAlreadyCompleted
+			}
+			return fmt.Errorf("failed to lock team match %d: %w", matchID, err)
+		}
+		// Для видов спорта, где счёт ведётся по голам, итог берётся из хронологии
+		if err := s.resolveScoreFromGoals(ctx, tx, sport, models.RatingMatchTeam, matchID, *currentMatch.T1ParticipantID, *currentMatch.T2ParticipantID, &input); err != nil {
+			return err
+		}
+		// Счёт проверяется по правилам спорта, победитель определяется по нему
+		result, err := resolveMatchResult(sport.ScoringRules, tournament.Format, *currentMatch.T1ParticipantID, *currentMatch.T2ParticipantID, &input)
+		if err != nil {
+			s.logger.WarnContext(ctx, "UpdateTeamMatchResult: Invalid result", slog.Any("score", input.Score), slog.Any("error", err))
+			return err
+		}
+		scoreT1, scoreT2, isDraw = result.Score1, result.Score2, result.Winner == scoring.WinnerNone
+
 		var txInternalErr error
 		txInternalErr = s.teamMatchRepo.UpdateScoreStatusWinner(ctx, tx, matchID, input.Score, models.MatchStatusCompleted, input.WinnerParticipantID)
 		if txInternalErr != nil {
@@ -495,12 +595,7 @@ func (s *matchService) UpdateTeamMatchResult(ctx context.Context, matchID int, t
 
 			p1Stand.GamesPlayed++
 			p2Stand.GamesPlayed++
-			p1Stand.ScoreFor += scoreT1
-			p1Stand.ScoreAgainst += scoreT2
-			p2Stand.ScoreFor += scoreT2
-			p2Stand.ScoreAgainst += scoreT1
-			p1Stand.ScoreDifference = p1Stand.ScoreFor - p1Stand.ScoreAgainst
-			p2Stand.ScoreDifference = p2Stand.ScoreFor - p2Stand.ScoreAgainst
+			addStandingScores(sport.ScoringRules, p1Stand, p2Stand, scoreT1, scoreT2)
 
 			if isDraw {
 				p1Stand.Points++
@@ -558,6 +653,12 @@ func (s *matchService) UpdateTeamMatchResult(ctx context.Context, matchID int, t
 				}
 			}
 		}
+
+		if s.ratingService != nil {
+			if ratingErr := s.ratingService.ApplyMatchResult(ctx, tx, models.RatingMatchTeam, matchID); ratingErr != nil {
+				return fmt.Errorf("failed to update ratings for team match %d: %w", matchID, ratingErr)
+			}
+		}
 		return nil
 	})
 
@@ -579,34 +680,362 @@ func (s *matchService) UpdateTeamMatchResult(ctx context.Context, matchID int, t
 	}
 
 	if s.hub != nil && updatedMatch != nil {
-		roomID := "tournament_" + strconv.Itoa(tournamentID)
-		s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "MATCH_UPDATED", Payload: updatedMatch, RoomID: roomID})
-		s.logger.InfoContext(ctx, "Sent MATCH_UPDATED", slog.Int("match_id", updatedMatch.ID), slog.String("room_id", roomID))
+		topic := brackets.TournamentTopic(tournamentID)
+		s.hub.Publish(topic, brackets.EventMatchUpdated, updatedMatch)
+		s.hub.Publish(brackets.MatchTopic(models.RatingMatchTeam, updatedMatch.ID), brackets.EventMatchUpdated, updatedMatch)
+		s.logger.InfoContext(ctx, "Sent MATCH_UPDATED", slog.Int("match_id", updatedMatch.ID), slog.String("topic", topic))
 
 		if tournament.Format.BracketType == "RoundRobin" {
 			standings, listErr := s.standingRepo.ListByTournament(ctx, s.db, tournamentID, true) // Use main db for read
 			if listErr == nil {
-				s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "STANDINGS_UPDATED", Payload: standings, RoomID: roomID})
+				s.hub.Publish(topic, brackets.EventStandingsUpdated, standings)
 				s.logger.InfoContext(ctx, "Sent STANDINGS_UPDATED", slog.Int("tournament_id", tournamentID))
 			} else {
 				s.logger.ErrorContext(ctx, "Failed to list standings for WebSocket broadcast", slog.Any("error", listErr))
 			}
 		} else if tournament.Format.BracketType == "SingleElimination" {
 			if updatedMatch.NextMatchDBID != nil && updatedMatch.WinnerToSlot != nil && updatedMatch.WinnerParticipantID != nil {
-				advPayload := map[string]interface{}{"advancing_participant_db_id": *updatedMatch.WinnerParticipantID, "source_match_id": updatedMatch.ID, "next_match_id": *updatedMatch.NextMatchDBID, "next_match_slot": *updatedMatch.WinnerToSlot, "tournament_id": tournamentID}
-				s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "PARTICIPANT_ADVANCED", Payload: advPayload, RoomID: roomID})
+				advPayload := brackets.ParticipantAdvancedPayload{TournamentID: tournamentID, SourceMatchID: updatedMatch.ID, AdvancingParticipantDBID: *updatedMatch.WinnerParticipantID, NextMatchID: *updatedMatch.NextMatchDBID, NextMatchSlot: *updatedMatch.WinnerToSlot}
+				s.hub.Publish(topic, brackets.EventParticipantAdvanced, advPayload)
 				s.logger.InfoContext(ctx, "Sent PARTICIPANT_ADVANCED", slog.Int("match_id", updatedMatch.ID))
 				if nextMatchToNotify != nil && (nextMatchToNotify.T1ParticipantID != nil && nextMatchToNotify.T2ParticipantID != nil) && nextMatchToNotify.Status == models.StatusScheduled {
-					s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "MATCH_UPDATED", Payload: nextMatchToNotify, RoomID: roomID})
+					s.hub.Publish(topic, brackets.EventMatchUpdated, nextMatchToNotify)
+					s.hub.Publish(brackets.MatchTopic(models.RatingMatchTeam, nextMatchToNotify.ID), brackets.EventMatchUpdated, nextMatchToNotify)
 					s.logger.InfoContext(ctx, "Sent MATCH_UPDATED for next match", slog.Int("next_match_id", nextMatchToNotify.ID))
 				}
 			}
 		}
 		if isFinalMatchForBranch && updatedMatch.WinnerParticipantID != nil && tournament.Format.BracketType == "SingleElimination" {
-			finalMatchPayload := map[string]interface{}{"match_id": updatedMatch.ID, "tournament_id": tournamentID, "winner_participant_id": *updatedMatch.WinnerParticipantID, "is_tournament_final_match": true}
-			s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "TOURNAMENT_FINAL_MATCH_COMPLETED", Payload: finalMatchPayload, RoomID: roomID})
+			finalMatchPayload := brackets.FinalMatchCompletedPayload{TournamentID: tournamentID, MatchID: updatedMatch.ID, WinnerParticipantID: *updatedMatch.WinnerParticipantID, IsTournamentFinalMatch: true}
+			s.hub.Publish(topic, brackets.EventTournamentFinalMatchCompleted, finalMatchPayload)
 			s.logger.InfoContext(ctx, "Sent TOURNAMENT_FINAL_MATCH_COMPLETED (candidate)", slog.Int("match_id", updatedMatch.ID))
 		}
 	}
+
+	if updatedMatch != nil {
+		s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchResult, Tournament: tournament, MatchType: models.RatingMatchTeam, MatchID: updatedMatch.ID, Score: derefString(updatedMatch.Score)},
+			updatedMatch.T1ParticipantID, updatedMatch.T2ParticipantID)
+		s.emitWebhook(ctx, models.WebhookMatchCompleted, tournament, webhookMatchData{MatchType: models.RatingMatchTeam, Match: updatedMatch})
+	}
+	if nextMatchToNotify != nil && nextMatchToNotify.T1ParticipantID != nil && nextMatchToNotify.T2ParticipantID != nil && nextMatchToNotify.Status == models.StatusScheduled {
+		s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchScheduled, Tournament: tournament, MatchType: models.RatingMatchTeam, MatchID: nextMatchToNotify.ID, MatchTime: nextMatchToNotify.MatchTime},
+			nextMatchToNotify.T1ParticipantID, nextMatchToNotify.T2ParticipantID)
+	}
 	return updatedMatch, nil
 }
+
+// notifyMatchParticipants уведомляет участников матча; пустые слоты пропускаются.
+func (s *matchService) notifyMatchParticipants(ctx context.Context, event NotificationEvent, participantIDs ...*int) {
+	if s.notifier == nil {
+		return
+	}
+	ids := make([]int, 0, len(participantIDs))
+	for _, id := range participantIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	s.notifier.NotifyParticipants(ctx, event, ids...)
+}
+
+// loadScorableTournament проверяет, что турнир активен и пользователь может вести счёт его матчей.
+func (s *matchService) loadScorableTournament(ctx context.Context, tournamentID int, currentUserID int) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	canManage, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, ErrMatchUpdateForbidden
+	}
+	if tournament.Status == models.StatusCompleted || tournament.Status == models.StatusCanceled {
+		return nil, fmt.Errorf("%w: tournament status is '%s'", ErrTournamentFinalized, tournament.Status)
+	}
+	if tournament.Status != models.StatusActive {
+		return nil, fmt.Errorf("%w: tournament status is '%s'", ErrTournamentInvalidStatus, tournament.Status)
+	}
+	return tournament, nil
+}
+
+// checkLiveTransition проверяет матч перед стартом (wantStatus = scheduled) или обновлением счёта (in_progress).
+func checkLiveTransition(matchTournamentID, tournamentID int, first, second *int, status, wantStatus models.MatchStatus, notFoundErr error) error {
+	if matchTournamentID != tournamentID {
+		return fmt.Errorf("%w: match does not belong to tournament %d", notFoundErr, tournamentID)
+	}
+	if status == models.MatchStatusCompleted || status == models.MatchStatusCanceled {
+		return ErrMatchAlreadyCompleted
+	}
+	if first == nil || second == nil {
+		return ErrMatchNotReady
+	}
+	if status != wantStatus {
+		if wantStatus == models.StatusScheduled {
+			return ErrMatchAlreadyStarted
+		}
+		return ErrMatchNotStarted
+	}
+	return nil
+}
+
+func (s *matchService) StartSoloMatch(ctx context.Context, matchID int, tournamentID int, currentUserID int) (*models.SoloMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	match, err := s.soloMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrSoloMatchNotFound, "failed to get solo match %d", matchID)
+	}
+	if err := checkLiveTransition(match.TournamentID, tournamentID, match.P1ParticipantID, match.P2ParticipantID, match.Status, models.StatusScheduled, ErrSoloMatchNotFound); err != nil {
+		return nil, err
+	}
+
+	score := liveScoreStart
+	if err := s.soloMatchRepo.UpdateLiveScore(ctx, nil, matchID, &score, models.StatusInProgress, models.StatusScheduled); err != nil {
+		if errors.Is(err, repositories.ErrSoloMatchStatusConflict) {
+			return nil, ErrMatchAlreadyStarted
+		}
+		return nil, fmt.Errorf("failed to start solo match %d: %w", matchID, err)
+	}
+	match.Score, match.Status = &score, models.StatusInProgress
+
+	s.logger.InfoContext(ctx, "Solo match started", slog.Int("match_id", matchID), slog.Int("tournament_id", tournamentID))
+	s.publishLiveScore(ctx, brackets.EventMatchStarted, models.RatingMatchSolo, tournament, matchID, match.Status, match.Score)
+	return match, nil
+}
+
+func (s *matchService) StartTeamMatch(ctx context.Context, matchID int, tournamentID int, currentUserID int) (*models.TeamMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	match, err := s.teamMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTeamMatchNotFound, "failed to get team match %d", matchID)
+	}
+	if err := checkLiveTransition(match.TournamentID, tournamentID, match.T1ParticipantID, match.T2ParticipantID, match.Status, models.StatusScheduled, ErrTeamMatchNotFound); err != nil {
+		return nil, err
+	}
+
+	score := liveScoreStart
+	if err := s.teamMatchRepo.UpdateLiveScore(ctx, nil, matchID, &score, models.StatusInProgress, models.StatusScheduled); err != nil {
+		if errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+			return nil, ErrMatchAlreadyStarted
+		}
+		return nil, fmt.Errorf("failed to start team match %d: %w", matchID, err)
+	}
+	match.Score, match.Status = &score, models.StatusInProgress
+
+	s.logger.InfoContext(ctx, "Team match started", slog.Int("match_id", matchID), slog.Int("tournament_id", tournamentID))
+	s.publishLiveScore(ctx, brackets.EventMatchStarted, models.RatingMatchTeam, tournament, matchID, match.Status, match.Score)
+	return match, nil
+}
+
+func (s *matchService) UpdateSoloLiveScore(ctx context.Context, matchID int, tournamentID int, input LiveScoreInput, currentUserID int) (*models.SoloMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateLiveScore(ctx, tournament, input.Score); err != nil {
+		return nil, err
+	}
+	match, err := s.soloMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrSoloMatchNotFound, "failed to get solo match %d", matchID)
+	}
+	if err := checkLiveTransition(match.TournamentID, tournamentID, match.P1ParticipantID, match.P2ParticipantID, match.Status, models.StatusInProgress, ErrSoloMatchNotFound); err != nil {
+		return nil, err
+	}
+
+	// Условие по статусу защищает от гонки с фиксацией результата
+	if err := s.soloMatchRepo.UpdateLiveScore(ctx, nil, matchID, &input.Score, models.StatusInProgress, models.StatusInProgress); err != nil {
+		if errors.Is(err, repositories.ErrSoloMatchStatusConflict) {
+			return nil, ErrMatchNotStarted
+		}
+		return nil, fmt.Errorf("failed to update live score of solo match %d: %w", matchID, err)
+	}
+	match.Score = &input.Score
+
+	s.publishLiveScore(ctx, brackets.EventMatchScoreUpdated, models.RatingMatchSolo, tournament, matchID, match.Status, match.Score)
+	return match, nil
+}
+
+func (s *matchService) UpdateTeamLiveScore(ctx context.Context, matchID int, tournamentID int, input LiveScoreInput, currentUserID int) (*models.TeamMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateLiveScore(ctx, tournament, input.Score); err != nil {
+		return nil, err
+	}
+	match, err := s.teamMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTeamMatchNotFound, "failed to get team match %d", matchID)
+	}
+	if err := checkLiveTransition(match.TournamentID, tournamentID, match.T1ParticipantID, match.T2ParticipantID, match.Status, models.StatusInProgress, ErrTeamMatchNotFound); err != nil {
+		return nil, err
+	}
+
+	if err := s.teamMatchRepo.UpdateLiveScore(ctx, nil, matchID, &input.Score, models.StatusInProgress, models.StatusInProgress); err != nil {
+		if errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+			return nil, ErrMatchNotStarted
+		}
+		return nil, fmt.Errorf("failed to update live score of team match %d: %w", matchID, err)
+	}
+	match.Score = &input.Score
+
+	s.publishLiveScore(ctx, brackets.EventMatchScoreUpdated, models.RatingMatchTeam, tournament, matchID, match.Status, match.Score)
+	return match, nil
+}
+
+// RescheduleSoloMatch переносит запланированный одиночный матч на новое время
+// и уведомляет участников, если они уже известны.
+func (s *matchService) RescheduleSoloMatch(ctx context.Context, matchID int, tournamentID int, input RescheduleMatchInput, currentUserID int) (*models.SoloMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if input.MatchTime.IsZero() || input.MatchTime.Before(time.Now()) {
+		return nil, ErrInvalidMatchTime
+	}
+	match, err := s.soloMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrSoloMatchNotFound, "failed to get solo match %d", matchID)
+	}
+	if err := checkReschedule(match.TournamentID, tournamentID, match.Status, ErrSoloMatchNotFound); err != nil {
+		return nil, err
+	}
+	if match.MatchTime.Equal(input.MatchTime) {
+		return match, nil
+	}
+
+	if err := s.soloMatchRepo.UpdateMatchTime(ctx, nil, matchID, input.MatchTime); err != nil {
+		if errors.Is(err, repositories.ErrSoloMatchStatusConflict) {
+			return nil, ErrMatchAlreadyStarted
+		}
+		return nil, fmt.Errorf("failed to reschedule solo match %d: %w", matchID, err)
+	}
+	match.MatchTime = input.MatchTime
+
+	s.logger.InfoContext(ctx, "Solo match rescheduled", slog.Int("match_id", matchID), slog.Time("match_time", match.MatchTime))
+	if s.hub != nil {
+		s.hub.Publish(brackets.TournamentTopic(tournamentID), brackets.EventMatchUpdated, match)
+		s.hub.Publish(brackets.MatchTopic(models.RatingMatchSolo, matchID), brackets.EventMatchUpdated, match)
+	}
+	s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchRescheduled, Tournament: tournament, MatchType: models.RatingMatchSolo, MatchID: matchID, MatchTime: match.MatchTime},
+		match.P1ParticipantID, match.P2ParticipantID)
+	s.emitWebhook(ctx, models.WebhookMatchRescheduled, tournament, webhookMatchData{MatchType: models.RatingMatchSolo, Match: match})
+	return match, nil
+}
+
+// RescheduleTeamMatch — то же для командного матча.
+func (s *matchService) RescheduleTeamMatch(ctx context.Context, matchID int, tournamentID int, input RescheduleMatchInput, currentUserID int) (*models.TeamMatch, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if input.MatchTime.IsZero() || input.MatchTime.Before(time.Now()) {
+		return nil, ErrInvalidMatchTime
+	}
+	match, err := s.teamMatchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTeamMatchNotFound, "failed to get team match %d", matchID)
+	}
+	if err := checkReschedule(match.TournamentID, tournamentID, match.Status, ErrTeamMatchNotFound); err != nil {
+		return nil, err
+	}
+	if match.MatchTime.Equal(input.MatchTime) {
+		return match, nil
+	}
+
+	if err := s.teamMatchRepo.UpdateMatchTime(ctx, nil, matchID, input.MatchTime); err != nil {
+		if errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+			return nil, ErrMatchAlreadyStarted
+		}
+		return nil, fmt.Errorf("failed to reschedule team match %d: %w", matchID, err)
+	}
+	match.MatchTime = input.MatchTime
+
+	s.logger.InfoContext(ctx, "Team match rescheduled", slog.Int("match_id", matchID), slog.Time("match_time", match.MatchTime))
+	if s.hub != nil {
+		s.hub.Publish(brackets.TournamentTopic(tournamentID), brackets.EventMatchUpdated, match)
+		s.hub.Publish(brackets.MatchTopic(models.RatingMatchTeam, matchID), brackets.EventMatchUpdated, match)
+	}
+	s.notifyMatchParticipants(ctx, NotificationEvent{Type: models.NotificationMatchRescheduled, Tournament: tournament, MatchType: models.RatingMatchTeam, MatchID: matchID, MatchTime: match.MatchTime},
+		match.T1ParticipantID, match.T2ParticipantID)
+	s.emitWebhook(ctx, models.WebhookMatchRescheduled, tournament, webhookMatchData{MatchType: models.RatingMatchTeam, Match: match})
+	return match, nil
+}
+
+// checkReschedule — перенести можно только запланированный матч; участники могут быть ещё не известны.
+func checkReschedule(matchTournamentID, tournamentID int, status models.MatchStatus, notFoundErr error) error {
+	if matchTournamentID != tournamentID {
+		return fmt.Errorf("%w: match does not belong to tournament %d", notFoundErr, tournamentID)
+	}
+	if status == models.MatchStatusCompleted || status == models.MatchStatusCanceled {
+		return ErrMatchAlreadyCompleted
+	}
+	if status != models.StatusScheduled {
+		return ErrMatchAlreadyStarted
+	}
+	return nil
+}
+
+// validateLiveScore проверяет промежуточный счёт по правилам спорта.
+// Если счёт считается по голам из хронологии, ручной ввод запрещён.
+func (s *matchService) validateLiveScore(ctx context.Context, tournament *models.Tournament, score string) error {
+	sport, err := s.getTournamentSport(ctx, tournament)
+	if err != nil {
+		return err
+	}
+	if sport.ScoreFromGoals {
+		return fmt.Errorf("%w: add or remove goal events instead", ErrMatchScoreFromEvents)
+	}
+	if _, err := scoring.Parse(sport.ScoringRules, score, false); err != nil {
+		return fmt.Errorf("%w: %w", ErrScoreParsingFailed, err)
+	}
+	return nil
+}
+
+// publishLiveScore рассылает счёт в топик матча (табло), в комнату турнира и подписчикам вебхуков.
+func (s *matchService) publishLiveScore(ctx context.Context, event string, matchType models.RatingMatchType, tournament *models.Tournament, matchID int, status models.MatchStatus, score *string) {
+	payload := brackets.LiveScorePayload{
+		TournamentID: tournament.ID,
+		MatchID:      matchID,
+		MatchType:    matchType,
+		Status:       status,
+		Score:        score,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if score != nil {
+		if s1, s2, ok := scoring.Summarize(*score); ok {
+			payload.Score1, payload.Score2 = &s1, &s2
+		}
+	}
+	if s.hub != nil {
+		s.hub.Publish(brackets.MatchTopic(matchType, matchID), event, payload)
+		s.hub.Publish(brackets.TournamentTopic(tournament.ID), event, payload)
+	}
+
+	webhookEvent := models.WebhookMatchScoreUpdated
+	if event == brackets.EventMatchStarted {
+		webhookEvent = models.WebhookMatchStarted
+	}
+	s.emitWebhook(ctx, webhookEvent, tournament, payload)
+}
+
+// webhookMatchData — данные событий match.* для вебхуков.
+type webhookMatchData struct {
+	MatchType models.RatingMatchType `json:"match_type"`
+	Match     interface{}            `json:"match"`
+}
+
+func (s *matchService) emitWebhook(ctx context.Context, event models.WebhookEvent, tournament *models.Tournament, data interface{}) {
+	if s.webhooks == nil {
+		return
+	}
+	s.webhooks.Emit(ctx, event, tournament, data)
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Dosada05/tournament-system/brackets"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/scoring"
+)
+
+var (
+	ErrMatchEventNotFound       = repositories.ErrMatchEventNotFound
+	ErrMatchEventPlayerInvalid  = repositories.ErrMatchEventPlayerInvalid
+	ErrMatchEventTypeNotAllowed = errors.New("match event type is not allowed for this sport")
+	ErrMatchEventInvalid        = errors.New("invalid match event")
+	ErrMatchScoreFromEvents     = errors.New("score of this match is derived from goal events")
+	ErrMatchScoreMismatch       = errors.New("score does not match the goal events of the match")
+)
+
+const maxMatchEventNoteLength = 500
+
+// MatchEventInput — событие хронологии. ParticipantID — сторона матча (для гола — кому он засчитан).
+type MatchEventInput struct {
+	Type          models.MatchEventType `json:"type"`
+	ParticipantID int                   `json:"participant_id"`
+	PlayerUserID  *int                  `json:"player_user_id,omitempty"`
+	RelatedUserID *int                  `json:"related_user_id,omitempty"`
+	Minute        *int                  `json:"minute,omitempty"`
+	Period        *int                  `json:"period,omitempty"`
+	Card          *models.CardColor     `json:"card,omitempty"`
+	Note          *string               `json:"note,omitempty"`
+}
+
+// matchSides — то общее, что нужно хронологии от solo- и team-матча.
+type matchSides struct {
+	TournamentID int
+	First        *int
+	Second       *int
+	Status       models.MatchStatus
+	NotFoundErr  error
+}
+
+func (s *matchService) loadMatchSides(ctx context.Context, matchType models.RatingMatchType, matchID int) (*matchSides, error) {
+	switch matchType {
+	case models.RatingMatchSolo:
+		match, err := s.soloMatchRepo.GetByID(ctx, matchID)
+		if err != nil {
+			return nil, handleRepositoryError(err, ErrSoloMatchNotFound, "failed to get solo match %d", matchID)
+		}
+		return &matchSides{match.TournamentID, match.P1ParticipantID, match.P2ParticipantID, match.Status, ErrSoloMatchNotFound}, nil
+	case models.RatingMatchTeam:
+		match, err := s.teamMatchRepo.GetByID(ctx, matchID)
+		if err != nil {
+			return nil, handleRepositoryError(err, ErrTeamMatchNotFound, "failed to get team match %d", matchID)
+		}
+		return &matchSides{match.TournamentID, match.T1ParticipantID, match.T2ParticipantID, match.Status, ErrTeamMatchNotFound}, nil
+	}
+	return nil, fmt.Errorf("unknown match type '%s'", matchType)
+}
+
+func (s *matchService) updateLiveScore(ctx context.Context, exec repositories.SQLExecutor, matchType models.RatingMatchType, matchID int, score string) error {
+	var err error
+	if matchType == models.RatingMatchSolo {
+		err = s.soloMatchRepo.UpdateLiveScore(ctx, exec, matchID, &score, models.StatusInProgress, models.StatusInProgress)
+	} else {
+		err = s.teamMatchRepo.UpdateLiveScore(ctx, exec, matchID, &score, models.StatusInProgress, models.StatusInProgress)
+	}
+	if errors.Is(err, repositories.ErrSoloMatchStatusConflict) || errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+		return ErrMatchNotStarted
+	}
+	return err
+}
+
+// getTournamentSport — вид спорта турнира с правилами счёта и настройками хронологии.
+func (s *matchService) getTournamentSport(ctx context.Context, tournament *models.Tournament) (*models.Sport, error) {
+	sport, err := s.sportRepo.GetByID(ctx, tournament.SportID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrSportNotFound, "failed to get sport %d of tournament %d", tournament.SportID, tournament.ID)
+	}
+	return sport, nil
+}
+
+// goalsScore — счёт "S1-S2" по числу голов каждой стороны.
+func (s *matchService) goalsScore(ctx context.Context, exec repositories.SQLExecutor, matchType models.RatingMatchType, matchID int, first, second int) (string, error) {
+	goals, err := s.matchEventRepo.CountGoals(ctx, exec, matchType, matchID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", goals[first], goals[second]), nil
+}
+
+func (s *matchService) ListMatchEvents(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int) ([]models.MatchEvent, error) {
+	sides, err := s.loadMatchSides(ctx, matchType, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if sides.TournamentID != tournamentID {
+		return nil, fmt.Errorf("%w: match does not belong to tournament %d", sides.NotFoundErr, tournamentID)
+	}
+	events, err := s.matchEventRepo.ListByMatch(ctx, matchType, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events of %s match %d: %w", matchType, matchID, err)
+	}
+	return events, nil
+}
+
+func (s *matchService) ListTournamentMatchEvents(ctx context.Context, tournamentID int, matchType models.RatingMatchType) (map[int][]models.MatchEvent, error) {
+	events, err := s.matchEventRepo.ListByTournament(ctx, tournamentID, matchType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list match events of tournament %d: %w", tournamentID, err)
+	}
+	byMatch := make(map[int][]models.MatchEvent)
+	for _, ev := range events {
+		byMatch[ev.MatchID] = append(byMatch[ev.MatchID], ev)
+	}
+	return byMatch, nil
+}
+
+func (s *matchService) AddMatchEvent(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int, input MatchEventInput, currentUserID int) (*models.MatchEvent, error) {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	sport, err := s.getTournamentSport(ctx, tournament)
+	if err != nil {
+		return nil, err
+	}
+	sides, err := s.loadMatchSides(ctx, matchType, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLiveTransition(sides.TournamentID, tournamentID, sides.First, sides.Second, sides.Status, models.StatusInProgress, sides.NotFoundErr); err != nil {
+		return nil, err
+	}
+
+	event, err := buildMatchEvent(input, sport, sides)
+	if err != nil {
+		return nil, err
+	}
+	event.TournamentID, event.MatchType, event.MatchID, event.CreatedBy = tournamentID, matchType, matchID, &currentUserID
+
+	var newScore *string
+	opErr := s.withTransaction(ctx, func(tx repositories.SQLExecutor) error {
+		if err := s.matchEventRepo.Create(ctx, tx, event); err != nil {
+			return err
+		}
+		if !sport.ScoreFromGoals || event.Type != models.MatchEventGoal {
+			return nil
+		}
+		score, err := s.goalsScore(ctx, tx, matchType, matchID, *sides.First, *sides.Second)
+		if err != nil {
+			return err
+		}
+		newScore = &score
+		return s.updateLiveScore(ctx, tx, matchType, matchID, score)
+	})
+	if opErr != nil {
+		if errors.Is(opErr, ErrMatchEventPlayerInvalid) || errors.Is(opErr, ErrMatchNotStarted) {
+			return nil, opErr
+		}
+		return nil, fmt.Errorf("failed to add event to %s match %d: %w", matchType, matchID, opErr)
+	}
+
+	s.logger.InfoContext(ctx, "Match event added", slog.String("match_type", string(matchType)), slog.Int("match_id", matchID), slog.String("event_type", string(event.Type)))
+	s.publishMatchEvent(brackets.EventMatchEventAdded, event.ID, event, matchType, tournamentID, matchID)
+	if newScore != nil {
+		s.publishLiveScore(ctx, brackets.EventMatchScoreUpdated, matchType, tournament, matchID, models.StatusInProgress, newScore)
+	}
+	return event, nil
+}
+
+func (s *matchService) DeleteMatchEvent(ctx context.Context, tournamentID int, matchType models.RatingMatchType, matchID int, eventID int, currentUserID int) error {
+	tournament, err := s.loadScorableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return err
+	}
+	sport, err := s.getTournamentSport(ctx, tournament)
+	if err != nil {
+		return err
+	}
+	sides, err := s.loadMatchSides(ctx, matchType, matchID)
+	if err != nil {
+		return err
+	}
+	if err := checkLiveTransition(sides.TournamentID, tournamentID, sides.First, sides.Second, sides.Status, models.StatusInProgress, sides.NotFoundErr); err != nil {
+		return err
+	}
+	event, err := s.matchEventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return handleRepositoryError(err, ErrMatchEventNotFound, "failed to get match event %d", eventID)
+	}
+	if event.MatchType != matchType || event.MatchID != matchID {
+		return fmt.Errorf("%w: event does not belong to %s match %d", ErrMatchEventNotFound, matchType, matchID)
+	}
+
+	var newScore *string
+	opErr := s.withTransaction(ctx, func(tx repositories.SQLExecutor) error {
+		if err := s.matchEventRepo.Delete(ctx, tx, eventID); err != nil {
+			return err
+		}
+		if !sport.ScoreFromGoals || event.Type != models.MatchEventGoal {
+			return nil
+		}
+		score, err := s.goalsScore(ctx, tx, matchType, matchID, *sides.First, *sides.Second)
+		if err != nil {
+			return err
+		}
+		newScore = &score
+		return s.updateLiveScore(ctx, tx, matchType, matchID, score)
+	})
+	if opErr != nil {
+		if errors.Is(opErr, ErrMatchEventNotFound) || errors.Is(opErr, ErrMatchNotStarted) {
+			return opErr
+		}
+		return fmt.Errorf("failed to delete event %d of %s match %d: %w", eventID, matchType, matchID, opErr)
+	}
+
+	s.publishMatchEvent(brackets.EventMatchEventDeleted, eventID, nil, matchType, tournamentID, matchID)
+	if newScore != nil {
+		s.publishLiveScore(ctx, brackets.EventMatchScoreUpdated, matchType, tournament, matchID, models.StatusInProgress, newScore)
+	}
+	return nil
+}
+
+// buildMatchEvent проверяет событие по правилам спорта и типа события.
+func buildMatchEvent(input MatchEventInput, sport *models.Sport, sides *matchSides) (*models.MatchEvent, error) {
+	if !input.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown type '%s'", ErrMatchEventInvalid, input.Type)
+	}
+	if !sport.AllowsMatchEvent(input.Type) {
+		return nil, fmt.Errorf("%w: '%s' in %s", ErrMatchEventTypeNotAllowed, input.Type, sport.Name)
+	}
+	if input.ParticipantID != *sides.First && input.ParticipantID != *sides.Second {
+		return nil, fmt.Errorf("%w: participant %d does not play in this match", ErrMatchEventInvalid, input.ParticipantID)
+	}
+	if input.Minute != nil && *input.Minute < 0 {
+		return nil, fmt.Errorf("%w: minute cannot be negative", ErrMatchEventInvalid)
+	}
+	if input.Period != nil && *input.Period <= 0 {
+		return nil, fmt.Errorf("%w: period must be positive", ErrMatchEventInvalid)
+	}
+	if input.Card != nil && input.Type != models.MatchEventCard {
+		return nil, fmt.Errorf("%w: card is only allowed for card events", ErrMatchEventInvalid)
+	}
+	if input.RelatedUserID != nil && input.Type != models.MatchEventGoal && input.Type != models.MatchEventSubstitution {
+		return nil, fmt.Errorf("%w: related player is only allowed for goal and substitution events", ErrMatchEventInvalid)
+	}
+
+	switch input.Type {
+	case models.MatchEventCard:
+		if input.PlayerUserID == nil {
+			return nil, fmt.Errorf("%w: card requires a player", ErrMatchEventInvalid)
+		}
+		if input.Card == nil || (*input.Card != models.CardYellow && *input.Card != models.CardRed) {
+			return nil, fmt.Errorf("%w: card must be 'yellow' or 'red'", ErrMatchEventInvalid)
+		}
+	case models.MatchEventSubstitution:
+		if input.PlayerUserID == nil || input.RelatedUserID == nil {
+			return nil, fmt.Errorf("%w: substitution requires both outgoing and incoming players", ErrMatchEventInvalid)
+		}
+		if *input.PlayerUserID == *input.RelatedUserID {
+			return nil, fmt.Errorf("%w: substitution players must differ", ErrMatchEventInvalid)
+		}
+	case models.MatchEventRoundWon:
+		if input.Period == nil {
+			return nil, fmt.Errorf("%w: round_won requires the round number in period", ErrMatchEventInvalid)
+		}
+	}
+
+	var note *string
+	if input.Note != nil {
+		trimmed := strings.TrimSpace(*input.Note)
+		if len(trimmed) > maxMatchEventNoteLength {
+			return nil, fmt.Errorf("%w: note is longer than %d characters", ErrMatchEventInvalid, maxMatchEventNoteLength)
+		}
+		if trimmed != "" {
+			note = &trimmed
+		}
+	}
+
+	return &models.MatchEvent{
+		Type:          input.Type,
+		ParticipantID: input.ParticipantID,
+		PlayerUserID:  input.PlayerUserID,
+		RelatedUserID: input.RelatedUserID,
+		Minute:        input.Minute,
+		Period:        input.Period,
+		Card:          input.Card,
+		Note:          note,
+	}, nil
+}
+
+// resolveScoreFromGoals подставляет счёт по голам, если спорт так настроен.
+// Переданный вручную счёт должен совпадать с голами. Вызывается в транзакции результата.
+func (s *matchService) resolveScoreFromGoals(ctx context.Context, tx repositories.SQLExecutor, sport *models.Sport, matchType models.RatingMatchType, matchID int, first, second int, input *UpdateMatchResultInput) error {
+	if !sport.ScoreFromGoals {
+		return nil
+	}
+	goals, err := s.matchEventRepo.CountGoals(ctx, tx, matchType, matchID)
+	if err != nil {
+		return fmt.Errorf("failed to derive score of %s match %d: %w", matchType, matchID, err)
+	}
+	score := fmt.Sprintf("%d-%d", goals[first], goals[second])
+	if input.Score != nil && strings.TrimSpace(*input.Score) != "" {
+		manual, parseErr := scoring.Parse(sport.ScoringRules, *input.Score, false)
+		if parseErr != nil {
+			return fmt.Errorf("%w: %w", ErrScoreParsingFailed, parseErr)
+		}
+		if manual.Score1 != goals[first] || manual.Score2 != goals[second] {
+			return fmt.Errorf("%w: goals give %s, got %s", ErrMatchScoreMismatch, score, *input.Score)
+		}
+	}
+	input.Score = &score
+	return nil
+}
+
+// publishMatchEvent рассылает изменение хронологии в топик матча и в комнату турнира.
+func (s *matchService) publishMatchEvent(event string, eventID int, matchEvent *models.MatchEvent, matchType models.RatingMatchType, tournamentID, matchID int) {
+	if s.hub == nil {
+		return
+	}
+	payload := brackets.MatchEventPayload{
+		TournamentID: tournamentID,
+		MatchID:      matchID,
+		MatchType:    matchType,
+		EventID:      eventID,
+		Event:        matchEvent,
+	}
+	s.hub.Publish(brackets.MatchTopic(matchType, matchID), event, payload)
+	s.hub.Publish(brackets.TournamentTopic(tournamentID), event, payload)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+type notificationText struct {
+	Title string
+	Body  string // Формат для fmt.Sprintf
+}
+
+// Тексты уведомлений; аргументы Body собирает notificationService.render.
+var notificationTexts = map[string]map[models.NotificationType]notificationText{
+	models.LocaleRussian: {
+		models.NotificationApplicationApproved: {"Заявка принята", "Ваша заявка на участие в турнире «%s» одобрена."},
+		models.NotificationApplicationRejected: {"Заявка отклонена", "Заявка на участие в турнире «%s» отклонена организатором."},
+		models.NotificationMatchScheduled:      {"Назначен матч", "Матч турнира «%s» назначен на %s."},
+		models.NotificationMatchRescheduled:    {"Матч перенесён", "Матч турнира «%s» перенесён на %s."},
+		models.NotificationMatchReminder:       {"Скоро матч", "Матч турнира «%s» начнётся %s."},
+		models.NotificationMatchResult:         {"Результат матча", "Матч турнира «%s» завершён со счётом %s."},
+		models.NotificationTeamInvite:          {"Приглашение в команду", "Вас пригласили в команду %s."},
+		models.NotificationTournamentStatus:    {"Статус турнира изменён", "Турнир «%s»: %s."},
+	},
+	models.LocaleEnglish: {
+		models.NotificationApplicationApproved: {"Application approved", "Your application to the tournament '%s' has been approved."},
+		models.NotificationApplicationRejected: {"Application rejected", "Your application to the tournament '%s' was rejected by the organizer."},
+		models.NotificationMatchScheduled:      {"Match scheduled", "Your match in the tournament '%s' is scheduled for %s."},
+		models.NotificationMatchRescheduled:    {"Match rescheduled", "Your match in the tournament '%s' has been moved to %s."},
+		models.NotificationMatchReminder:       {"Match starting soon", "Your match in the tournament '%s' starts %s."},
+		models.NotificationMatchResult:         {"Match result", "Your match in the tournament '%s' has finished with the score %s."},
+		models.NotificationTeamInvite:          {"Team invitation", "You have been invited to join the team %s."},
+		models.NotificationTournamentStatus:    {"Tournament status changed", "Tournament '%s': %s."},
+	},
+}
+
+func notificationMessage(locale string, notificationType models.NotificationType, args ...interface{}) (string, string) {
+	text, ok := notificationTexts[locale][notificationType]
+	if !ok {
+		text = notificationTexts[models.DefaultLocale][notificationType]
+	}
+	return text.Title, fmt.Sprintf(text.Body, args...)
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Dosada05/tournament-system/brackets"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+const (
+	defaultNotificationsLimit = 20
+	maxNotificationsLimit     = 100
+)
+
+var (
+	ErrNotificationNotFound    = repositories.ErrNotificationNotFound
+	ErrInvalidNotificationType = errors.New("invalid notification type")
+)
+
+// NotificationEvent описывает событие, о котором нужно уведомить пользователей.
+// Заполняются только поля, нужные для данного типа.
+type NotificationEvent struct {
+	Type        models.NotificationType
+	Tournament  *models.Tournament
+	MatchType   models.RatingMatchType
+	MatchID     int
+	MatchTime   time.Time
+	Score       string
+	TeamID      int
+	TeamName    string
+	InviteToken string
+	// InAppOnly — письмо вызывающий код отправляет сам (например, приглашение в команду).
+	InAppOnly bool
+}
+
+type NotificationService interface {
+	// Notify создаёт уведомления в приложении и ставит письма в очередь с учётом настроек
+	// каждого пользователя. Ошибки только логируются: уведомления не должны ломать операцию.
+	Notify(ctx context.Context, event NotificationEvent, userIDs ...int)
+	// NotifyParticipants уведомляет участников турнира: игрока соло-участника или всех членов команды.
+	NotifyParticipants(ctx context.Context, event NotificationEvent, participantIDs ...int)
+	// NotifyTournamentParticipants уведомляет всех одобренных участников event.Tournament.
+	NotifyTournamentParticipants(ctx context.Context, event NotificationEvent)
+	EmailEnabled(ctx context.Context, userID int, notificationType models.NotificationType) bool
+
+	List(ctx context.Context, userID int, filter models.NotificationFilter) (*models.NotificationList, error)
+	MarkRead(ctx context.Context, userID int, id int64) (*models.Notification, error)
+	MarkAllRead(ctx context.Context, userID int) (int64, error)
+	// GetPreferences возвращает настройки по всем типам уведомлений, включая значения по умолчанию.
+	GetPreferences(ctx context.Context, userID int) ([]models.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID int, prefs []models.NotificationPreference) ([]models.NotificationPreference, error)
+}
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
+	participantRepo  repositories.ParticipantRepository
+	emailService     *EmailService
+	outboxService    EmailOutboxService
+	hub              *brackets.Hub
+	publicURL        string
+	logger           *slog.Logger
+}
+
+func NewNotificationService(
+	notificationRepo repositories.NotificationRepository,
+	userRepo repositories.UserRepository,
+	participantRepo repositories.ParticipantRepository,
+	emailService *EmailService,
+	outboxService EmailOutboxService,
+	hub *brackets.Hub,
+	publicURL string,
+	logger *slog.Logger,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		participantRepo:  participantRepo,
+		emailService:     emailService,
+		outboxService:    outboxService,
+		hub:              hub,
+		publicURL:        publicURL,
+		logger:           logger,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, event NotificationEvent, userIDs ...int) {
+	seen := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == 0 || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Notify: failed to load user", slog.Int("user_id", userID), slog.Any("error", err))
+			continue
+		}
+		s.notifyUser(ctx, event, user)
+	}
+}
+
+func (s *notificationService) notifyUser(ctx context.Context, event NotificationEvent, user *models.User) {
+	pref := s.preference(ctx, user.ID, event.Type)
+	if !pref.InApp && (!pref.Email || event.InAppOnly) {
+		return
+	}
+
+	recipient := RecipientFromUser(user)
+	title, body := s.render(event, recipient)
+	link := s.link(event)
+
+	if pref.InApp {
+		notification := &models.Notification{
+			UserID: user.ID,
+			Type:   event.Type,
+			Title:  title,
+			Body:   body,
+			Data:   s.data(event, link),
+		}
+		if err := s.notificationRepo.Create(ctx, nil, notification); err != nil {
+			s.logger.ErrorContext(ctx, "Notify: failed to create notification", slog.Int("user_id", user.ID), slog.String("type", string(event.Type)), slog.Any("error", err))
+		} else if s.hub != nil {
+			s.hub.Publish(brackets.UserTopic(user.ID), brackets.EventNotificationCreated, notification)
+		}
+	}
+
+	if pref.Email && !event.InAppOnly && user.Email != "" {
+		var email *models.OutboxEmail
+		var err error
+		if event.Type == models.NotificationTournamentStatus && event.Tournament != nil {
+			email, err = s.emailService.BuildTournamentStatusEmail(recipient, event.Tournament, link)
+		} else {
+			email, err = s.emailService.BuildNotificationEmail(recipient, title, body, link)
+		}
+		if err == nil {
+			err = s.outboxService.Enqueue(ctx, nil, email)
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Notify: failed to enqueue notification email", slog.Int("user_id", user.ID), slog.String("type", string(event.Type)), slog.Any("error", err))
+		}
+	}
+}
+
+func (s *notificationService) NotifyParticipants(ctx context.Context, event NotificationEvent, participantIDs ...int) {
+	userIDs := make([]int, 0, len(participantIDs))
+	for _, participantID := range participantIDs {
+		participant, err := s.participantRepo.FindByID(ctx, participantID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "NotifyParticipants: failed to load participant", slog.Int("participant_id", participantID), slog.Any("error", err))
+			continue
+		}
+		userIDs = append(userIDs, s.participantUserIDs(ctx, participant)...)
+	}
+	s.Notify(ctx, event, userIDs...)
+}
+
+func (s *notificationService) NotifyTournamentParticipants(ctx context.Context, event NotificationEvent) {
+	if event.Tournament == nil {
+		return
+	}
+	status := models.StatusParticipant
+	participants, err := s.participantRepo.ListByTournament(ctx, event.Tournament.ID, &status, false)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "NotifyTournamentParticipants: failed to list participants", slog.Int("tournament_id", event.Tournament.ID), slog.Any("error", err))
+		return
+	}
+	userIDs := make([]int, 0, len(participants))
+	for _, participant := range participants {
+		userIDs = append(userIDs, s.participantUserIDs(ctx, participant)...)
+	}
+	s.Notify(ctx, event, userIDs...)
+}
+
+func (s *notificationService) participantUserIDs(ctx context.Context, participant *models.Participant) []int {
+	if participant.UserID != nil {
+		return []int{*participant.UserID}
+	}
+	if participant.TeamID == nil {
+		return nil
+	}
+	members, err := s.userRepo.ListByTeamID(ctx, *participant.TeamID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to list team members for notification", slog.Int("team_id", *participant.TeamID), slog.Any("error", err))
+		return nil
+	}
+	userIDs := make([]int, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.ID)
+	}
+	return userIDs
+}
+
+func (s *notificationService) EmailEnabled(ctx context.Context, userID int, notificationType models.NotificationType) bool {
+	return s.preference(ctx, userID, notificationType).Email
+}
+
+// preference возвращает настройку пользователя; при ошибке чтения действуют значения по умолчанию.
+func (s *notificationService) preference(ctx context.Context, userID int, notificationType models.NotificationType) models.NotificationPreference {
+	pref := models.NotificationPreference{Type: notificationType, Email: true, InApp: true}
+	saved, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to load notification preferences, using defaults", slog.Int("user_id", userID), slog.Any("error", err))
+		return pref
+	}
+	for _, p := range saved {
+		if p.Type == notificationType {
+			return p
+		}
+	}
+	return pref
+}
+
+func (s *notificationService) render(event NotificationEvent, recipient EmailRecipient) (string, string) {
+	locale := recipient.locale()
+	tournamentName := ""
+	if event.Tournament != nil {
+		tournamentName = event.Tournament.Name
+	}
+
+	switch event.Type {
+	case models.NotificationMatchScheduled, models.NotificationMatchRescheduled, models.NotificationMatchReminder:
+		return notificationMessage(locale, event.Type, tournamentName, formatEmailDateTime(event.MatchTime, recipient))
+	case models.NotificationMatchResult:
+		return notificationMessage(locale, event.Type, tournamentName, event.Score)
+	case models.NotificationTeamInvite:
+		return notificationMessage(locale, event.Type, event.TeamName)
+	case models.NotificationTournamentStatus:
+		status := ""
+		if event.Tournament != nil {
+			status = tournamentStatusLabel(locale, event.Tournament.Status)
+		}
+		return notificationMessage(locale, event.Type, tournamentName, status)
+	default:
+		return notificationMessage(locale, event.Type, tournamentName)
+	}
+}
+
+func (s *notificationService) link(event NotificationEvent) string {
+	switch {
+	case event.InviteToken != "":
+		return s.publicURL + "/invites/join/" + event.InviteToken
+	case event.Tournament != nil:
+		return fmt.Sprintf("%s/tournaments/%d", s.publicURL, event.Tournament.ID)
+	case event.TeamID != 0:
+		return fmt.Sprintf("%s/teams/%d", s.publicURL, event.TeamID)
+	}
+	return ""
+}
+
+func (s *notificationService) data(event NotificationEvent, link string) map[string]interface{} {
+	data := map[string]interface{}{}
+	if event.Tournament != nil {
+		data["tournament_id"] = event.Tournament.ID
+	}
+	if event.MatchID != 0 {
+		data["match_id"] = event.MatchID
+		data["match_type"] = event.MatchType
+	}
+	if event.TeamID != 0 {
+		data["team_id"] = event.TeamID
+	}
+	if link != "" {
+		data["link"] = link
+	}
+	return data
+}
+
+func (s *notificationService) List(ctx context.Context, userID int, filter models.NotificationFilter) (*models.NotificationList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNotificationsLimit
+	}
+	if filter.Limit > maxNotificationsLimit {
+		filter.Limit = maxNotificationsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	notifications, total, err := s.notificationRepo.ListByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationList{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	}, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID int, id int64) (*models.Notification, error) {
+	return s.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID int) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID int) ([]models.NotificationPreference, error) {
+	saved, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[models.NotificationType]models.NotificationPreference, len(saved))
+	for _, p := range saved {
+		byType[p.Type] = p
+	}
+
+	prefs := make([]models.NotificationPreference, 0, len(models.NotificationTypes()))
+	for _, t := range models.NotificationTypes() {
+		if p, ok := byType[t]; ok {
+			prefs = append(prefs, p)
+			continue
+		}
+		prefs = append(prefs, models.NotificationPreference{Type: t, Email: true, InApp: true})
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID int, prefs []models.NotificationPreference) ([]models.NotificationPreference, error) {
+	for _, p := range prefs {
+		if !p.Type.IsValid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNotificationType, p.Type)
+		}
+	}
+	if err := s.notificationRepo.UpsertPreferences(ctx, nil, userID, prefs); err != nil {
+		return nil, handleRepositoryError(err, ErrUserNotFound, "failed to update notification preferences for user %d", userID)
+	}
+	return s.GetPreferences(ctx, userID)
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var (
+	ErrOrganizationNameRequired   = errors.New("organization name is required")
+	ErrOrganizationNameConflict   = errors.New("organization name is already in use")
+	ErrOrganizationMemberNotFound = errors.New("organization member not found")
+	ErrOrganizationMemberConflict = errors.New("user is already a member of this organization")
+	ErrInvalidOrganizationRole    = errors.New("invalid organization role")
+	ErrCannotRemoveOrgOwner       = errors.New("organization owner cannot be removed or demoted")
+	ErrOrganizationCreationFailed = errors.New("failed to create organization")
+	ErrTeamNotInOrganization      = errors.New("team does not belong to this organization")
+)
+
+type CreateOrganizationInput struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type UpdateOrganizationInput struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type AddOrganizationMemberInput struct {
+	UserID int                     `json:"user_id"`
+	Role   models.OrganizationRole `json:"role"`
+}
+
+// OrganizationProfile — публичная страница организации: состав, команды, текущие турниры и история.
+type OrganizationProfile struct {
+	Organization        *models.Organization `json:"organization"`
+	Teams               []models.Team        `json:"teams"`
+	UpcomingTournaments []models.Tournament  `json:"upcoming_tournaments"`
+	PastTournaments     []models.Tournament  `json:"past_tournaments"`
+}
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, creatorID int, input CreateOrganizationInput) (*models.Organization, error)
+	GetOrganizationByID(ctx context.Context, id int) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error)
+	ListUserOrganizations(ctx context.Context, userID int) ([]models.Organization, error)
+	UpdateOrganization(ctx context.Context, id int, currentUserID int, input UpdateOrganizationInput) (*models.Organization, error)
+	DeleteOrganization(ctx context.Context, id int, currentUserID int) error
+	GetOrganizationProfile(ctx context.Context, id int) (*OrganizationProfile, error)
+	ListOrganizationTournaments(ctx context.Context, id int, status *models.TournamentStatus, limit, offset int) ([]models.Tournament, error)
+
+	AddMember(ctx context.Context, organizationID int, currentUserID int, input AddOrganizationMemberInput) (*models.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, organizationID int, currentUserID int, userID int, role models.OrganizationRole) error
+	RemoveMember(ctx context.Context, organizationID int, currentUserID int, userID int) error
+
+	AttachTeam(ctx context.Context, organizationID int, teamID int, currentUserID int) (*models.Team, error)
+	DetachTeam(ctx context.Context, organizationID int, teamID int, currentUserID int) error
+}
+
+type organizationService struct {
+	db             *sql.DB
+	orgRepo        repositories.OrganizationRepository
+	userRepo       repositories.UserRepository
+	teamRepo       repositories.TeamRepository
+	tournamentRepo repositories.TournamentRepository
+	uploader       storage.FileUploader
+}
+
+func NewOrganizationService(
+	db *sql.DB,
+	orgRepo repositories.OrganizationRepository,
+	userRepo repositories.UserRepository,
+	teamRepo repositories.TeamRepository,
+	tournamentRepo repositories.TournamentRepository,
+	uploader storage.FileUploader,
+) OrganizationService {
+	return &organizationService{
+		db:             db,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		tournamentRepo: tournamentRepo,
+		uploader:       uploader,
+	}
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, creatorID int, input CreateOrganizationInput) (*models.Organization, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, creatorID); err != nil {
+		return nil, handleRepositoryError(err, ErrUserNotFound, "failed to verify organization creator %d", creatorID)
+	}
+
+	org := &models.Organization{
+		Name:        name,
+		Description: input.Description,
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin transaction: %w", ErrOrganizationCreationFailed, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.orgRepo.Create(ctx, tx, org); err != nil {
+		return nil, s.mapOrganizationError(err, "%w", ErrOrganizationCreationFailed)
+	}
+
+	owner := &models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         creatorID,
+		Role:           models.OrgRoleOwner,
+	}
+	if err := s.orgRepo.AddMember(ctx, tx, owner); err != nil {
+		return nil, s.mapOrganizationError(err, "%w: failed to add owner", ErrOrganizationCreationFailed)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit: %w", ErrOrganizationCreationFailed, err)
+	}
+
+	org.Members = []models.OrganizationMember{*owner}
+	return org, nil
+}
+
+func (s *organizationService) GetOrganizationByID(ctx context.Context, id int) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, s.mapOrganizationError(err, "failed to get organization %d", id)
+	}
+
+	members, err := s.orgRepo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of organization %d: %w", id, err)
+	}
+	for i := range members {
+		populateUserDetailsFunc(members[i].User, s.uploader)
+	}
+	org.Members = members
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	orgs, err := s.orgRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+func (s *organizationService) ListUserOrganizations(ctx context.Context, userID int) ([]models.Organization, error) {
+	orgs, err := s.orgRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations of user %d: %w", userID, err)
+	}
+	return orgs, nil
+}
+
+func (s *organizationService) UpdateOrganization(ctx context.Context, id int, currentUserID int, input UpdateOrganizationInput) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, s.mapOrganizationError(err, "failed to get organization %d for update", id)
+	}
+	if _, err := s.requireRole(ctx, id, currentUserID, true); err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, ErrOrganizationNameRequired
+		}
+		org.Name = name
+	}
+	if input.Description != nil {
+		org.Description = input.Description
+	}
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, s.mapOrganizationError(err, "failed to update organization %d", id)
+	}
+	return org, nil
+}
+
+func (s *organizationService) DeleteOrganization(ctx context.Context, id int, currentUserID int) error {
+	member, err := s.requireRole(ctx, id, currentUserID, false)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.OrgRoleOwner {
+		return fmt.Errorf("%w: only the organization owner can delete it", ErrForbiddenOperation)
+	}
+	if err := s.orgRepo.Delete(ctx, id); err != nil {
+		return s.mapOrganizationError(err, "failed to delete organization %d", id)
+	}
+	return nil
+}
+
+func (s *organizationService) GetOrganizationProfile(ctx context.Context, id int) (*OrganizationProfile, error) {
+	org, err := s.GetOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := s.teamRepo.ListByOrganization(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams of organization %d: %w", id, err)
+	}
+	for i := range teams {
+		if teams[i].LogoKey != nil && *teams[i].LogoKey != "" && s.uploader != nil {
+			teams[i].LogoURL, teams[i].LogoURLs = logoURLsFunc(s.uploader, teams[i].LogoKey)
+		}
+	}
+
+	tournaments, err := s.tournamentRepo.List(ctx, repositories.ListTournamentsFilter{OrganizationID: &id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tournaments of organization %d: %w", id, err)
+	}
+
+	profile := &OrganizationProfile{
+		Organization:        org,
+		Teams:               teams,
+		UpcomingTournaments: make([]models.Tournament, 0),
+		PastTournaments:     make([]models.Tournament, 0),
+	}
+	for i := range tournaments {
+		t := tournaments[i]
+		populateTournamentLogoURLFunc(&t, s.uploader)
+		switch t.Status {
+		case models.StatusCompleted, models.StatusCanceled:
+			profile.PastTournaments = append(profile.PastTournaments, t)
+		default:
+			profile.UpcomingTournaments = append(profile.UpcomingTournaments, t)
+		}
+	}
+	return profile, nil
+}
+
+func (s *organizationService) ListOrganizationTournaments(ctx context.Context, id int, status *models.TournamentStatus, limit, offset int) ([]models.Tournament, error) {
+	if _, err := s.orgRepo.GetByID(ctx, id); err != nil {
+		return nil, s.mapOrganizationError(err, "failed to get organization %d", id)
+	}
+
+	tournaments, err := s.tournamentRepo.List(ctx, repositories.ListTournamentsFilter{
+		OrganizationID: &id,
+		Status:         status,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTournamentListFailed, err)
+	}
+	for i := range tournaments {
+		populateTournamentLogoURLFunc(&tournaments[i], s.uploader)
+	}
+	return tournaments, nil
+}
+
+func (s *organizationService) AddMember(ctx context.Context, organizationID int, currentUserID int, input AddOrganizationMemberInput) (*models.OrganizationMember, error) {
+	actor, err := s.requireRole(ctx, organizationID, currentUserID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Role == "" {
+		input.Role = models.OrgRoleMember
+	}
+	if input.Role != models.OrgRoleAdmin && input.Role != models.OrgRoleMember {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidOrganizationRole, input.Role)
+	}
+	if input.Role == models.OrgRoleAdmin && actor.Role != models.OrgRoleOwner {
+		return nil, fmt.Errorf("%w: only the owner can grant the admin role", ErrForbiddenOperation)
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, handleRepositoryError(err, ErrUserNotFound, "failed to get user %d", input.UserID)
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         input.UserID,
+		Role:           input.Role,
+	}
+	if err := s.orgRepo.AddMember(ctx, nil, member); err != nil {
+		return nil, s.mapOrganizationError(err, "failed to add member %d to organization %d", input.UserID, organizationID)
+	}
+	return member, nil
+}
+
+func (s *organizationService) UpdateMemberRole(ctx context.Context, organizationID int, currentUserID int, userID int, role models.OrganizationRole) error {
+	actor, err := s.requireRole(ctx, organizationID, currentUserID, true)
+	if err != nil {
+		return err
+	}
+	if actor.Role != models.OrgRoleOwner {
+		return fmt.Errorf("%w: only the owner can change member roles", ErrForbiddenOperation)
+	}
+	if role != models.OrgRoleAdmin && role != models.OrgRoleMember {
+		return fmt.Errorf("%w: '%s'", ErrInvalidOrganizationRole, role)
+	}
+
+	target, err := s.orgRepo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return s.mapOrganizationError(err, "failed to get member %d of organization %d", userID, organizationID)
+	}
+	if target.Role == models.OrgRoleOwner {
+		return ErrCannotRemoveOrgOwner
+	}
+
+	if err := s.orgRepo.UpdateMemberRole(ctx, organizationID, userID, role); err != nil {
+		return s.mapOrganizationError(err, "failed to update role of member %d", userID)
+	}
+	return nil
+}
+
+// RemoveMember исключает участника. Пользователь может покинуть организацию сам,
+// admin может исключать рядовых участников, owner — любых, кроме себя.
+func (s *organizationService) RemoveMember(ctx context.Context, organizationID int, currentUserID int, userID int) error {
+	target, err := s.orgRepo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return s.mapOrganizationError(err, "failed to get member %d of organization %d", userID, organizationID)
+	}
+	if target.Role == models.OrgRoleOwner {
+		return ErrCannotRemoveOrgOwner
+	}
+
+	if currentUserID != userID {
+		actor, err := s.requireRole(ctx, organizationID, currentUserID, true)
+		if err != nil {
+			return err
+		}
+		if target.Role == models.OrgRoleAdmin && actor.Role != models.OrgRoleOwner {
+			return fmt.Errorf("%w: only the owner can remove admins", ErrForbiddenOperation)
+		}
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, organizationID, userID); err != nil {
+		return s.mapOrganizationError(err, "failed to remove member %d from organization %d", userID, organizationID)
+	}
+	return nil
+}
+
+// AttachTeam привязывает команду к организации. Доступно капитану команды, состоящему в организации.
+func (s *organizationService) AttachTeam(ctx context.Context, organizationID int, teamID int, currentUserID int) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTeamNotFound, "failed to get team %d", teamID)
+	}
+	if team.CaptainID != currentUserID {
+		return nil, ErrCaptainActionForbidden
+	}
+	if _, err := s.requireRole(ctx, organizationID, currentUserID, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.teamRepo.UpdateOrganization(ctx, teamID, &organizationID); err != nil {
+		return nil, s.mapOrganizationError(err, "failed to attach team %d to organization %d", teamID, organizationID)
+	}
+	team.OrganizationID = &organizationID
+	return team, nil
+}
+
+// DetachTeam отвязывает команду от организации. Доступно капитану команды и owner/admin организации.
+func (s *organizationService) DetachTeam(ctx context.Context, organizationID int, teamID int, currentUserID int) error {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return handleRepositoryError(err, ErrTeamNotFound, "failed to get team %d", teamID)
+	}
+	if team.OrganizationID == nil || *team.OrganizationID != organizationID {
+		return ErrTeamNotInOrganization
+	}
+	if team.CaptainID != currentUserID {
+		if _, err := s.requireRole(ctx, organizationID, currentUserID, true); err != nil {
+			return err
+		}
+	}
+
+	if err := s.teamRepo.UpdateOrganization(ctx, teamID, nil); err != nil {
+		return handleRepositoryError(err, ErrTeamNotFound, "failed to detach team %d", teamID)
+	}
+	return nil
+}
+
+// requireRole возвращает членство пользователя в организации. Если manage == true,
+// дополнительно требуется роль owner или admin.
+func (s *organizationService) requireRole(ctx context.Context, organizationID, userID int, manage bool) (*models.OrganizationMember, error) {
+	member, err := s.orgRepo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+			return nil, fmt.Errorf("%w: not a member of organization %d", ErrForbiddenOperation, organizationID)
+		}
+		return nil, fmt.Errorf("failed to check membership in organization %d: %w", organizationID, err)
+	}
+	if manage && !member.Role.CanManage() {
+		return nil, fmt.Errorf("%w: organization admin role required", ErrForbiddenOperation)
+	}
+	return member, nil
+}
+
+func (s *organizationService) mapOrganizationError(err error, format string, args ...interface{}) error {
+	switch {
+	case errors.Is(err, repositories.ErrOrganizationNotFound):
+		return ErrOrganizationNotFound
+	case errors.Is(err, repositories.ErrOrganizationNameConflict):
+		return ErrOrganizationNameConflict
+	case errors.Is(err, repositories.ErrOrganizationMemberNotFound):
+		return ErrOrganizationMemberNotFound
+	case errors.Is(err, repositories.ErrOrganizationMemberConflict):
+		return ErrOrganizationMemberConflict
+	case errors.Is(err, repositories.ErrOrganizationMemberUserInvalid):
+		return ErrUserNotFound
+	case errors.Is(err, repositories.ErrTeamNotFound):
+		return ErrTeamNotFound
+	}
+	return handleRepositoryError(err, nil, format, args...)
+}
@@ -38,7 +38,10 @@ type participantService struct {
 	userRepo        repositories.UserRepository
 	teamRepo        repositories.TeamRepository
 	formatRepo      repositories.FormatRepository // Добавлена зависимость для загрузки формата
+	orgRepo         repositories.OrganizationRepository
 	fileUploader    storage.FileUploader
+	notifier        NotificationService
+	webhooks        WebhookService
 }
 
 func NewParticipantService(
@@ -47,7 +50,10 @@ func NewParticipantService(
 	userRepo repositories.UserRepository,
 	teamRepo repositories.TeamRepository,
 	formatRepo repositories.FormatRepository, // Добавлен параметр
+	orgRepo repositories.OrganizationRepository,
 	fileUploader storage.FileUploader,
+	notifier NotificationService,
+	webhooks WebhookService,
 ) ParticipantService {
 	return &participantService{
 		participantRepo: participantRepo,
@@ -55,7 +61,10 @@ func NewParticipantService(
 		userRepo:        userRepo,
 		teamRepo:        teamRepo,
 		formatRepo:      formatRepo, // Инициализация
+		orgRepo:         orgRepo,
 		fileUploader:    fileUploader,
+		notifier:        notifier,
+		webhooks:        webhooks,
 	}
 }
 
@@ -64,16 +73,10 @@ func (s *participantService) populateParticipantDetails(p *models.Participant) {
 		return
 	}
 	if p.User != nil && p.User.LogoKey != nil && *p.User.LogoKey != "" {
-		url := s.fileUploader.GetPublicURL(*p.User.LogoKey)
-		if url != "" {
-			p.User.LogoURL = &url
-		}
+		p.User.LogoURL, p.User.LogoURLs = logoURLsFunc(s.fileUploader, p.User.LogoKey)
 	}
 	if p.Team != nil && p.Team.LogoKey != nil && *p.Team.LogoKey != "" {
-		url := s.fileUploader.GetPublicURL(*p.Team.LogoKey)
-		if url != "" {
-			p.Team.LogoURL = &url
-		}
+		p.Team.LogoURL, p.Team.LogoURLs = logoURLsFunc(s.fileUploader, p.Team.LogoKey)
 	}
 }
 
@@ -329,7 +332,11 @@ func (s *participantService) UpdateApplicationStatus(ctx context.Context, partic
 		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d for status update", participant.TournamentID)
 	}
 
-	if tournament.OrganizerID != currentUserID {
+	canManage, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
 		return nil, ErrNotTournamentOrganizer
 	}
 
@@ -352,18 +359,33 @@ func (s *participantService) UpdateApplicationStatus(ctx context.Context, partic
 		}
 	}
 
-	if err := s.participantRepo.UpdateStatus(ctx, participantID, newStatus); err != nil {
+	if err := s.participantRepo.UpdateStatus(ctx, nil, participantID, newStatus); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrParticipantStatusUpdateFailed, err)
 	}
 
+	if s.notifier != nil {
+		notificationType := models.NotificationApplicationApproved
+		if newStatus == models.StatusApplicationRejected {
+			notificationType = models.NotificationApplicationRejected
+		}
+		s.notifier.NotifyParticipants(ctx, NotificationEvent{Type: notificationType, Tournament: tournament}, participantID)
+	}
+
 	updatedParticipant, err := s.participantRepo.GetWithDetails(ctx, participantID)
 	if err != nil {
 		fmt.Printf("Warning: failed to get participant details after status update for participant ID %d: %v\n", participantID, err)
 		participant.Status = newStatus
-		s.populateParticipantDetails(participant)
-		return participant, nil
+		updatedParticipant = participant
 	}
 	s.populateParticipantDetails(updatedParticipant)
+
+	if s.webhooks != nil {
+		webhookEvent := models.WebhookParticipantApproved
+		if newStatus == models.StatusApplicationRejected {
+			webhookEvent = models.WebhookParticipantRejected
+		}
+		s.webhooks.Emit(ctx, webhookEvent, tournament, updatedParticipant)
+	}
 	return updatedParticipant, nil
 }
 
@@ -387,6 +409,8 @@ func handleRepositoryError(err error, serviceSpecificNotFoundError error, format
 		repositories.ErrInviteNotFound,
 		repositories.ErrSoloMatchNotFound,
 		repositories.ErrTeamMatchNotFound,
+		repositories.ErrOrganizationNotFound,
+		repositories.ErrSeasonNotFound,
 	}
 
 	for _, knownErr := range knownNotFoundErrors {
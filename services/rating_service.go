@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/ratings"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var (
+	ErrRatingSportRequired = errors.New("sport_id is required for ratings")
+)
+
+type RatingLeaderboardFilter struct {
+	SportID    int
+	Teams      bool
+	MinMatches int
+	Limit      int
+	Offset     int
+}
+
+type RatingService interface {
+	// ApplyMatchResult обновляет рейтинги участников завершённого матча внутри транзакции сохранения результата.
+	// Матчи применяются в порядке completed_at; если уже учтён матч, завершённый позже этого
+	// (параллельное сохранение раньше получило блокировку), рейтинги вида спорта пересчитываются заново.
+	ApplyMatchResult(ctx context.Context, exec repositories.SQLExecutor, matchType models.RatingMatchType, matchID int) error
+	// RebuildRatings полностью пересчитывает рейтинги по истории матчей; sportID == nil — для всех видов спорта.
+	RebuildRatings(ctx context.Context, sportID *int) error
+	GetLeaderboard(ctx context.Context, filter RatingLeaderboardFilter) ([]models.Rating, error)
+	GetUserRatings(ctx context.Context, userID int) ([]models.Rating, error)
+	GetTeamRatings(ctx context.Context, teamID int) ([]models.Rating, error)
+	GetRatingHistory(ctx context.Context, sportID int, userID, teamID *int, limit, offset int) ([]models.RatingHistoryEntry, error)
+	// SeedParticipants сортирует участников по рейтингу (по убыванию); участники без рейтинга получают стартовый.
+	SeedParticipants(ctx context.Context, sportID int, participants []*models.Participant) ([]*models.Participant, error)
+}
+
+type ratingService struct {
+	db         *sql.DB
+	ratingRepo repositories.RatingRepository
+	sportRepo  repositories.SportRepository
+	uploader   storage.FileUploader
+	logger     *slog.Logger
+}
+
+func NewRatingService(
+	db *sql.DB,
+	ratingRepo repositories.RatingRepository,
+	sportRepo repositories.SportRepository,
+	uploader storage.FileUploader,
+	logger *slog.Logger,
+) RatingService {
+	return &ratingService{
+		db:         db,
+		ratingRepo: ratingRepo,
+		sportRepo:  sportRepo,
+		uploader:   uploader,
+		logger:     logger,
+	}
+}
+
+func (s *ratingService) withTransaction(ctx context.Context, fn func(tx repositories.SQLExecutor) error) (err error) {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = dbTx.Rollback()
+			panic(p)
+		} else if err != nil {
+			if rbErr := dbTx.Rollback(); rbErr != nil {
+				s.logger.ErrorContext(ctx, "Transaction rollback failed", slog.Any("rollback_error", rbErr))
+			}
+		} else if cErr := dbTx.Commit(); cErr != nil {
+			err = fmt.Errorf("failed to commit transaction: %w", cErr)
+		}
+	}()
+	return fn(dbTx)
+}
+
+func (s *ratingService) ApplyMatchResult(ctx context.Context, exec repositories.SQLExecutor, matchType models.RatingMatchType, matchID int) error {
+	match, err := s.ratingRepo.GetRatedMatch(ctx, exec, matchType, matchID)
+	if err != nil {
+		return fmt.Errorf("failed to load %s match %d for rating: %w", matchType, matchID, err)
+	}
+
+	if err := s.ratingRepo.LockSport(ctx, exec, match.SportID); err != nil {
+		return err
+	}
+
+	// completed_at ставится при сохранении результата, до блокировки вида спорта. Если параллельная
+	// транзакция с более поздним completed_at успела применить свой матч раньше, порядок нарушен —
+	// пересчитываем вид спорта, чтобы результат совпал с полным пересчётом.
+	outOfOrder, err := s.ratingRepo.HasLaterRatedMatch(ctx, exec, match)
+	if err != nil {
+		return fmt.Errorf("failed to check rating order for %s match %d: %w", matchType, matchID, err)
+	}
+	if outOfOrder {
+		s.logger.InfoContext(ctx, "Match completed out of order, rebuilding ratings", slog.String("match_type", string(matchType)), slog.Int("match_id", matchID), slog.Int("sport_id", match.SportID))
+		return s.rebuildSport(ctx, exec, match.SportID)
+	}
+
+	return s.applyRatedMatch(ctx, exec, match)
+}
+
+func (s *ratingService) RebuildRatings(ctx context.Context, sportID *int) error {
+	sportIDs := make([]int, 0)
+	if sportID != nil {
+		if _, err := s.sportRepo.GetByID(ctx, *sportID); err != nil {
+			return handleRepositoryError(err, ErrSportNotFound, "failed to get sport %d", *sportID)
+		}
+		sportIDs = append(sportIDs, *sportID)
+	} else {
+		sports, err := s.sportRepo.GetAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list sports: %w", err)
+		}
+		for _, sport := range sports {
+			sportIDs = append(sportIDs, sport.ID)
+		}
+	}
+
+	for _, id := range sportIDs {
+		sid := id
+		err := s.withTransaction(ctx, func(tx repositories.SQLExecutor) error {
+			if err := s.ratingRepo.LockSport(ctx, tx, sid); err != nil {
+				return err
+			}
+			return s.rebuildSport(ctx, tx, sid)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rebuild ratings for sport %d: %w", sid, err)
+		}
+	}
+	return nil
+}
+
+// rebuildSport вызывается под блокировкой вида спорта.
+func (s *ratingService) rebuildSport(ctx context.Context, exec repositories.SQLExecutor, sportID int) error {
+	if err := s.ratingRepo.DeleteBySport(ctx, exec, sportID); err != nil {
+		return fmt.Errorf("failed to clear ratings for sport %d: %w", sportID, err)
+	}
+
+	matches, err := s.ratingRepo.ListRatedMatches(ctx, exec, sportID)
+	if err != nil {
+		return fmt.Errorf("failed to list matches for sport %d: %w", sportID, err)
+	}
+	for i := range matches {
+		if err := s.applyRatedMatch(ctx, exec, &matches[i]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "Ratings rebuilt", slog.Int("sport_id", sportID), slog.Int("matches", len(matches)))
+	return nil
+}
+
+func (s *ratingService) applyRatedMatch(ctx context.Context, exec repositories.SQLExecutor, match *models.RatedMatch) error {
+	if (match.P1UserID == nil && match.P1TeamID == nil) || (match.P2UserID == nil && match.P2TeamID == nil) {
+		s.logger.WarnContext(ctx, "Skipping rating update: participant without user or team", slog.Int("match_id", match.MatchID))
+		return nil
+	}
+
+	r1, err := s.ratingRepo.GetOrCreate(ctx, exec, match.SportID, match.P1UserID, match.P1TeamID)
+	if err != nil {
+		return fmt.Errorf("failed to get rating for participant %d: %w", match.P1ParticipantID, err)
+	}
+	r2, err := s.ratingRepo.GetOrCreate(ctx, exec, match.SportID, match.P2UserID, match.P2TeamID)
+	if err != nil {
+		return fmt.Errorf("failed to get rating for participant %d: %w", match.P2ParticipantID, err)
+	}
+
+	score1 := ratings.ScoreDraw
+	if match.WinnerParticipantID != nil {
+		if *match.WinnerParticipantID == match.P1ParticipantID {
+			score1 = ratings.ScoreWin
+		} else {
+			score1 = ratings.ScoreLoss
+		}
+	}
+	score2 := 1 - score1
+
+	before1 := ratings.Rating{Rating: r1.Rating, Deviation: r1.Deviation, Volatility: r1.Volatility}
+	before2 := ratings.Rating{Rating: r2.Rating, Deviation: r2.Deviation, Volatility: r2.Volatility}
+	after1 := ratings.Update(before1, before2, score1)
+	after2 := ratings.Update(before2, before1, score2)
+
+	if err := s.saveRatingChange(ctx, exec, r1, r2.ID, match, before1, after1, score1); err != nil {
+		return err
+	}
+	return s.saveRatingChange(ctx, exec, r2, r1.ID, match, before2, after2, score2)
+}
+
+func (s *ratingService) saveRatingChange(ctx context.Context, exec repositories.SQLExecutor, r *models.Rating, opponentRatingID int, match *models.RatedMatch, before, after ratings.Rating, score float64) error {
+	r.Rating = after.Rating
+	r.Deviation = after.Deviation
+	r.Volatility = after.Volatility
+	r.MatchesPlayed++
+	switch score {
+	case ratings.ScoreWin:
+		r.Wins++
+	case ratings.ScoreLoss:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+	if err := s.ratingRepo.Update(ctx, exec, r); err != nil {
+		return fmt.Errorf("failed to update rating %d: %w", r.ID, err)
+	}
+
+	entry := &models.RatingHistoryEntry{
+		RatingID:         r.ID,
+		MatchType:        match.MatchType,
+		MatchID:          match.MatchID,
+		TournamentID:     match.TournamentID,
+		OpponentRatingID: &opponentRatingID,
+		Score:            score,
+		RatingBefore:     before.Rating,
+		RatingAfter:      after.Rating,
+		DeviationBefore:  before.Deviation,
+		DeviationAfter:   after.Deviation,
+	}
+	if err := s.ratingRepo.AddHistory(ctx, exec, entry); err != nil {
+		return fmt.Errorf("failed to save rating history for rating %d: %w", r.ID, err)
+	}
+	return nil
+}
+
+func (s *ratingService) GetLeaderboard(ctx context.Context, filter RatingLeaderboardFilter) ([]models.Rating, error) {
+	if filter.SportID <= 0 {
+		return nil, ErrRatingSportRequired
+	}
+	list, err := s.ratingRepo.ListLeaderboard(ctx, repositories.ListRatingsFilter{
+		SportID:    filter.SportID,
+		Teams:      filter.Teams,
+		MinMatches: filter.MinMatches,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings for sport %d: %w", filter.SportID, err)
+	}
+	for i := range list {
+		list[i].Rank = filter.Offset + i + 1
+		if list[i].LogoKey != nil && *list[i].LogoKey != "" && s.uploader != nil {
+			list[i].LogoURL, list[i].LogoURLs = logoURLsFunc(s.uploader, list[i].LogoKey)
+		}
+	}
+	return list, nil
+}
+
+func (s *ratingService) GetUserRatings(ctx context.Context, userID int) ([]models.Rating, error) {
+	list, err := s.ratingRepo.ListByEntity(ctx, &userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings for user %d: %w", userID, err)
+	}
+	return list, nil
+}
+
+func (s *ratingService) GetTeamRatings(ctx context.Context, teamID int) ([]models.Rating, error) {
+	list, err := s.ratingRepo.ListByEntity(ctx, nil, &teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings for team %d: %w", teamID, err)
+	}
+	return list, nil
+}
+
+func (s *ratingService) GetRatingHistory(ctx context.Context, sportID int, userID, teamID *int, limit, offset int) ([]models.RatingHistoryEntry, error) {
+	if sportID <= 0 {
+		return nil, ErrRatingSportRequired
+	}
+	history, err := s.ratingRepo.ListHistory(ctx, sportID, userID, teamID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rating history: %w", err)
+	}
+	return history, nil
+}
+
+func (s *ratingService) SeedParticipants(ctx context.Context, sportID int, participants []*models.Participant) ([]*models.Participant, error) {
+	userIDs := make([]int, 0, len(participants))
+	teamIDs := make([]int, 0, len(participants))
+	for _, p := range participants {
+		if p.UserID != nil {
+			userIDs = append(userIDs, *p.UserID)
+		}
+		if p.TeamID != nil {
+			teamIDs = append(teamIDs, *p.TeamID)
+		}
+	}
+
+	userRatings, teamRatings, err := s.ratingRepo.MapBySport(ctx, sportID, userIDs, teamIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ratings for seeding: %w", err)
+	}
+
+	ratingOf := func(p *models.Participant) float64 {
+		if p.UserID != nil {
+			if r, ok := userRatings[*p.UserID]; ok {
+				return r
+			}
+		}
+		if p.TeamID != nil {
+			if r, ok := teamRatings[*p.TeamID]; ok {
+				return r
+			}
+		}
+		return ratings.DefaultRating
+	}
+
+	seeded := make([]*models.Participant, len(participants))
+	copy(seeded, participants)
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return ratingOf(seeded[i]) > ratingOf(seeded[j])
+	})
+	return seeded, nil
+}
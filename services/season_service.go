@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var (
+	ErrSeasonNotFound           = errors.New("season not found")
+	ErrSeasonNameRequired       = errors.New("season name is required")
+	ErrSeasonNameConflict       = errors.New("season name already exists for this organizer")
+	ErrSeasonSportMismatch      = errors.New("tournament sport does not match season sport")
+	ErrSeasonTournamentConflict = repositories.ErrSeasonTournamentConflict
+	ErrSeasonTournamentNotFound = repositories.ErrSeasonTournamentNotFound
+	ErrSeasonInvalidPointsTable = errors.New("points table values must not be negative")
+)
+
+type CreateSeasonInput struct {
+	Name                string  `json:"name"`
+	Description         *string `json:"description"`
+	SportID             int     `json:"sport_id"`
+	OrganizationID      *int    `json:"organization_id"`
+	PointsTable         []int   `json:"points_table"`
+	ParticipationPoints int     `json:"participation_points"`
+}
+
+type UpdateSeasonInput struct {
+	Name                *string `json:"name"`
+	Description         *string `json:"description"`
+	PointsTable         []int   `json:"points_table"`
+	ParticipationPoints *int    `json:"participation_points"`
+}
+
+type ListSeasonsFilter struct {
+	SportID        *int
+	OrganizationID *int
+	Limit          int
+	Offset         int
+}
+
+type SeasonService interface {
+	CreateSeason(ctx context.Context, organizerID int, input CreateSeasonInput) (*models.Season, error)
+	GetSeasonByID(ctx context.Context, id int) (*models.Season, error)
+	ListSeasons(ctx context.Context, filter ListSeasonsFilter) ([]models.Season, error)
+	UpdateSeason(ctx context.Context, id int, currentUserID int, input UpdateSeasonInput) (*models.Season, error)
+	DeleteSeason(ctx context.Context, id int, currentUserID int) error
+	AddTournament(ctx context.Context, seasonID, tournamentID, currentUserID int) error
+	RemoveTournament(ctx context.Context, seasonID, tournamentID, currentUserID int) error
+	GetLeaderboard(ctx context.Context, seasonID int) ([]models.SeasonLeaderboardEntry, error)
+	// ApplyTournamentResults пересчитывает вклад завершённого турнира в таблицу сезона.
+	// Вызывается из FinalizeTournament внутри его транзакции; турниры вне сезонов игнорируются.
+	ApplyTournamentResults(ctx context.Context, exec repositories.SQLExecutor, tournament *models.Tournament, championPID *int) error
+}
+
+type seasonService struct {
+	seasonRepo      repositories.SeasonRepository
+	tournamentRepo  repositories.TournamentRepository
+	sportRepo       repositories.SportRepository
+	formatRepo      repositories.FormatRepository
+	participantRepo repositories.ParticipantRepository
+	soloMatchRepo   repositories.SoloMatchRepository
+	teamMatchRepo   repositories.TeamMatchRepository
+	standingRepo    repositories.TournamentStandingRepository
+	orgRepo         repositories.OrganizationRepository
+	uploader        storage.FileUploader
+	logger          *slog.Logger
+}
+
+func NewSeasonService(
+	seasonRepo repositories.SeasonRepository,
+	tournamentRepo repositories.TournamentRepository,
+	sportRepo repositories.SportRepository,
+	formatRepo repositories.FormatRepository,
+	participantRepo repositories.ParticipantRepository,
+	soloMatchRepo repositories.SoloMatchRepository,
+	teamMatchRepo repositories.TeamMatchRepository,
+	standingRepo repositories.TournamentStandingRepository,
+	orgRepo repositories.OrganizationRepository,
+	uploader storage.FileUploader,
+	logger *slog.Logger,
+) SeasonService {
+	return &seasonService{
+		seasonRepo:      seasonRepo,
+		tournamentRepo:  tournamentRepo,
+		sportRepo:       sportRepo,
+		formatRepo:      formatRepo,
+		participantRepo: participantRepo,
+		soloMatchRepo:   soloMatchRepo,
+		teamMatchRepo:   teamMatchRepo,
+		standingRepo:    standingRepo,
+		orgRepo:         orgRepo,
+		uploader:        uploader,
+		logger:          logger,
+	}
+}
+
+func (s *seasonService) CreateSeason(ctx context.Context, organizerID int, input CreateSeasonInput) (*models.Season, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrSeasonNameRequired
+	}
+	if err := validatePointsTable(input.PointsTable, input.ParticipationPoints); err != nil {
+		return nil, err
+	}
+	if _, err := s.sportRepo.GetByID(ctx, input.SportID); err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentSportNotFound, "failed to verify sport %d", input.SportID)
+	}
+	if input.OrganizationID != nil {
+		member, err := s.orgRepo.GetMember(ctx, *input.OrganizationID, organizerID)
+		if err != nil && !errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+			return nil, fmt.Errorf("failed to verify organization %d membership: %w", *input.OrganizationID, err)
+		}
+		if member == nil || !member.Role.CanManage() {
+			return nil, fmt.Errorf("%w: organization admin role required", ErrForbiddenOperation)
+		}
+	}
+
+	season := &models.Season{
+		Name:                name,
+		Description:         input.Description,
+		SportID:             input.SportID,
+		OrganizerID:         organizerID,
+		OrganizationID:      input.OrganizationID,
+		PointsTable:         input.PointsTable,
+		ParticipationPoints: input.ParticipationPoints,
+	}
+	if err := s.seasonRepo.Create(ctx, season); err != nil {
+		return nil, mapSeasonRepositoryError(err, "failed to create season")
+	}
+	return season, nil
+}
+
+func (s *seasonService) GetSeasonByID(ctx context.Context, id int) (*models.Season, error) {
+	season, err := s.seasonRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapSeasonRepositoryError(err, "failed to get season %d", id)
+	}
+
+	if sport, sportErr := s.sportRepo.GetByID(ctx, season.SportID); sportErr == nil {
+		populateSportLogoURLFunc(sport, s.uploader)
+		season.Sport = sport
+	}
+
+	tournamentIDs, err := s.seasonRepo.ListTournamentIDs(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tournaments of season %d: %w", id, err)
+	}
+	season.Tournaments = make([]models.Tournament, 0, len(tournamentIDs))
+	for _, tid := range tournamentIDs {
+		t, tErr := s.tournamentRepo.GetByID(ctx, tid)
+		if tErr != nil {
+			s.logger.WarnContext(ctx, "GetSeasonByID: failed to load tournament", slog.Int("season_id", id), slog.Int("tournament_id", tid), slog.Any("error", tErr))
+			continue
+		}
+		populateTournamentLogoURLFunc(t, s.uploader)
+		season.Tournaments = append(season.Tournaments, *t)
+	}
+	return season, nil
+}
+
+func (s *seasonService) ListSeasons(ctx context.Context, filter ListSeasonsFilter) ([]models.Season, error) {
+	seasons, err := s.seasonRepo.List(ctx, repositories.ListSeasonsFilter{
+		SportID:        filter.SportID,
+		OrganizationID: filter.OrganizationID,
+		Limit:          filter.Limit,
+		Offset:         filter.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list seasons: %w", err)
+	}
+	return seasons, nil
+}
+
+func (s *seasonService) UpdateSeason(ctx context.Context, id int, currentUserID int, input UpdateSeasonInput) (*models.Season, error) {
+	season, err := s.getManagedSeason(ctx, id, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, ErrSeasonNameRequired
+		}
+		season.Name = name
+	}
+	if input.Description != nil {
+		season.Description = input.Description
+	}
+	if input.PointsTable != nil {
+		season.PointsTable = input.PointsTable
+	}
+	if input.ParticipationPoints != nil {
+		season.ParticipationPoints = *input.ParticipationPoints
+	}
+	if err := validatePointsTable(season.PointsTable, season.ParticipationPoints); err != nil {
+		return nil, err
+	}
+
+	if err := s.seasonRepo.Update(ctx, season); err != nil {
+		return nil, mapSeasonRepositoryError(err, "failed to update season %d", id)
+	}
+
+	// Таблица очков могла измениться — пересчитываем итоги уже завершённых турниров
+	if input.PointsTable != nil || input.ParticipationPoints != nil {
+		if err := s.recalculateSeason(ctx, season); err != nil {
+			return nil, err
+		}
+	}
+	return season, nil
+}
+
+func (s *seasonService) DeleteSeason(ctx context.Context, id int, currentUserID int) error {
+	if _, err := s.getManagedSeason(ctx, id, currentUserID); err != nil {
+		return err
+	}
+	if err := s.seasonRepo.Delete(ctx, id); err != nil {
+		return mapSeasonRepositoryError(err, "failed to delete season %d", id)
+	}
+	return nil
+}
+
+func (s *seasonService) AddTournament(ctx context.Context, seasonID, tournamentID, currentUserID int) error {
+	season, err := s.getManagedSeason(ctx, seasonID, currentUserID)
+	if err != nil {
+		return err
+	}
+
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	allowed, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, currentUserID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrForbiddenOperation
+	}
+	if tournament.SportID != season.SportID {
+		return ErrSeasonSportMismatch
+	}
+
+	if err := s.seasonRepo.AddTournament(ctx, seasonID, tournamentID); err != nil {
+		return mapSeasonRepositoryError(err, "failed to add tournament %d to season %d", tournamentID, seasonID)
+	}
+
+	// Уже завершённый турнир сразу учитываем в таблице сезона
+	if tournament.Status == models.StatusCompleted {
+		if err := s.ApplyTournamentResults(ctx, nil, tournament, tournament.OverallWinnerParticipantID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seasonService) RemoveTournament(ctx context.Context, seasonID, tournamentID, currentUserID int) error {
+	if _, err := s.getManagedSeason(ctx, seasonID, currentUserID); err != nil {
+		return err
+	}
+	if err := s.seasonRepo.RemoveTournament(ctx, nil, seasonID, tournamentID); err != nil {
+		return mapSeasonRepositoryError(err, "failed to remove tournament %d from season %d", tournamentID, seasonID)
+	}
+	return nil
+}
+
+func (s *seasonService) GetLeaderboard(ctx context.Context, seasonID int) ([]models.SeasonLeaderboardEntry, error) {
+	if _, err := s.seasonRepo.GetByID(ctx, seasonID); err != nil {
+		return nil, mapSeasonRepositoryError(err, "failed to get season %d", seasonID)
+	}
+
+	entries, err := s.seasonRepo.GetLeaderboard(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard for season %d: %w", seasonID, err)
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Points == entries[i-1].Points && entries[i].TournamentWins == entries[i-1].TournamentWins && entries[i].BestPlacement == entries[i-1].BestPlacement {
+			entries[i].Rank = entries[i-1].Rank
+		}
+		if entries[i].LogoKey != nil && *entries[i].LogoKey != "" && s.uploader != nil {
+			entries[i].LogoURL, entries[i].LogoURLs = logoURLsFunc(s.uploader, entries[i].LogoKey)
+		}
+	}
+	return entries, nil
+}
+
+func (s *seasonService) ApplyTournamentResults(ctx context.Context, exec repositories.SQLExecutor, tournament *models.Tournament, championPID *int) error {
+	season, err := s.seasonRepo.GetByTournamentID(ctx, exec, tournament.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSeasonNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get season for tournament %d: %w", tournament.ID, err)
+	}
+	return s.applyResults(ctx, exec, season, tournament, championPID)
+}
+
+func (s *seasonService) applyResults(ctx context.Context, exec repositories.SQLExecutor, season *models.Season, tournament *models.Tournament, championPID *int) error {
+	if tournament.Format == nil {
+		format, err := s.formatRepo.GetByID(ctx, tournament.FormatID)
+		if err != nil {
+			return handleRepositoryError(err, ErrFormatNotFound, "failed to load format %d for tournament %d", tournament.FormatID, tournament.ID)
+		}
+		tournament.Format = format
+	}
+
+	placements, err := buildTournamentPlacementsFunc(ctx, exec, tournament, championPID, s.participantRepo, s.soloMatchRepo, s.teamMatchRepo, s.standingRepo)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*models.SeasonResult, 0, len(placements))
+	for _, p := range placements {
+		results = append(results, &models.SeasonResult{
+			ParticipantID: p.ParticipantID,
+			UserID:        p.UserID,
+			TeamID:        p.TeamID,
+			Placement:     p.Placement,
+			Points:        season.PointsForPlacement(p.Placement),
+		})
+	}
+
+	if err := s.seasonRepo.ReplaceTournamentResults(ctx, exec, season.ID, tournament.ID, results); err != nil {
+		return fmt.Errorf("failed to save season results for tournament %d: %w", tournament.ID, err)
+	}
+	s.logger.InfoContext(ctx, "Season results updated", slog.Int("season_id", season.ID), slog.Int("tournament_id", tournament.ID), slog.Int("results", len(results)))
+	return nil
+}
+
+func (s *seasonService) recalculateSeason(ctx context.Context, season *models.Season) error {
+	tournamentIDs, err := s.seasonRepo.ListTournamentIDs(ctx, season.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list tournaments of season %d: %w", season.ID, err)
+	}
+	for _, tid := range tournamentIDs {
+		t, tErr := s.tournamentRepo.GetByID(ctx, tid)
+		if tErr != nil {
+			return handleRepositoryError(tErr, ErrTournamentNotFound, "failed to get tournament %d", tid)
+		}
+		if t.Status != models.StatusCompleted {
+			continue
+		}
+		if err := s.applyResults(ctx, nil, season, t, t.OverallWinnerParticipantID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seasonService) getManagedSeason(ctx context.Context, id int, userID int) (*models.Season, error) {
+	season, err := s.seasonRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapSeasonRepositoryError(err, "failed to get season %d", id)
+	}
+	if season.OrganizerID == userID {
+		return season, nil
+	}
+	if season.OrganizationID != nil {
+		member, memberErr := s.orgRepo.GetMember(ctx, *season.OrganizationID, userID)
+		if memberErr != nil && !errors.Is(memberErr, repositories.ErrOrganizationMemberNotFound) {
+			return nil, fmt.Errorf("failed to check organization membership: %w", memberErr)
+		}
+		if member != nil && member.Role.CanManage() {
+			return season, nil
+		}
+	}
+	return nil, ErrForbiddenOperation
+}
+
+func validatePointsTable(table []int, participationPoints int) error {
+	if participationPoints < 0 {
+		return ErrSeasonInvalidPointsTable
+	}
+	for _, p := range table {
+		if p < 0 {
+			return ErrSeasonInvalidPointsTable
+		}
+	}
+	return nil
+}
+
+func mapSeasonRepositoryError(err error, format string, args ...interface{}) error {
+	switch {
+	case errors.Is(err, repositories.ErrSeasonNotFound):
+		return ErrSeasonNotFound
+	case errors.Is(err, repositories.ErrSeasonNameConflict):
+		return ErrSeasonNameConflict
+	case errors.Is(err, repositories.ErrSeasonInvalidSport):
+		return ErrTournamentSportNotFound
+	case errors.Is(err, repositories.ErrSeasonInvalidOrganization):
+		return ErrOrganizationNotFound
+	case errors.Is(err, repositories.ErrSeasonTournamentConflict),
+		errors.Is(err, repositories.ErrSeasonTournamentNotFound):
+		return err
+	}
+	return handleRepositoryError(err, nil, format, args...)
+}
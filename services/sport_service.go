@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dosada05/tournament-system/imaging"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/scoring"
 	"github.com/Dosada05/tournament-system/storage"
 )
 
@@ -26,6 +28,9 @@ var (
 	ErrSportDeleteFailed       = errors.New("failed to delete sport")
 	ErrSportLogoUpdateDBFailed = errors.New("failed to update sport logo information in database")
 	ErrSportLogoUploadFailed   = errors.New("failed to upload sport logo")
+	ErrSportInvalidEventType   = errors.New("unknown match event type")
+	ErrSportScoreFromGoals     = errors.New("score_from_goals requires the goal event type and the points score schema")
+	ErrSportInvalidScoring     = errors.New("invalid sport scoring rules")
 )
 
 type SportService interface {
@@ -38,37 +43,44 @@ type SportService interface {
 }
 
 type CreateSportInput struct {
-	Name string
+	Name            string
+	MatchEventTypes []models.MatchEventType `json:"match_event_types"`
+	ScoreFromGoals  bool                    `json:"score_from_goals"`
+	ScoringRules    *models.ScoringRules    `json:"scoring_rules"` // nil — очки "S1-S2" с ничьими
 }
 
+// UpdateSportInput — nil в MatchEventTypes / ScoreFromGoals / ScoringRules оставляет настройку без изменений.
 type UpdateSportInput struct {
-	Name string
+	Name            string
+	MatchEventTypes *[]models.MatchEventType `json:"match_event_types"`
+	ScoreFromGoals  *bool                    `json:"score_from_goals"`
+	ScoringRules    *models.ScoringRules     `json:"scoring_rules"`
 }
 
 type sportService struct {
 	sportRepo repositories.SportRepository
 	userRepo  repositories.UserRepository // Для проверки прав админа
 	uploader  storage.FileUploader
+	images    *imaging.Processor
 }
 
 func NewSportService(
 	sportRepo repositories.SportRepository,
 	userRepo repositories.UserRepository,
 	uploader storage.FileUploader,
+	images *imaging.Processor,
 ) SportService {
 	return &sportService{
 		sportRepo: sportRepo,
 		userRepo:  userRepo,
 		uploader:  uploader,
+		images:    images,
 	}
 }
 
 func (s *sportService) populateSportLogoURL(sport *models.Sport) {
 	if sport != nil && sport.LogoKey != nil && *sport.LogoKey != "" && s.uploader != nil {
-		url := s.uploader.GetPublicURL(*sport.LogoKey)
-		if url != "" {
-			sport.LogoURL = &url
-		}
+		sport.LogoURL, sport.LogoURLs = logoURLsFunc(s.uploader, sport.LogoKey)
 	}
 }
 
@@ -85,9 +97,18 @@ func (s *sportService) CreateSport(ctx context.Context, input CreateSportInput)
 	}
 
 	sport := &models.Sport{
-		Name: name,
+		Name:            name,
+		MatchEventTypes: input.MatchEventTypes,
+		ScoreFromGoals:  input.ScoreFromGoals,
+		ScoringRules:    models.DefaultScoringRules(),
 		// LogoKey изначально nil
 	}
+	if input.ScoringRules != nil {
+		sport.ScoringRules = *input.ScoringRules
+	}
+	if err := validateSportMatchEvents(sport); err != nil {
+		return nil, err
+	}
 
 	err := s.sportRepo.Create(ctx, sport)
 	if err != nil {
@@ -100,6 +121,30 @@ func (s *sportService) CreateSport(ctx context.Context, input CreateSportInput)
 	return sport, nil
 }
 
+// validateSportMatchEvents проверяет правила счёта и типы событий хронологии, убирает повторы типов.
+func validateSportMatchEvents(sport *models.Sport) error {
+	seen := make(map[models.MatchEventType]bool, len(sport.MatchEventTypes))
+	types := make([]models.MatchEventType, 0, len(sport.MatchEventTypes))
+	for _, t := range sport.MatchEventTypes {
+		if !t.IsValid() {
+			return fmt.Errorf("%w: '%s'", ErrSportInvalidEventType, t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	sport.MatchEventTypes = types
+
+	if err := scoring.ValidateRules(sport.ScoringRules); err != nil {
+		return fmt.Errorf("%w: %w", ErrSportInvalidScoring, err)
+	}
+	if sport.ScoreFromGoals && (!seen[models.MatchEventGoal] || sport.ScoringRules.Schema != models.ScoreSchemaPoints || sport.ScoringRules.LowerIsBetter) {
+		return ErrSportScoreFromGoals
+	}
+	return nil
+}
+
 func (s *sportService) GetSportByID(ctx context.Context, id int) (*models.Sport, error) {
 	sport, err := s.sportRepo.GetByID(ctx, id)
 	if err != nil {
@@ -139,9 +184,21 @@ func (s *sportService) UpdateSport(ctx context.Context, id int, input UpdateSpor
 		return nil, fmt.Errorf("failed to get sport %d for update: %w", id, err)
 	}
 
-	sportToUpdate.Name = name // Обновляем только имя
+	sportToUpdate.Name = name
+	if input.MatchEventTypes != nil {
+		sportToUpdate.MatchEventTypes = *input.MatchEventTypes
+	}
+	if input.ScoreFromGoals != nil {
+		sportToUpdate.ScoreFromGoals = *input.ScoreFromGoals
+	}
+	if input.ScoringRules != nil {
+		sportToUpdate.ScoringRules = *input.ScoringRules
+	}
+	if err := validateSportMatchEvents(sportToUpdate); err != nil {
+		return nil, err
+	}
 
-	err = s.sportRepo.Update(ctx, sportToUpdate) // Репозиторий обновит только имя
+	err = s.sportRepo.Update(ctx, sportToUpdate) // Логотип репозиторий не трогает
 	if err != nil {
 		switch {
 		case errors.Is(err, repositories.ErrSportNotFound):
@@ -185,7 +242,7 @@ func (s *sportService) DeleteSport(ctx context.Context, id int) error {
 		go func(keyToDelete string) {
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				fmt.Printf("Warning: Failed to delete sport logo %s during sport deletion: %v\n", keyToDelete, deleteErr)
 			}
 		}(*oldLogoKey)
@@ -221,21 +278,17 @@ func (s *sportService) UploadSportLogo(ctx context.Context, sportID int, current
 
 	oldLogoKey := sport.LogoKey
 
-	ext, err := GetExtensionFromContentType(contentType) // Используем общую функцию GetExtensionFromContentType
+	newKey, err := uploadLogoFunc(ctx, s.uploader, s.images, fmt.Sprintf("%s/%d/logo_%d", sportLogoPrefix, sportID, time.Now().UnixNano()), file)
 	if err != nil {
-		return nil, err // err уже будет ErrCouldNotDetermineFileExtension
-	}
-
-	newKey := fmt.Sprintf("%s/%d/logo_%d%s", sportLogoPrefix, sportID, time.Now().UnixNano(), ext)
-
-	_, err = s.uploader.Upload(ctx, newKey, contentType, file)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s: %w", ErrSportLogoUploadFailed, newKey, err)
+		if errors.Is(err, ErrLogoUploadFailed) {
+			return nil, fmt.Errorf("%w: %w", ErrSportLogoUploadFailed, err)
+		}
+		return nil, err
 	}
 
 	err = s.sportRepo.UpdateLogoKey(ctx, sportID, &newKey)
 	if err != nil {
-		if deleteErr := s.uploader.Delete(context.Background(), newKey); deleteErr != nil {
+		if deleteErr := deleteLogoFunc(context.Background(), s.uploader, newKey); deleteErr != nil {
 			fmt.Printf("CRITICAL: Failed to delete uploaded sport logo %s after DB update error: %v. DB error: %v\n", newKey, deleteErr, err)
 		}
 		if errors.Is(err, repositories.ErrSportNotFound) {
@@ -248,7 +301,7 @@ func (s *sportService) UploadSportLogo(ctx context.Context, sportID int, current
 		go func(keyToDelete string) {
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				fmt.Printf("Warning: Failed to delete old sport logo %s: %v\n", keyToDelete, deleteErr)
 			}
 		}(*oldLogoKey)
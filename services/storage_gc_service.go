@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/Dosada05/tournament-system/imaging"
+	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/storage"
+)
+
+var ErrStorageGCInvalidGracePeriod = errors.New("grace period must not be negative")
+
+// storageGCPrefixes — директории хранилища, в которых лежат файлы, привязанные к logo_key.
+var storageGCPrefixes = []string{
+	userLogoPrefix + "/",
+	teamLogoPrefix + "/",
+	sportLogoPrefix + "/",
+	tournamentLogoPrefix + "/",
+}
+
+type StorageGCOptions struct {
+	DryRun bool
+	// Объекты моложе GracePeriod не удаляются: их запись в БД может быть ещё не закоммичена.
+	GracePeriod time.Duration
+}
+
+type StorageGCObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type StorageGCPrefixReport struct {
+	Prefix     string `json:"prefix"`
+	Scanned    int    `json:"scanned"`
+	Referenced int    `json:"referenced"`
+	Recent     int    `json:"recent"` // Неиспользуемые, но моложе периода ожидания
+	Orphaned   int    `json:"orphaned"`
+	Error      string `json:"error,omitempty"`
+}
+
+type StorageGCReport struct {
+	DryRun       bool                    `json:"dry_run"`
+	GracePeriod  string                  `json:"grace_period"`
+	StartedAt    time.Time               `json:"started_at"`
+	FinishedAt   time.Time               `json:"finished_at"`
+	Prefixes     []StorageGCPrefixReport `json:"prefixes"`
+	Orphans      []StorageGCObject       `json:"orphans"`
+	Deleted      int                     `json:"deleted"`
+	BytesFreed   int64                   `json:"bytes_freed"`
+	DeleteErrors map[string]string       `json:"delete_errors,omitempty"`
+}
+
+type StorageGCService interface {
+	// CollectGarbage удаляет из хранилища файлы, на которые не ссылается ни один logo_key.
+	// В режиме DryRun только составляет отчёт.
+	CollectGarbage(ctx context.Context, opts StorageGCOptions) (*StorageGCReport, error)
+}
+
+type storageGCService struct {
+	logoKeyRepo repositories.LogoKeyRepository
+	uploader    storage.FileUploader
+	logger      *slog.Logger
+}
+
+func NewStorageGCService(logoKeyRepo repositories.LogoKeyRepository, uploader storage.FileUploader, logger *slog.Logger) StorageGCService {
+	return &storageGCService{
+		logoKeyRepo: logoKeyRepo,
+		uploader:    uploader,
+		logger:      logger,
+	}
+}
+
+func (s *storageGCService) CollectGarbage(ctx context.Context, opts StorageGCOptions) (*StorageGCReport, error) {
+	if opts.GracePeriod < 0 {
+		return nil, ErrStorageGCInvalidGracePeriod
+	}
+	report := &StorageGCReport{
+		DryRun:      opts.DryRun,
+		GracePeriod: opts.GracePeriod.String(),
+		StartedAt:   time.Now().UTC(),
+		Prefixes:    []StorageGCPrefixReport{},
+		Orphans:     []StorageGCObject{},
+	}
+
+	// Без списка ссылок из БД удалять нельзя ничего — иначе под удаление попадёт всё.
+	keys, err := s.logoKeyRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storage gc: failed to load referenced keys: %w", err)
+	}
+	referencedKeys := make(map[string]struct{}, len(keys))
+	// Варианты лежат рядом с оригиналом, поэтому директория оригинала считается используемой целиком.
+	referencedDirs := make(map[string]struct{})
+	for _, key := range keys {
+		referencedKeys[key] = struct{}{}
+		if imaging.IsOriginalKey(key) {
+			referencedDirs[path.Dir(key)] = struct{}{}
+		}
+	}
+
+	cutoff := time.Now().Add(-opts.GracePeriod)
+	for _, prefix := range storageGCPrefixes {
+		prefixReport := StorageGCPrefixReport{Prefix: prefix}
+		objects, err := s.uploader.List(ctx, prefix)
+		if err != nil {
+			// Ошибка одной директории не мешает проверить остальные.
+			s.logger.ErrorContext(ctx, "Storage GC: failed to list objects", slog.String("prefix", prefix), slog.Any("error", err))
+			prefixReport.Error = err.Error()
+			report.Prefixes = append(report.Prefixes, prefixReport)
+			continue
+		}
+
+		for _, obj := range objects {
+			prefixReport.Scanned++
+			if _, ok := referencedKeys[obj.Key]; ok {
+				prefixReport.Referenced++
+				continue
+			}
+			if _, ok := referencedDirs[path.Dir(obj.Key)]; ok {
+				prefixReport.Referenced++
+				continue
+			}
+			if obj.LastModified.IsZero() || obj.LastModified.After(cutoff) {
+				prefixReport.Recent++
+				continue
+			}
+			prefixReport.Orphaned++
+			report.Orphans = append(report.Orphans, StorageGCObject{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+		}
+		report.Prefixes = append(report.Prefixes, prefixReport)
+	}
+
+	if !opts.DryRun {
+		for _, orphan := range report.Orphans {
+			if err := s.uploader.Delete(ctx, orphan.Key); err != nil {
+				if report.DeleteErrors == nil {
+					report.DeleteErrors = make(map[string]string)
+				}
+				report.DeleteErrors[orphan.Key] = err.Error()
+				s.logger.WarnContext(ctx, "Storage GC: failed to delete orphaned object", slog.String("key", orphan.Key), slog.Any("error", err))
+				continue
+			}
+			report.Deleted++
+			report.BytesFreed += orphan.Size
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	s.logger.InfoContext(ctx, "Storage GC finished",
+		slog.Bool("dry_run", opts.DryRun),
+		slog.Int("orphans", len(report.Orphans)),
+		slog.Int("deleted", report.Deleted),
+		slog.Int64("bytes_freed", report.BytesFreed),
+	)
+	return report, nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dosada05/tournament-system/imaging"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/repositories"
 	"github.com/Dosada05/tournament-system/storage"
@@ -49,23 +50,29 @@ type UpdateTeamInput struct {
 }
 
 type teamService struct {
-	teamRepo  repositories.TeamRepository
-	userRepo  repositories.UserRepository
-	sportRepo repositories.SportRepository
-	uploader  storage.FileUploader
+	teamRepo      repositories.TeamRepository
+	userRepo      repositories.UserRepository
+	sportRepo     repositories.SportRepository
+	placementRepo repositories.TournamentPlacementRepository
+	uploader      storage.FileUploader
+	images        *imaging.Processor
 }
 
 func NewTeamService(
 	teamRepo repositories.TeamRepository,
 	userRepo repositories.UserRepository,
 	sportRepo repositories.SportRepository,
+	placementRepo repositories.TournamentPlacementRepository,
 	uploader storage.FileUploader,
+	images *imaging.Processor,
 ) TeamService {
 	return &teamService{
-		teamRepo:  teamRepo,
-		userRepo:  userRepo,
-		sportRepo: sportRepo,
-		uploader:  uploader,
+		teamRepo:      teamRepo,
+		userRepo:      userRepo,
+		sportRepo:     sportRepo,
+		placementRepo: placementRepo,
+		uploader:      uploader,
+		images:        images,
 	}
 }
 
@@ -133,6 +140,12 @@ func (s *teamService) GetTeamByID(ctx context.Context, teamID int) (*models.Team
 		return nil, fmt.Errorf("failed to get team by id %d: %w", teamID, err)
 	}
 	s.populateTeamLogoURL(team)
+
+	placements, err := s.placementRepo.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements for team %d: %w", teamID, err)
+	}
+	team.Placements = placements
 	return team, nil
 }
 
@@ -272,7 +285,7 @@ func (s *teamService) DeleteTeam(ctx context.Context, teamID int, currentUserID
 		go func(keyToDelete string) {
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				fmt.Printf("Warning: Failed to delete team logo %s during team deletion: %v\n", keyToDelete, deleteErr)
 			}
 		}(*team.LogoKey)
@@ -300,21 +313,14 @@ func (s *teamService) UploadLogo(ctx context.Context, teamID int, currentUserID
 
 	oldLogoKey := team.LogoKey
 
-	ext, err := GetExtensionFromContentType(contentType)
+	newKey, err := uploadLogoFunc(ctx, s.uploader, s.images, fmt.Sprintf("%s/%d/logo_%d", teamLogoPrefix, teamID, time.Now().UnixNano()), file)
 	if err != nil {
 		return nil, err
 	}
 
-	newKey := fmt.Sprintf("%s/%d/logo_%d%s", teamLogoPrefix, teamID, time.Now().UnixNano(), ext)
-
-	_, err = s.uploader.Upload(ctx, newKey, contentType, file)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s: %w", ErrLogoUploadFailed, newKey, err)
-	}
-
 	err = s.teamRepo.UpdateLogoKey(ctx, teamID, &newKey)
 	if err != nil {
-		if deleteErr := s.uploader.Delete(context.Background(), newKey); deleteErr != nil {
+		if deleteErr := deleteLogoFunc(context.Background(), s.uploader, newKey); deleteErr != nil {
 			fmt.Printf("CRITICAL: Failed to delete uploaded team logo %s after DB update error: %v. DB error: %v\n", newKey, deleteErr, err)
 		}
 		if errors.Is(err, repositories.ErrTeamNotFound) {
@@ -327,7 +333,7 @@ func (s *teamService) UploadLogo(ctx context.Context, teamID int, currentUserID
 		go func(keyToDelete string) {
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				fmt.Printf("Warning: Failed to delete old team logo %s: %v\n", keyToDelete, deleteErr)
 			}
 		}(*oldLogoKey)
@@ -340,9 +346,6 @@ func (s *teamService) UploadLogo(ctx context.Context, teamID int, currentUserID
 
 func (s *teamService) populateTeamLogoURL(team *models.Team) {
 	if team != nil && team.LogoKey != nil && *team.LogoKey != "" {
-		url := s.uploader.GetPublicURL(*team.LogoKey)
-		if url != "" {
-			team.LogoURL = &url
-		}
+		team.LogoURL, team.LogoURLs = logoURLsFunc(s.uploader, team.LogoKey)
 	}
 }
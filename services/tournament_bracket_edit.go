@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dosada05/tournament-system/brackets"
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+var (
+	ErrBracketEditNotAllowed         = errors.New("bracket can only be edited while the tournament is active")
+	ErrBracketEditUnsupported        = errors.New("manual bracket editing is only supported for SingleElimination brackets")
+	ErrBracketEditMatchPlayed        = errors.New("bracket edits are only allowed on unplayed matches")
+	ErrBracketEditInvalidSlot        = errors.New("invalid bracket slot")
+	ErrBracketEditSlotFed            = errors.New("slot is filled by the winner of an earlier match")
+	ErrBracketEditInvalidParticipant = errors.New("participant cannot be placed in the bracket")
+)
+
+// BracketSlotRef — позиция в сетке: матч и слот (1 или 2).
+type BracketSlotRef struct {
+	MatchID int `json:"match_id"`
+	Slot    int `json:"slot"`
+}
+
+type SwapBracketSlotsInput struct {
+	First  BracketSlotRef `json:"first"`
+	Second BracketSlotRef `json:"second"`
+}
+
+type ReplaceBracketParticipantInput struct {
+	BracketSlotRef
+	ParticipantID int `json:"participant_id"`
+}
+
+type SetBracketByeInput struct {
+	MatchID       int `json:"match_id"`
+	AdvancingSlot int `json:"advancing_slot"` // Слот участника, который проходит дальше без игры
+}
+
+// bracketSlotMatch — общее представление solo/team матча для правки сетки.
+type bracketSlotMatch struct {
+	ID                  int
+	Status              models.MatchStatus
+	WinnerParticipantID *int
+	Participants        [2]*int
+	loaded              [2]*int // Участники на момент чтения: запись пройдёт, только если они не изменились
+	NextMatchDBID       *int
+	WinnerToSlot        *int
+}
+
+func (m *bracketSlotMatch) unplayed() bool {
+	return m.Status == models.StatusScheduled && m.WinnerParticipantID == nil
+}
+
+// bracketLayout — матчи турнира и слоты, которые заполняются победителями предыдущих матчей.
+type bracketLayout struct {
+	tournament *models.Tournament
+	matches    map[int]*bracketSlotMatch
+	fed        map[BracketSlotRef]int // слот -> ID матча-источника
+}
+
+func (l *bracketLayout) match(matchID int) (*bracketSlotMatch, error) {
+	m, ok := l.matches[matchID]
+	if !ok {
+		return nil, fmt.Errorf("%w: match %d is not part of tournament %d", ErrBracketEditInvalidSlot, matchID, l.tournament.ID)
+	}
+	return m, nil
+}
+
+// editableSlot проверяет, что слот существует, занят участником, не зависит от другого матча и матч ещё не сыгран.
+func (l *bracketLayout) editableSlot(ref BracketSlotRef) (*bracketSlotMatch, error) {
+	if ref.Slot != 1 && ref.Slot != 2 {
+		return nil, fmt.Errorf("%w: slot must be 1 or 2, got %d", ErrBracketEditInvalidSlot, ref.Slot)
+	}
+	m, err := l.match(ref.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	if !m.unplayed() {
+		return nil, fmt.Errorf("%w: match %d is '%s'", ErrBracketEditMatchPlayed, m.ID, m.Status)
+	}
+	if sourceID, ok := l.fed[ref]; ok {
+		return nil, fmt.Errorf("%w: slot %d of match %d comes from match %d", ErrBracketEditSlotFed, ref.Slot, m.ID, sourceID)
+	}
+	if m.Participants[ref.Slot-1] == nil {
+		return nil, fmt.Errorf("%w: slot %d of match %d is empty", ErrBracketEditInvalidSlot, ref.Slot, m.ID)
+	}
+	return m, nil
+}
+
+func (l *bracketLayout) contains(participantID int) bool {
+	for _, m := range l.matches {
+		for _, p := range m.Participants {
+			if p != nil && *p == participantID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadBracketLayout загружает турнир и его сгенерированную сетку, проверяя права и статус.
+func (s *tournamentService) loadBracketLayout(ctx context.Context, tournamentID int, currentUserID int) (*bracketLayout, error) {
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return nil, err
+	}
+	if tournament.Status != models.StatusActive {
+		return nil, fmt.Errorf("%w: tournament %d is '%s'", ErrBracketEditNotAllowed, tournamentID, tournament.Status)
+	}
+	if tournament.Format == nil {
+		format, err := s.formatRepo.GetByID(ctx, tournament.FormatID)
+		if err != nil {
+			return nil, handleRepositoryError(err, ErrTournamentFormatNotFound, "failed to get format %d for tournament %d", tournament.FormatID, tournamentID)
+		}
+		tournament.Format = format
+	}
+	if tournament.Format.BracketType != "SingleElimination" {
+		return nil, ErrBracketEditUnsupported
+	}
+
+	layout := &bracketLayout{
+		tournament: tournament,
+		matches:    make(map[int]*bracketSlotMatch),
+		fed:        make(map[BracketSlotRef]int),
+	}
+	if tournament.Format.ParticipantType == models.FormatParticipantSolo {
+		matches, err := s.soloMatchRepo.ListByTournament(ctx, tournamentID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list solo matches for tournament %d: %w", tournamentID, err)
+		}
+		for _, m := range matches {
+			layout.matches[m.ID] = &bracketSlotMatch{ID: m.ID, Status: m.Status, WinnerParticipantID: m.WinnerParticipantID,
+				Participants: [2]*int{m.P1ParticipantID, m.P2ParticipantID}, loaded: [2]*int{m.P1ParticipantID, m.P2ParticipantID},
+				NextMatchDBID: m.NextMatchDBID, WinnerToSlot: m.WinnerToSlot}
+		}
+	} else {
+		matches, err := s.teamMatchRepo.ListByTournament(ctx, tournamentID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list team matches for tournament %d: %w", tournamentID, err)
+		}
+		for _, m := range matches {
+			layout.matches[m.ID] = &bracketSlotMatch{ID: m.ID, Status: m.Status, WinnerParticipantID: m.WinnerParticipantID,
+				Participants: [2]*int{m.T1ParticipantID, m.T2ParticipantID}, loaded: [2]*int{m.T1ParticipantID, m.T2ParticipantID},
+				NextMatchDBID: m.NextMatchDBID, WinnerToSlot: m.WinnerToSlot}
+		}
+	}
+	for _, m := range layout.matches {
+		if m.NextMatchDBID != nil && m.WinnerToSlot != nil {
+			layout.fed[BracketSlotRef{MatchID: *m.NextMatchDBID, Slot: *m.WinnerToSlot}] = m.ID
+		}
+	}
+	return layout, nil
+}
+
+// updateBracketMatchParticipants записывает участников матча, только если с момента loadBracketLayout
+// матч не начался, не получил результат и в его слоты никто не продвинулся.
+func (s *tournamentService) updateBracketMatchParticipants(ctx context.Context, tx repositories.SQLExecutor, tournament *models.Tournament, m *bracketSlotMatch) error {
+	var err error
+	if tournament.Format.ParticipantType == models.FormatParticipantSolo {
+		err = s.soloMatchRepo.UpdateUnplayedParticipants(ctx, tx, m.ID, m.loaded[0], m.loaded[1], m.Participants[0], m.Participants[1])
+	} else {
+		err = s.teamMatchRepo.UpdateUnplayedParticipants(ctx, tx, m.ID, m.loaded[0], m.loaded[1], m.Participants[0], m.Participants[1])
+	}
+	return bracketEditConflict(err, m.ID)
+}
+
+func (s *tournamentService) deleteBracketMatch(ctx context.Context, tx repositories.SQLExecutor, tournament *models.Tournament, matchID int) error {
+	var err error
+	if tournament.Format.ParticipantType == models.FormatParticipantSolo {
+		err = s.soloMatchRepo.DeleteUnplayed(ctx, tx, matchID)
+	} else {
+		err = s.teamMatchRepo.DeleteUnplayed(ctx, tx, matchID)
+	}
+	return bracketEditConflict(err, matchID)
+}
+
+// bracketEditConflict превращает несработавшую условную запись в ErrBracketEditMatchPlayed.
+func bracketEditConflict(err error, matchID int) error {
+	if errors.Is(err, repositories.ErrSoloMatchStatusConflict) || errors.Is(err, repositories.ErrTeamMatchStatusConflict) {
+		return fmt.Errorf("%w: match %d was started, played or changed concurrently", ErrBracketEditMatchPlayed, matchID)
+	}
+	return err
+}
+
+// applyBracketEdit сохраняет изменённые матчи в одной транзакции и рассылает BRACKET_UPDATED.
+// extra, если задан, выполняется в той же транзакции до изменения матчей.
+func (s *tournamentService) applyBracketEdit(ctx context.Context, layout *bracketLayout, changed []*bracketSlotMatch, deleted []int, extra func(tx repositories.SQLExecutor) error) (*FullTournamentBracketView, error) {
+	tournament := layout.tournament
+	err := runInTransaction(ctx, s.db, s.logger, func(tx repositories.SQLExecutor) error {
+		if extra != nil {
+			if err := extra(tx); err != nil {
+				return err
+			}
+		}
+		for _, m := range changed {
+			if err := s.updateBracketMatchParticipants(ctx, tx, tournament, m); err != nil {
+				return fmt.Errorf("failed to update participants of match %d: %w", m.ID, err)
+			}
+		}
+		for _, matchID := range deleted {
+			if err := s.deleteBracketMatch(ctx, tx, tournament, matchID); err != nil {
+				return fmt.Errorf("failed to delete match %d: %w", matchID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.GetTournamentBracketData(ctx, tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.hub != nil {
+		s.hub.Publish(brackets.TournamentTopic(tournament.ID), brackets.EventBracketUpdated, view)
+	}
+	s.emitWebhook(ctx, models.WebhookBracketUpdated, tournament, view)
+	return view, nil
+}
+
+// SwapBracketSlots меняет местами участников двух слотов (в том числе внутри одного матча).
+func (s *tournamentService) SwapBracketSlots(ctx context.Context, tournamentID int, currentUserID int, input SwapBracketSlotsInput) (*FullTournamentBracketView, error) {
+	if input.First == input.Second {
+		return nil, fmt.Errorf("%w: cannot swap a slot with itself", ErrBracketEditInvalidSlot)
+	}
+	layout, err := s.loadBracketLayout(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	first, err := layout.editableSlot(input.First)
+	if err != nil {
+		return nil, err
+	}
+	second, err := layout.editableSlot(input.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	i, j := input.First.Slot-1, input.Second.Slot-1
+	first.Participants[i], second.Participants[j] = second.Participants[j], first.Participants[i]
+
+	changed := []*bracketSlotMatch{first}
+	if second != first {
+		changed = append(changed, second)
+	}
+	s.logger.InfoContext(ctx, "Swapping bracket slots", slog.Int("tournament_id", tournamentID), slog.Any("first", input.First), slog.Any("second", input.Second), slog.Int("user_id", currentUserID))
+	return s.applyBracketEdit(ctx, layout, changed, nil, nil)
+}
+
+// ReplaceBracketParticipant ставит в слот другого участника турнира (например, запасного вместо снявшегося).
+// Поданная заявка запасного при этом подтверждается.
+func (s *tournamentService) ReplaceBracketParticipant(ctx context.Context, tournamentID int, currentUserID int, input ReplaceBracketParticipantInput) (*FullTournamentBracketView, error) {
+	layout, err := s.loadBracketLayout(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := layout.editableSlot(input.BracketSlotRef)
+	if err != nil {
+		return nil, err
+	}
+
+	alternate, err := s.participantRepo.FindByID(ctx, input.ParticipantID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrParticipantNotFound, "failed to get participant %d", input.ParticipantID)
+	}
+	if alternate.TournamentID != tournamentID {
+		return nil, fmt.Errorf("%w: participant %d belongs to another tournament", ErrBracketEditInvalidParticipant, alternate.ID)
+	}
+	if alternate.Status != models.StatusParticipant && alternate.Status != models.StatusApplicationSubmitted {
+		return nil, fmt.Errorf("%w: participant %d has status '%s'", ErrBracketEditInvalidParticipant, alternate.ID, alternate.Status)
+	}
+	if layout.contains(alternate.ID) {
+		return nil, fmt.Errorf("%w: participant %d is already in the bracket", ErrBracketEditInvalidParticipant, alternate.ID)
+	}
+
+	// Заявка подтверждается в той же транзакции, что и правка слота: иначе при сбое
+	// запасной остался бы подтверждённым, но вне сетки.
+	var confirmAlternate func(tx repositories.SQLExecutor) error
+	if alternate.Status == models.StatusApplicationSubmitted {
+		confirmAlternate = func(tx repositories.SQLExecutor) error {
+			if err := s.participantRepo.UpdateStatus(ctx, tx, alternate.ID, models.StatusParticipant); err != nil {
+				return fmt.Errorf("failed to confirm participant %d: %w", alternate.ID, err)
+			}
+			return nil
+		}
+	}
+
+	replacedID := *m.Participants[input.Slot-1]
+	alternateID := alternate.ID
+	m.Participants[input.Slot-1] = &alternateID
+	s.logger.InfoContext(ctx, "Replacing bracket participant", slog.Int("tournament_id", tournamentID), slog.Int("match_id", m.ID), slog.Int("replaced_participant_id", replacedID), slog.Int("participant_id", alternateID), slog.Int("user_id", currentUserID))
+
+	return s.applyBracketEdit(ctx, layout, []*bracketSlotMatch{m}, nil, confirmAlternate)
+}
+
+// SetBracketBye проводит участника дальше без игры: он занимает слот следующего матча,
+// а сам матч удаляется — так же генератор хранит bye. Слот соперника должен быть пуст.
+func (s *tournamentService) SetBracketBye(ctx context.Context, tournamentID int, currentUserID int, input SetBracketByeInput) (*FullTournamentBracketView, error) {
+	layout, err := s.loadBracketLayout(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := layout.editableSlot(BracketSlotRef{MatchID: input.MatchID, Slot: input.AdvancingSlot})
+	if err != nil {
+		return nil, err
+	}
+	otherSlot := BracketSlotRef{MatchID: m.ID, Slot: 3 - input.AdvancingSlot}
+	if sourceID, ok := layout.fed[otherSlot]; ok {
+		return nil, fmt.Errorf("%w: opponent slot of match %d comes from match %d", ErrBracketEditSlotFed, m.ID, sourceID)
+	}
+	// Иначе соперник молча исчез бы из сетки вместе с матчем — это не bye, а снятие с турнира.
+	if opponentID := m.Participants[otherSlot.Slot-1]; opponentID != nil {
+		return nil, fmt.Errorf("%w: opponent slot %d of match %d holds participant %d", ErrBracketEditInvalidSlot, otherSlot.Slot, m.ID, *opponentID)
+	}
+	if m.NextMatchDBID == nil || m.WinnerToSlot == nil {
+		return nil, fmt.Errorf("%w: match %d has no next match to advance to", ErrBracketEditInvalidSlot, m.ID)
+	}
+	next, err := layout.match(*m.NextMatchDBID)
+	if err != nil {
+		return nil, err
+	}
+	if !next.unplayed() {
+		return nil, fmt.Errorf("%w: next match %d is '%s'", ErrBracketEditMatchPlayed, next.ID, next.Status)
+	}
+	if next.Participants[*m.WinnerToSlot-1] != nil {
+		return nil, fmt.Errorf("%w: slot %d of next match %d is already taken", ErrBracketEditInvalidSlot, *m.WinnerToSlot, next.ID)
+	}
+
+	next.Participants[*m.WinnerToSlot-1] = m.Participants[input.AdvancingSlot-1]
+	s.logger.InfoContext(ctx, "Setting bracket bye", slog.Int("tournament_id", tournamentID), slog.Int("match_id", m.ID), slog.Int("next_match_id", next.ID), slog.Int("user_id", currentUserID))
+	return s.applyBracketEdit(ctx, layout, []*bracketSlotMatch{next}, []int{m.ID}, nil)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dosada05/tournament-system/models"
+)
+
+// loadPreviewableTournament загружает турнир с форматом и проверяет, что сетку ещё можно менять.
+func (s *tournamentService) loadPreviewableTournament(ctx context.Context, tournamentID int, currentUserID int) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return nil, err
+	}
+	if tournament.Status != models.StatusSoon && tournament.Status != models.StatusRegistration {
+		return nil, fmt.Errorf("%w: tournament %d is '%s'", ErrBracketPreviewNotAllowed, tournamentID, tournament.Status)
+	}
+	if tournament.Format == nil {
+		format, err := s.formatRepo.GetByID(ctx, tournament.FormatID)
+		if err != nil {
+			return nil, handleRepositoryError(err, ErrTournamentFormatNotFound, "failed to get format %d for tournament %d", tournament.FormatID, tournamentID)
+		}
+		tournament.Format = format
+	}
+	return tournament, nil
+}
+
+func (s *tournamentService) PreviewBracket(ctx context.Context, tournamentID int, currentUserID int, input BracketPreviewInput) (*models.BracketPreview, error) {
+	tournament, err := s.loadPreviewableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.bracketService.PreviewBracket(ctx, tournament, input)
+}
+
+func (s *tournamentService) LockBracketPreview(ctx context.Context, tournamentID int, currentUserID int, input BracketPreviewInput) (*models.BracketPreview, error) {
+	tournament, err := s.loadPreviewableTournament(ctx, tournamentID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.bracketService.LockBracketPreview(ctx, tournament, currentUserID, input)
+}
+
+func (s *tournamentService) GetLockedBracketPreview(ctx context.Context, tournamentID int, currentUserID int) (*models.BracketPreview, error) {
+	tournament, err := s.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d", tournamentID)
+	}
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return nil, err
+	}
+	return s.bracketService.GetLockedBracketPreview(ctx, tournamentID)
+}
+
+func (s *tournamentService) UnlockBracketPreview(ctx context.Context, tournamentID int, currentUserID int) error {
+	if _, err := s.loadPreviewableTournament(ctx, tournamentID, currentUserID); err != nil {
+		return err
+	}
+	return s.bracketService.UnlockBracketPreview(ctx, tournamentID)
+}
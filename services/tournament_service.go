@@ -15,8 +15,10 @@ import (
 
 	"github.com/Dosada05/tournament-system/brackets"
 	"github.com/Dosada05/tournament-system/db"
+	"github.com/Dosada05/tournament-system/imaging"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/repositories"
+	"github.com/Dosada05/tournament-system/scoring"
 	"github.com/Dosada05/tournament-system/storage"
 	"golang.org/x/sync/errgroup"
 )
@@ -54,6 +56,12 @@ type FullTournamentBracketView struct {
 	ParticipantsMap            map[int]ParticipantView    `json:"participants_map,omitempty"`
 	OverallWinnerParticipantID *int                       `json:"overall_winner_participant_id,omitempty"`
 	TournamentSettings         *models.RoundRobinSettings `json:"tournament_settings,omitempty"` // Parsed settings for RR
+	Placements                 []PlacementView            `json:"placements,omitempty"`          // Final placements once completed
+}
+
+type PlacementView struct {
+	Placement   int             `json:"placement"`
+	Participant ParticipantView `json:"participant"`
 }
 
 type RoundView struct {
@@ -62,30 +70,32 @@ type RoundView struct {
 }
 
 type MatchView struct {
-	MatchID               int                `json:"match_id"`
-	BracketMatchUID       *string            `json:"bracket_match_uid,omitempty"`
-	Status                models.MatchStatus `json:"status"`
-	Round                 int                `json:"round"`
-	OrderInRound          int                `json:"order_in_round"`
-	Participant1          *ParticipantView   `json:"participant1,omitempty"`
-	Participant2          *ParticipantView   `json:"participant2,omitempty"`
-	ScoreP1               *int               `json:"score_p1,omitempty"` // Populated from ScoreString if applicable
-	ScoreP2               *int               `json:"score_p2,omitempty"` // Populated from ScoreString if applicable
-	IsDraw                bool               `json:"is_draw"`            // Indicates if the match was a draw
-	ScoreString           *string            `json:"score_string,omitempty"`
-	WinnerParticipantDBID *int               `json:"winner_participant_db_id,omitempty"`
-	NextMatchDBID         *int               `json:"next_match_db_id,omitempty"`
-	WinnerToSlot          *int               `json:"winner_to_slot,omitempty"`
-	MatchTime             time.Time          `json:"match_time"`
+	MatchID               int                 `json:"match_id"`
+	BracketMatchUID       *string             `json:"bracket_match_uid,omitempty"`
+	Status                models.MatchStatus  `json:"status"`
+	Round                 int                 `json:"round"`
+	OrderInRound          int                 `json:"order_in_round"`
+	Participant1          *ParticipantView    `json:"participant1,omitempty"`
+	Participant2          *ParticipantView    `json:"participant2,omitempty"`
+	ScoreP1               *int                `json:"score_p1,omitempty"` // Populated from ScoreString if applicable
+	ScoreP2               *int                `json:"score_p2,omitempty"` // Populated from ScoreString if applicable
+	IsDraw                bool                `json:"is_draw"`            // Indicates if the match was a draw
+	ScoreString           *string             `json:"score_string,omitempty"`
+	WinnerParticipantDBID *int                `json:"winner_participant_db_id,omitempty"`
+	NextMatchDBID         *int                `json:"next_match_db_id,omitempty"`
+	WinnerToSlot          *int                `json:"winner_to_slot,omitempty"`
+	MatchTime             time.Time           `json:"match_time"`
+	Events                []models.MatchEvent `json:"events,omitempty"` // Хронология матча
 }
 
 type ParticipantView struct {
-	ParticipantDBID int     `json:"participant_db_id"`
-	Type            string  `json:"type"` // "team" or "user"
-	Name            string  `json:"name"`
-	LogoURL         *string `json:"logo_url,omitempty"`
-	OriginalUserID  *int    `json:"original_user_id,omitempty"`
-	OriginalTeamID  *int    `json:"original_team_id,omitempty"`
+	ParticipantDBID int              `json:"participant_db_id"`
+	Type            string           `json:"type"` // "team" or "user"
+	Name            string           `json:"name"`
+	LogoURL         *string          `json:"logo_url,omitempty"`
+	LogoURLs        *models.LogoURLs `json:"logo_urls,omitempty"`
+	OriginalUserID  *int             `json:"original_user_id,omitempty"`
+	OriginalTeamID  *int             `json:"original_team_id,omitempty"`
 }
 
 // TournamentStandingView for API response
@@ -112,6 +122,8 @@ type CreateTournamentInput struct {
 	EndDate         time.Time `json:"end_date" validate:"required"`
 	Location        *string   `json:"location"`
 	MaxParticipants int       `json:"max_participants" validate:"required,gt=0"`
+	OrganizationID  *int      `json:"organization_id"`
+	SeedByRating    bool      `json:"seed_by_rating"`
 }
 
 type UpdateTournamentDetailsInput struct {
@@ -122,15 +134,17 @@ type UpdateTournamentDetailsInput struct {
 	EndDate         *time.Time `json:"end_date"`
 	Location        *string    `json:"location"`
 	MaxParticipants *int       `json:"max_participants" validate:"omitempty,gt=0"`
+	SeedByRating    *bool      `json:"seed_by_rating"`
 }
 
 type ListTournamentsFilter struct {
-	SportID     *int
-	FormatID    *int
-	OrganizerID *int
-	Status      *models.TournamentStatus
-	Limit       int
-	Offset      int
+	SportID        *int
+	FormatID       *int
+	OrganizerID    *int
+	OrganizationID *int
+	Status         *models.TournamentStatus
+	Limit          int
+	Offset         int
 }
 
 type TournamentService interface {
@@ -144,6 +158,13 @@ type TournamentService interface {
 	FinalizeTournament(ctx context.Context, tournamentID int, winnerParticipantDBID *int, currentUserID int) (*models.Tournament, error)
 	GetTournamentBracketData(ctx context.Context, tournamentID int) (*FullTournamentBracketView, error)
 	AutoUpdateTournamentStatusesByDates(ctx context.Context) error
+	PreviewBracket(ctx context.Context, tournamentID int, currentUserID int, input BracketPreviewInput) (*models.BracketPreview, error)
+	LockBracketPreview(ctx context.Context, tournamentID int, currentUserID int, input BracketPreviewInput) (*models.BracketPreview, error)
+	GetLockedBracketPreview(ctx context.Context, tournamentID int, currentUserID int) (*models.BracketPreview, error)
+	UnlockBracketPreview(ctx context.Context, tournamentID int, currentUserID int) error
+	SwapBracketSlots(ctx context.Context, tournamentID int, currentUserID int, input SwapBracketSlotsInput) (*FullTournamentBracketView, error)
+	ReplaceBracketParticipant(ctx context.Context, tournamentID int, currentUserID int, input ReplaceBracketParticipantInput) (*FullTournamentBracketView, error)
+	SetBracketBye(ctx context.Context, tournamentID int, currentUserID int, input SetBracketByeInput) (*FullTournamentBracketView, error)
 }
 
 type tournamentService struct {
@@ -156,9 +177,15 @@ type tournamentService struct {
 	soloMatchRepo   repositories.SoloMatchRepository
 	teamMatchRepo   repositories.TeamMatchRepository
 	standingRepo    repositories.TournamentStandingRepository
+	orgRepo         repositories.OrganizationRepository
+	placementRepo   repositories.TournamentPlacementRepository
 	bracketService  BracketService
 	matchService    MatchService
+	seasonService   SeasonService
 	uploader        storage.FileUploader
+	images          *imaging.Processor
+	notifier        NotificationService
+	webhooks        WebhookService
 	hub             *brackets.Hub
 	logger          *slog.Logger
 }
@@ -173,9 +200,15 @@ func NewTournamentService(
 	soloMatchRepo repositories.SoloMatchRepository,
 	teamMatchRepo repositories.TeamMatchRepository,
 	standingRepo repositories.TournamentStandingRepository, // Added
+	orgRepo repositories.OrganizationRepository,
+	placementRepo repositories.TournamentPlacementRepository,
 	bracketService BracketService,
 	matchService MatchService,
+	seasonService SeasonService,
 	uploader storage.FileUploader,
+	images *imaging.Processor,
+	notifier NotificationService,
+	webhooks WebhookService,
 	hub *brackets.Hub,
 	logger *slog.Logger,
 ) TournamentService {
@@ -189,9 +222,15 @@ func NewTournamentService(
 		soloMatchRepo:   soloMatchRepo,
 		teamMatchRepo:   teamMatchRepo,
 		standingRepo:    standingRepo,
+		orgRepo:         orgRepo,
+		placementRepo:   placementRepo,
 		bracketService:  bracketService,
 		matchService:    matchService,
+		seasonService:   seasonService,
 		uploader:        uploader,
+		images:          images,
+		notifier:        notifier,
+		webhooks:        webhooks,
 		hub:             hub,
 		logger:          logger,
 	}
@@ -221,6 +260,18 @@ func (s *tournamentService) CreateTournament(ctx context.Context, organizerID in
 	if err != nil {
 		return nil, handleRepositoryError(err, ErrTournamentOrganizerNotFound, "failed to verify organizer %d", organizerID)
 	}
+	if input.OrganizationID != nil {
+		member, memberErr := s.orgRepo.GetMember(ctx, *input.OrganizationID, organizerID)
+		if memberErr != nil {
+			if errors.Is(memberErr, repositories.ErrOrganizationMemberNotFound) {
+				return nil, fmt.Errorf("%w: not a member of organization %d", ErrForbiddenOperation, *input.OrganizationID)
+			}
+			return nil, fmt.Errorf("failed to verify organization %d membership: %w", *input.OrganizationID, memberErr)
+		}
+		if !member.Role.CanManage() {
+			return nil, fmt.Errorf("%w: organization role '%s' cannot create tournaments", ErrForbiddenOperation, member.Role)
+		}
+	}
 
 	tournament := &models.Tournament{
 		Name:            name,
@@ -234,6 +285,8 @@ func (s *tournamentService) CreateTournament(ctx context.Context, organizerID in
 		Location:        input.Location,
 		MaxParticipants: input.MaxParticipants,
 		Status:          models.StatusSoon,
+		OrganizationID:  input.OrganizationID,
+		SeedByRating:    input.SeedByRating,
 	}
 
 	err = s.tournamentRepo.Create(ctx, tournament)
@@ -311,12 +364,13 @@ func (s *tournamentService) GetTournamentByID(ctx context.Context, id int, curre
 
 func (s *tournamentService) ListTournaments(ctx context.Context, filter ListTournamentsFilter) ([]models.Tournament, error) {
 	repoFilter := repositories.ListTournamentsFilter{
-		SportID:     filter.SportID,
-		FormatID:    filter.FormatID,
-		OrganizerID: filter.OrganizerID,
-		Status:      filter.Status,
-		Limit:       filter.Limit,
-		Offset:      filter.Offset,
+		SportID:        filter.SportID,
+		FormatID:       filter.FormatID,
+		OrganizerID:    filter.OrganizerID,
+		OrganizationID: filter.OrganizationID,
+		Status:         filter.Status,
+		Limit:          filter.Limit,
+		Offset:         filter.Offset,
 	}
 	tournaments, err := s.tournamentRepo.List(ctx, repoFilter)
 	if err != nil {
@@ -337,8 +391,8 @@ func (s *tournamentService) UpdateTournamentDetails(ctx context.Context, id int,
 		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d for update", id)
 	}
 
-	if tournament.OrganizerID != currentUserID {
-		return nil, ErrForbiddenOperation
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return nil, err
 	}
 
 	if tournament.Status != models.StatusSoon && tournament.Status != models.StatusRegistration {
@@ -393,6 +447,10 @@ func (s *tournamentService) UpdateTournamentDetails(ctx context.Context, id int,
 		tournament.MaxParticipants = *input.MaxParticipants
 		updated = true
 	}
+	if input.SeedByRating != nil && *input.SeedByRating != tournament.SeedByRating {
+		tournament.SeedByRating = *input.SeedByRating
+		updated = true
+	}
 
 	if !updated {
 		s.populateTournamentDetails(ctx, tournament)
@@ -421,8 +479,10 @@ func (s *tournamentService) UpdateTournamentStatus(ctx context.Context, id int,
 		return nil, handleRepositoryError(err, ErrTournamentNotFound, "UpdateTournamentStatus: failed to get tournament %d", id)
 	}
 
-	if currentUserID != 0 && tournament.OrganizerID != currentUserID {
-		return nil, ErrForbiddenOperation
+	if currentUserID != 0 {
+		if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+			return nil, err
+		}
 	}
 
 	// 3. Load format if not already loaded and needed (especially for 'active' transition)
@@ -535,24 +595,31 @@ func (s *tournamentService) UpdateTournamentStatus(ctx context.Context, id int,
 		ownTx = nil // Mark as committed
 		s.logger.InfoContext(ctx, "Own transaction committed successfully", slog.Int("tournament_id", id))
 
-		// Send WebSocket notifications only after successful commit of an owned transaction
+		// Send WebSocket notifications and webhooks only after successful commit of an owned transaction
+		statusPayload := brackets.TournamentStatusPayload{TournamentID: tournament.ID, NewStatus: newStatus, OldStatus: currentStatus}
+		var fullBracketData *FullTournamentBracketView
+		if newStatus == models.StatusActive && currentStatus != models.StatusActive && (s.hub != nil || s.webhooks != nil) {
+			var errData error
+			fullBracketData, errData = s.GetTournamentBracketData(ctx, tournament.ID)
+			if errData != nil {
+				s.logger.WarnContext(ctx, "Failed to get full bracket data for broadcast after status update", slog.Int("tournament_id", id), slog.Any("error", errData))
+			}
+		}
 		if s.hub != nil {
-			roomID := "tournament_" + strconv.Itoa(tournament.ID)
-			statusPayload := map[string]interface{}{"tournament_id": tournament.ID, "new_status": newStatus, "old_status": currentStatus}
-			s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "TOURNAMENT_STATUS_UPDATED", Payload: statusPayload, RoomID: roomID})
-
-			if newStatus == models.StatusActive && currentStatus != models.StatusActive {
-				fullBracketData, errData := s.GetTournamentBracketData(ctx, tournament.ID)
-				if errData == nil {
-					s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "BRACKET_UPDATED", Payload: fullBracketData, RoomID: roomID})
-					if tournament.Format != nil && tournament.Format.BracketType == "RoundRobin" {
-						s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "STANDINGS_UPDATED", Payload: fullBracketData.Standings, RoomID: roomID})
-					}
-				} else {
-					s.logger.WarnContext(ctx, "Failed to get full bracket data for WebSocket broadcast after status update", slog.Int("tournament_id", id), slog.Any("error", errData))
+			topic := brackets.TournamentTopic(tournament.ID)
+			s.hub.Publish(topic, brackets.EventTournamentStatusUpdated, statusPayload)
+			if fullBracketData != nil {
+				s.hub.Publish(topic, brackets.EventBracketUpdated, fullBracketData)
+				if tournament.Format != nil && tournament.Format.BracketType == "RoundRobin" {
+					s.hub.Publish(topic, brackets.EventStandingsUpdated, fullBracketData.Standings)
 				}
 			}
 		}
+		s.emitWebhook(ctx, models.WebhookTournamentStatusChange, tournament, statusPayload)
+		if fullBracketData != nil {
+			s.emitWebhook(ctx, models.WebhookBracketUpdated, tournament, fullBracketData)
+		}
+		s.notifyStatusChanged(ctx, tournament)
 	}
 
 	updatedTournament, fetchErr := s.GetTournamentByID(ctx, id, 0)
@@ -570,8 +637,8 @@ func (s *tournamentService) DeleteTournament(ctx context.Context, id int, curren
 		return handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d for deletion check", id)
 	}
 
-	if tournament.OrganizerID != currentUserID { // Add admin role check if necessary
-		return ErrForbiddenOperation
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return err
 	}
 
 	// Allow deletion only for 'soon', 'registration', or 'canceled' tournaments
@@ -598,7 +665,7 @@ func (s *tournamentService) DeleteTournament(ctx context.Context, id int, curren
 		go func(keyToDelete string) { // Asynchronous deletion
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				s.logger.WarnContext(context.Background(), "Failed to delete tournament logo from storage",
 					slog.String("key", keyToDelete), slog.Int("tournament_id", id), slog.Any("error", deleteErr))
 			}
@@ -613,8 +680,8 @@ func (s *tournamentService) UploadTournamentLogo(ctx context.Context, tournament
 		return nil, handleRepositoryError(err, ErrTournamentNotFound, "failed to get tournament %d for logo upload", tournamentID)
 	}
 
-	if tournament.OrganizerID != currentUserID { // Add admin role check if necessary
-		return nil, ErrForbiddenOperation
+	if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+		return nil, err
 	}
 	// Potentially check tournament status to disallow logo changes for active/completed tournaments
 
@@ -626,21 +693,16 @@ func (s *tournamentService) UploadTournamentLogo(ctx context.Context, tournament
 	}
 
 	oldLogoKey := tournament.LogoKey
-	ext, errExt := GetExtensionFromContentType(contentType) // Using helper
-	if errExt != nil {
-		return nil, errExt
-	}
-	newKey := fmt.Sprintf("%s/%d/logo_%d%s", tournamentLogoPrefix, tournamentID, time.Now().UnixNano(), ext)
-
-	if _, errUpload := s.uploader.Upload(ctx, newKey, contentType, file); errUpload != nil {
-		return nil, fmt.Errorf("%w: %s: %w", ErrLogoUploadFailed, newKey, errUpload)
+	newKey, errUpload := uploadLogoFunc(ctx, s.uploader, s.images, fmt.Sprintf("%s/%d/logo_%d", tournamentLogoPrefix, tournamentID, time.Now().UnixNano()), file)
+	if errUpload != nil {
+		return nil, errUpload
 	}
 
 	if errDbUpdate := s.tournamentRepo.UpdateLogoKey(ctx, tournamentID, &newKey); errDbUpdate != nil {
 		// Attempt to clean up the newly uploaded file if DB update fails
 		go func(keyToDelete string) {
 			s.logger.InfoContext(context.Background(), "Attempting to delete orphaned logo from storage", slog.String("key", keyToDelete))
-			if delErr := s.uploader.Delete(context.Background(), keyToDelete); delErr != nil {
+			if delErr := deleteLogoFunc(context.Background(), s.uploader, keyToDelete); delErr != nil {
 				s.logger.WarnContext(context.Background(), "Failed to delete orphaned logo", slog.String("key", keyToDelete), slog.Any("error", delErr))
 			}
 		}(newKey)
@@ -652,7 +714,7 @@ func (s *tournamentService) UploadTournamentLogo(ctx context.Context, tournament
 		go func(keyToDelete string) { // Asynchronous deletion
 			deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if deleteErr := s.uploader.Delete(deleteCtx, keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(deleteCtx, s.uploader, keyToDelete); deleteErr != nil {
 				s.logger.WarnContext(context.Background(), "Failed to delete old tournament logo from storage",
 					slog.String("key", keyToDelete), slog.Int("tournament_id", tournamentID), slog.Any("error", deleteErr))
 			}
@@ -672,8 +734,10 @@ func (s *tournamentService) FinalizeTournament(ctx context.Context, tournamentID
 		return nil, fmt.Errorf("FinalizeTournament: failed to get tournament %d: %w", tournamentID, err)
 	}
 
-	if currentUserID != 0 && tournament.OrganizerID != currentUserID {
-		return nil, ErrForbiddenOperation
+	if currentUserID != 0 {
+		if err := s.ensureCanManage(ctx, tournament, currentUserID); err != nil {
+			return nil, err
+		}
 	}
 
 	if tournament.Status == models.StatusCompleted {
@@ -705,10 +769,7 @@ func (s *tournamentService) FinalizeTournament(ctx context.Context, tournamentID
 						populateUserDetailsFunc(winnerParticipant.User, s.uploader)
 					}
 					if winnerParticipant.Team != nil && winnerParticipant.Team.LogoKey != nil {
-						url := s.uploader.GetPublicURL(*winnerParticipant.Team.LogoKey)
-						if url != "" {
-							winnerParticipant.Team.LogoURL = &url
-						}
+						winnerParticipant.Team.LogoURL, winnerParticipant.Team.LogoURLs = logoURLsFunc(s.uploader, winnerParticipant.Team.LogoKey)
 					}
 					tmpView := participantToParticipantViewFunc(winnerParticipant, s.uploader)
 					winnerView = &tmpView
@@ -733,10 +794,7 @@ func (s *tournamentService) FinalizeTournament(ctx context.Context, tournamentID
 			populateUserDetailsFunc(winnerParticipant.User, s.uploader)
 		}
 		if winnerParticipant.Team != nil && winnerParticipant.Team.LogoKey != nil {
-			url := s.uploader.GetPublicURL(*winnerParticipant.Team.LogoKey)
-			if url != "" {
-				winnerParticipant.Team.LogoURL = &url
-			}
+			winnerParticipant.Team.LogoURL, winnerParticipant.Team.LogoURLs = logoURLsFunc(s.uploader, winnerParticipant.Team.LogoKey)
 		}
 		tmpView := participantToParticipantViewFunc(winnerParticipant, s.uploader)
 		winnerView = &tmpView
@@ -762,6 +820,20 @@ func (s *tournamentService) FinalizeTournament(ctx context.Context, tournamentID
 				s.logger.InfoContext(ctx, "Tournament overall winner persisted", slog.Int("tournament_id", tournamentID), slog.Any("winner_pid", finalWinnerPID))
 			}
 		}
+
+		placements, errPlacements := buildTournamentPlacementsFunc(ctx, tx, tournament, finalWinnerPID, s.participantRepo, s.soloMatchRepo, s.teamMatchRepo, s.standingRepo)
+		if errPlacements != nil {
+			return fmt.Errorf("FinalizeTournament: failed to compute placements: %w", errPlacements)
+		}
+		if errSave := s.placementRepo.ReplaceForTournament(ctx, tx, tournamentID, placements); errSave != nil {
+			return fmt.Errorf("FinalizeTournament: failed to save placements: %w", errSave)
+		}
+
+		if s.seasonService != nil {
+			if errSeason := s.seasonService.ApplyTournamentResults(ctx, tx, tournament, finalWinnerPID); errSeason != nil {
+				return fmt.Errorf("FinalizeTournament: failed to update season standings: %w", errSeason)
+			}
+		}
 		return nil
 	})
 
@@ -771,16 +843,22 @@ func (s *tournamentService) FinalizeTournament(ctx context.Context, tournamentID
 
 	s.logger.InfoContext(ctx, "Tournament finalized", slog.Int("tournament_id", tournamentID), slog.Any("winner_pid", finalWinnerPID))
 
+	completionPayload := brackets.TournamentCompletedPayload{
+		TournamentID:          tournamentID,
+		WinnerParticipantDBID: finalWinnerPID,
+		WinnerDetails:         winnerView,
+	}
 	if s.hub != nil {
-		roomID := "tournament_" + strconv.Itoa(tournamentID)
-		completionPayload := map[string]interface{}{
-			"tournament_id":            tournamentID,
-			"winner_participant_db_id": finalWinnerPID,
-			"winner_details":           winnerView,
-		}
-		s.hub.BroadcastToRoom(roomID, brackets.WebSocketMessage{Type: "TOURNAMENT_COMPLETED", Payload: completionPayload, RoomID: roomID})
-		s.logger.InfoContext(ctx, "Sent TOURNAMENT_COMPLETED to room", slog.String("room_id", roomID), slog.Any("winner_pid", finalWinnerPID))
+		topic := brackets.TournamentTopic(tournamentID)
+		s.hub.Publish(topic, brackets.EventTournamentCompleted, completionPayload)
+		s.logger.InfoContext(ctx, "Sent TOURNAMENT_COMPLETED", slog.String("topic", topic), slog.Any("winner_pid", finalWinnerPID))
 	}
+	previousStatus := tournament.Status
+	tournament.Status = models.StatusCompleted
+	s.emitWebhook(ctx, models.WebhookTournamentStatusChange, tournament,
+		brackets.TournamentStatusPayload{TournamentID: tournamentID, NewStatus: models.StatusCompleted, OldStatus: previousStatus})
+	s.emitWebhook(ctx, models.WebhookTournamentCompleted, tournament, completionPayload)
+	s.notifyStatusChanged(ctx, tournament)
 
 	// Re-fetch to ensure the returned object has the overall winner ID if it was just set
 	finalUpdatedTournament, fetchErr := s.GetTournamentByID(ctx, tournamentID, 0)
@@ -801,6 +879,8 @@ func (s *tournamentService) AutoUpdateTournamentStatusesByDates(ctx context.Cont
 	}
 
 	var opErr error
+	var changed []*models.Tournament // Уведомления и вебхуки рассылаются только после коммита
+	previousStatus := make(map[int]models.TournamentStatus)
 	defer func() {
 		if p := recover(); p != nil {
 			s.logger.ErrorContext(ctx, "Scheduler: recovered from panic, rolling back transaction", slog.Any("panic_value", p))
@@ -819,6 +899,11 @@ func (s *tournamentService) AutoUpdateTournamentStatusesByDates(ctx context.Cont
 				opErr = fmt.Errorf("scheduler: failed to commit transaction: %w", cErr)
 			} else {
 				s.logger.InfoContext(ctx, "Scheduler: Transaction committed successfully for status updates.")
+				for _, t := range changed {
+					s.emitWebhook(ctx, models.WebhookTournamentStatusChange, t,
+						brackets.TournamentStatusPayload{TournamentID: t.ID, NewStatus: t.Status, OldStatus: previousStatus[t.ID]})
+					s.notifyStatusChanged(ctx, t)
+				}
 			}
 		}
 	}()
@@ -880,6 +965,10 @@ func (s *tournamentService) AutoUpdateTournamentStatusesByDates(ctx context.Cont
 				return opErr
 			}
 
+			previousStatus[t.ID] = originalStatus
+			t.Status = newStatus
+			changed = append(changed, t)
+
 			if updatedTournament.Status == newStatus {
 				s.logger.InfoContext(ctx, "Scheduler: Successfully updated tournament status",
 					slog.Int("tournament_id", t.ID), slog.String("new_status", string(updatedTournament.Status)))
@@ -897,6 +986,74 @@ func (s *tournamentService) AutoUpdateTournamentStatusesByDates(ctx context.Cont
 	return opErr
 }
 
+func (s *tournamentService) emitWebhook(ctx context.Context, event models.WebhookEvent, tournament *models.Tournament, data interface{}) {
+	if s.webhooks == nil {
+		return
+	}
+	s.webhooks.Emit(ctx, event, tournament, data)
+}
+
+// notifyStatusChanged уведомляет участников о новом статусе турнира,
+// а при старте — о матчах первого круга, в которых известны оба соперника.
+func (s *tournamentService) notifyStatusChanged(ctx context.Context, tournament *models.Tournament) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.NotifyTournamentParticipants(ctx, NotificationEvent{Type: models.NotificationTournamentStatus, Tournament: tournament})
+	if tournament.Status != models.StatusActive {
+		return
+	}
+
+	if tournament.Format == nil {
+		format, err := s.formatRepo.GetByID(ctx, tournament.FormatID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "notifyStatusChanged: failed to load format", slog.Int("tournament_id", tournament.ID), slog.Any("error", err))
+			return
+		}
+		tournament.Format = format
+	}
+
+	scheduled := models.StatusScheduled
+	if tournament.Format.ParticipantType == models.FormatParticipantSolo {
+		matches, err := s.soloMatchRepo.ListByTournament(ctx, tournament.ID, nil, &scheduled)
+		if err != nil {
+			s.logger.WarnContext(ctx, "notifyStatusChanged: failed to list solo matches", slog.Int("tournament_id", tournament.ID), slog.Any("error", err))
+			return
+		}
+		for _, m := range matches {
+			if m.P1ParticipantID != nil && m.P2ParticipantID != nil {
+				s.notifier.NotifyParticipants(ctx, NotificationEvent{Type: models.NotificationMatchScheduled, Tournament: tournament, MatchType: models.RatingMatchSolo, MatchID: m.ID, MatchTime: m.MatchTime},
+					*m.P1ParticipantID, *m.P2ParticipantID)
+			}
+		}
+		return
+	}
+
+	matches, err := s.teamMatchRepo.ListByTournament(ctx, tournament.ID, nil, &scheduled)
+	if err != nil {
+		s.logger.WarnContext(ctx, "notifyStatusChanged: failed to list team matches", slog.Int("tournament_id", tournament.ID), slog.Any("error", err))
+		return
+	}
+	for _, m := range matches {
+		if m.T1ParticipantID != nil && m.T2ParticipantID != nil {
+			s.notifier.NotifyParticipants(ctx, NotificationEvent{Type: models.NotificationMatchScheduled, Tournament: tournament, MatchType: models.RatingMatchTeam, MatchID: m.ID, MatchTime: m.MatchTime},
+				*m.T1ParticipantID, *m.T2ParticipantID)
+		}
+	}
+}
+
+// ensureCanManage возвращает ErrForbiddenOperation, если пользователь не может управлять турниром.
+func (s *tournamentService) ensureCanManage(ctx context.Context, tournament *models.Tournament, userID int) error {
+	allowed, err := canManageTournamentFunc(ctx, s.orgRepo, tournament, userID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrForbiddenOperation
+	}
+	return nil
+}
+
 func (s *tournamentService) populateTournamentDetails(ctx context.Context, tournament *models.Tournament) {
 	if tournament == nil {
 		return
@@ -992,11 +1149,13 @@ func (s *tournamentService) GetTournamentBracketData(ctx context.Context, tourna
 		if listErr != nil {
 			return nil, fmt.Errorf("GetTournamentBracketData: failed to list solo matches: %w", listErr)
 		}
+		matchEvents := s.loadBracketMatchEvents(ctx, tournamentID, models.RatingMatchSolo)
 		for _, sm := range soloMatches {
 			if sm == nil {
 				continue
 			}
 			mv := s.toMatchView(sm, nil, participantsMap)
+			mv.Events = matchEvents[sm.ID]
 			allMatchesView = append(allMatchesView, mv)
 			if tournament.Format.BracketType == "SingleElimination" && sm.Round != nil {
 				roundsMap[*sm.Round] = append(roundsMap[*sm.Round], &mv)
@@ -1007,11 +1166,13 @@ func (s *tournamentService) GetTournamentBracketData(ctx context.Context, tourna
 		if listErr != nil {
 			return nil, fmt.Errorf("GetTournamentBracketData: failed to list team matches: %w", listErr)
 		}
+		matchEvents := s.loadBracketMatchEvents(ctx, tournamentID, models.RatingMatchTeam)
 		for _, tm := range teamMatches {
 			if tm == nil {
 				continue
 			}
 			mv := s.toMatchView(nil, tm, participantsMap)
+			mv.Events = matchEvents[tm.ID]
 			allMatchesView = append(allMatchesView, mv)
 			if tournament.Format.BracketType == "SingleElimination" && tm.Round != nil {
 				roundsMap[*tm.Round] = append(roundsMap[*tm.Round], &mv)
@@ -1080,6 +1241,25 @@ func (s *tournamentService) GetTournamentBracketData(ctx context.Context, tourna
 
 	overallWinnerPID := tournament.OverallWinnerParticipantID
 
+	var placementsViewList []PlacementView
+	if tournament.Status == models.StatusCompleted {
+		placements, placementsErr := s.placementRepo.ListByTournament(ctx, tournamentID)
+		if placementsErr == nil && len(placements) == 0 {
+			// Турниры, завершённые до появления таблицы мест, считаем на лету
+			placements, placementsErr = buildTournamentPlacementsFunc(ctx, nil, tournament, overallWinnerPID, s.participantRepo, s.soloMatchRepo, s.teamMatchRepo, s.standingRepo)
+		}
+		if placementsErr != nil {
+			s.logger.ErrorContext(ctx, "GetTournamentBracketData: failed to load placements", slog.Int("tournament_id", tournamentID), slog.Any("error", placementsErr))
+		}
+		for _, p := range placements {
+			pView, ok := participantsMap[p.ParticipantID]
+			if !ok {
+				continue
+			}
+			placementsViewList = append(placementsViewList, PlacementView{Placement: p.Placement, Participant: pView})
+		}
+	}
+
 	return &FullTournamentBracketView{
 		TournamentID:               tournament.ID,
 		Name:                       tournament.Name,
@@ -1092,9 +1272,20 @@ func (s *tournamentService) GetTournamentBracketData(ctx context.Context, tourna
 		Standings:                  standingsViewList,
 		ParticipantsMap:            participantsMap,
 		OverallWinnerParticipantID: overallWinnerPID,
+		Placements:                 placementsViewList,
 	}, nil
 }
 
+// loadBracketMatchEvents — хронология матчей для сетки; при ошибке сетка отдаётся без неё.
+func (s *tournamentService) loadBracketMatchEvents(ctx context.Context, tournamentID int, matchType models.RatingMatchType) map[int][]models.MatchEvent {
+	events, err := s.matchService.ListTournamentMatchEvents(ctx, tournamentID, matchType)
+	if err != nil {
+		s.logger.WarnContext(ctx, "GetTournamentBracketData: failed to load match events", slog.Int("tournament_id", tournamentID), slog.Any("error", err))
+		return nil
+	}
+	return events
+}
+
 func (s *tournamentService) toMatchView(sm *models.SoloMatch, tm *models.TeamMatch, participantsMap map[int]ParticipantView) MatchView {
 	mv := MatchView{}
 	var p1ID, p2ID, winnerID, nextMatchID, winnerSlot *int
@@ -1147,14 +1338,15 @@ func (s *tournamentService) toMatchView(sm *models.SoloMatch, tm *models.TeamMat
 	mv.MatchTime = matchTimeVal
 
 	if scoreStr != nil && *scoreStr != "" {
-		s1, s2, draw, err := parseScore(*scoreStr) // Assuming parseScore is accessible
-		if err == nil {
+		// Для сетов — число выигранных сетов, для времени — миллисекунды
+		if s1, s2, ok := scoring.Summarize(*scoreStr); ok {
 			p1Score = &s1
 			p2Score = &s2
-			isDraw = draw
 		} else {
-			s.logger.WarnContext(context.Background(), "Failed to parse score string for MatchView", slog.Int("match_id", mv.MatchID), slog.String("score", *scoreStr), slog.Any("error", err))
+			s.logger.WarnContext(context.Background(), "Failed to parse score string for MatchView", slog.Int("match_id", mv.MatchID), slog.String("score", *scoreStr))
 		}
+		// Победитель определяется по правилам спорта при фиксации результата
+		isDraw = statusVal == models.MatchStatusCompleted && winnerID == nil
 	}
 	mv.ScoreP1 = p1Score
 	mv.ScoreP2 = p2Score
@@ -1197,4 +1389,3 @@ func (s *tournamentService) toMatchView(sm *models.SoloMatch, tm *models.TeamMat
 	}
 	return mv
 }
-
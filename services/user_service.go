@@ -10,6 +10,7 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/Dosada05/tournament-system/imaging"
 	"github.com/Dosada05/tournament-system/models"
 	"github.com/Dosada05/tournament-system/repositories"
 	"github.com/Dosada05/tournament-system/storage"
@@ -22,16 +23,17 @@ const (
 )
 
 var (
-	ErrUserUpdateFailed               = errors.New("failed to update user profile")
-	ErrNicknameTaken                  = errors.New("nickname is already taken")
-	ErrEmailTaken                     = errors.New("email is already taken")
-	ErrInvalidEmailFormat             = errors.New("invalid email format")
-	ErrPasswordHashingFailed          = errors.New("failed to hash password")
-	ErrLogoUploadFailed               = errors.New("failed to upload logo")
-	ErrInvalidLogoFormat              = errors.New("invalid logo file format or content type")
-	ErrLogoUpdateDatabaseFailed       = errors.New("failed to update logo information in database")
-	ErrLogoDeleteFailed               = errors.New("failed to delete previous logo")
-	ErrCouldNotDetermineFileExtension = errors.New("could not determine file extension from content type")
+	ErrUserUpdateFailed         = errors.New("failed to update user profile")
+	ErrNicknameTaken            = errors.New("nickname is already taken")
+	ErrEmailTaken               = errors.New("email is already taken")
+	ErrInvalidEmailFormat       = errors.New("invalid email format")
+	ErrPasswordHashingFailed    = errors.New("failed to hash password")
+	ErrLogoUploadFailed         = errors.New("failed to upload logo")
+	ErrInvalidLogoFormat        = errors.New("invalid logo file format or content type")
+	ErrLogoUpdateDatabaseFailed = errors.New("failed to update logo information in database")
+	ErrLogoDeleteFailed         = errors.New("failed to delete previous logo")
+	ErrInvalidLocale            = errors.New("unsupported locale")
+	ErrInvalidTimezone          = errors.New("invalid timezone")
 )
 
 type UserService interface {
@@ -47,17 +49,23 @@ type UpdateProfileInput struct {
 	Nickname  *string `json:"nickname"`
 	Email     *string `json:"email"`
 	Password  *string `json:"password"`
+	Locale    *string `json:"locale"`
+	Timezone  *string `json:"timezone"`
 }
 
 type userService struct {
-	userRepo repositories.UserRepository
-	uploader storage.FileUploader
+	userRepo      repositories.UserRepository
+	placementRepo repositories.TournamentPlacementRepository
+	uploader      storage.FileUploader
+	images        *imaging.Processor
 }
 
-func NewUserService(userRepo repositories.UserRepository, uploader storage.FileUploader) UserService {
+func NewUserService(userRepo repositories.UserRepository, placementRepo repositories.TournamentPlacementRepository, uploader storage.FileUploader, images *imaging.Processor) UserService {
 	return &userService{
-		userRepo: userRepo,
-		uploader: uploader,
+		userRepo:      userRepo,
+		placementRepo: placementRepo,
+		uploader:      uploader,
+		images:        images,
 	}
 }
 
@@ -71,6 +79,12 @@ func (s *userService) GetProfileByID(ctx context.Context, userID int) (*models.U
 	}
 	user.PasswordHash = ""
 	s.populateLogoURL(user)
+
+	placements, err := s.placementRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements for user %d: %w", userID, err)
+	}
+	user.Placements = placements
 	return user, nil
 }
 
@@ -137,6 +151,20 @@ func (s *userService) UpdateProfile(ctx context.Context, userID int, input Updat
 		user.PasswordHash = newPasswordHash
 		updated = true
 	}
+	if input.Locale != nil || input.Timezone != nil {
+		locale, timezone := user.Locale, user.Timezone
+		if input.Locale != nil {
+			locale = *input.Locale
+		}
+		if input.Timezone != nil {
+			timezone = *input.Timezone
+		}
+		changed, err := applyLocaleSettings(user, locale, timezone)
+		if err != nil {
+			return nil, err
+		}
+		updated = updated || changed
+	}
 	if !updated {
 		user.PasswordHash = ""
 		s.populateLogoURL(user)
@@ -190,18 +218,13 @@ func (s *userService) UpdateUserLogo(ctx context.Context, targetUserID int, curr
 		return nil, fmt.Errorf("failed to get user %d for logo update: %w", targetUserID, err)
 	}
 	oldLogoKey := user.LogoKey
-	ext, err := getExtensionFromContentType(contentType)
+	newKey, err := uploadLogoFunc(ctx, s.uploader, s.images, fmt.Sprintf("%s/%d/avatar_%d", userLogoPrefix, targetUserID, time.Now().UnixNano()), file)
 	if err != nil {
 		return nil, err
 	}
-	newKey := fmt.Sprintf("%s/%d/avatar_%d%s", userLogoPrefix, targetUserID, time.Now().UnixNano(), ext)
-	_, err = s.uploader.Upload(ctx, newKey, contentType, file)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrLogoUploadFailed, err)
-	}
 	err = s.userRepo.UpdateLogoKey(ctx, targetUserID, newKey)
 	if err != nil {
-		if deleteErr := s.uploader.Delete(context.Background(), newKey); deleteErr != nil {
+		if deleteErr := deleteLogoFunc(context.Background(), s.uploader, newKey); deleteErr != nil {
 			fmt.Printf("CRITICAL: Failed to delete uploaded file %s after DB error: %v\n", newKey, deleteErr)
 		}
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -211,7 +234,7 @@ func (s *userService) UpdateUserLogo(ctx context.Context, targetUserID int, curr
 	}
 	if oldLogoKey != nil && *oldLogoKey != "" && *oldLogoKey != newKey {
 		go func(keyToDelete string) {
-			if deleteErr := s.uploader.Delete(context.Background(), keyToDelete); deleteErr != nil {
+			if deleteErr := deleteLogoFunc(context.Background(), s.uploader, keyToDelete); deleteErr != nil {
 				fmt.Printf("Failed to delete old user logo %s: %v\n", keyToDelete, deleteErr)
 			}
 		}(*oldLogoKey)
@@ -224,10 +247,7 @@ func (s *userService) UpdateUserLogo(ctx context.Context, targetUserID int, curr
 
 func (s *userService) populateLogoURL(user *models.User) {
 	if user != nil && user.LogoKey != nil && *user.LogoKey != "" {
-		url := s.uploader.GetPublicURL(*user.LogoKey)
-		if url != "" {
-			user.LogoURL = &url
-		}
+		user.LogoURL, user.LogoURLs = logoURLsFunc(s.uploader, user.LogoKey)
 	}
 }
 
@@ -239,22 +259,24 @@ func hashPassword(password string) (string, error) {
 	return string(hashedBytes), nil
 }
 
-func getExtensionFromContentType(contentType string) (string, error) {
-	switch contentType {
-	case "image/jpeg":
-		return ".jpg", nil
-	case "image/png":
-		return ".png", nil
-	case "image/gif":
-		return ".gif", nil
-	case "image/webp":
-		return ".webp", nil
-	default:
-		parts := strings.Split(contentType, "/")
-		if len(parts) == 2 && strings.HasPrefix(parts[0], "image") && parts[1] != "" {
-			ext := "." + strings.Split(parts[1], "+")[0]
-			return ext, nil
+// applyLocaleSettings проверяет язык и часовой пояс и записывает их пользователю.
+// Пустые значения оставляют текущие настройки.
+func applyLocaleSettings(user *models.User, locale, timezone string) (bool, error) {
+	changed := false
+	if locale = strings.TrimSpace(locale); locale != "" {
+		normalized, ok := models.NormalizeLocale(locale)
+		if !ok {
+			return false, fmt.Errorf("%w: %q, supported: %s", ErrInvalidLocale, locale, strings.Join(models.SupportedLocales(), ", "))
+		}
+		changed = changed || normalized != user.Locale
+		user.Locale = normalized
+	}
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		if !models.IsValidTimezone(timezone) {
+			return false, fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
 		}
-		return "", fmt.Errorf("%w: unsupported content type '%s'", ErrCouldNotDetermineFileExtension, contentType)
+		changed = changed || timezone != user.Timezone
+		user.Timezone = timezone
 	}
+	return changed, nil
 }
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Dosada05/tournament-system/models"
+	"github.com/Dosada05/tournament-system/repositories"
+)
+
+const (
+	webhookBatchSize      = 20
+	webhookLease          = 2 * time.Minute
+	webhookRequestTimeout = 10 * time.Second
+	webhookSecretBytes    = 32
+	webhookMaxURLLength   = 2048
+)
+
+// Заголовки запроса доставки. Подпись: "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	ErrWebhookNotFound         = repositories.ErrWebhookNotFound
+	ErrWebhookDeliveryNotFound = repositories.ErrWebhookDeliveryNotFound
+	ErrInvalidWebhookURL       = errors.New("webhook url must be a public http(s) address")
+	ErrInvalidWebhookEvent     = errors.New("unknown webhook event")
+)
+
+type CreateWebhookInput struct {
+	URL            string                `json:"url"`
+	Events         []models.WebhookEvent `json:"events"`
+	OrganizationID *int                  `json:"organization_id,omitempty"`
+}
+
+type UpdateWebhookInput struct {
+	URL          *string                `json:"url,omitempty"`
+	Events       *[]models.WebhookEvent `json:"events,omitempty"`
+	IsActive     *bool                  `json:"is_active,omitempty"`
+	RotateSecret bool                   `json:"rotate_secret,omitempty"`
+}
+
+// webhookEnvelope — тело запроса доставки.
+type webhookEnvelope struct {
+	Event        models.WebhookEvent `json:"event"`
+	TournamentID int                 `json:"tournament_id"`
+	OccurredAt   time.Time           `json:"occurred_at"`
+	Data         interface{}         `json:"data"`
+}
+
+type WebhookService interface {
+	// Emit ставит событие в очередь доставки всем активным подпискам организатора турнира и его организации.
+	// Ошибки только логируются: вебхуки не должны ломать основную операцию.
+	Emit(ctx context.Context, event models.WebhookEvent, tournament *models.Tournament, data interface{})
+	// ProcessDue отправляет доставки, у которых подошло время попытки. Возвращает число успешных.
+	ProcessDue(ctx context.Context) (int, error)
+
+	Create(ctx context.Context, input CreateWebhookInput, currentUserID int) (*models.Webhook, error)
+	// List — подписки пользователя или, если указан organizationID, организации.
+	List(ctx context.Context, organizationID *int, currentUserID int) ([]*models.Webhook, error)
+	GetByID(ctx context.Context, id int, currentUserID int) (*models.Webhook, error)
+	Update(ctx context.Context, id int, input UpdateWebhookInput, currentUserID int) (*models.Webhook, error)
+	Delete(ctx context.Context, id int, currentUserID int) error
+
+	ListDeliveries(ctx context.Context, webhookID int, filter models.WebhookDeliveryFilter, currentUserID int) ([]*models.WebhookDelivery, int, error)
+	// Redeliver ставит в очередь новую доставку с тем же телом; исходная запись не меняется.
+	Redeliver(ctx context.Context, webhookID int, deliveryID int64, currentUserID int) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+	orgRepo     repositories.OrganizationRepository
+	client      *http.Client
+	logger      *slog.Logger
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepository, orgRepo repositories.OrganizationRepository, logger *slog.Logger) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		orgRepo:     orgRepo,
+		client: &http.Client{
+			Timeout:   webhookRequestTimeout,
+			Transport: newWebhookTransport(),
+			// Редиректы не выполняем: иначе проверку адреса можно обойти через 3xx
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		logger: logger,
+	}
+}
+
+func (s *webhookService) Emit(ctx context.Context, event models.WebhookEvent, tournament *models.Tournament, data interface{}) {
+	if tournament == nil {
+		return
+	}
+	logger := s.logger.With(slog.String("event", string(event)), slog.Int("tournament_id", tournament.ID))
+
+	webhooks, err := s.webhookRepo.ListActiveForTournament(ctx, tournament.OrganizerID, tournament.OrganizationID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to list webhooks for event", slog.Any("error", err))
+		return
+	}
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(webhookEnvelope{Event: event, TournamentID: tournament.ID, OccurredAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				logger.ErrorContext(ctx, "Failed to encode webhook payload", slog.Any("error", err))
+				return
+			}
+		}
+		delivery := &models.WebhookDelivery{WebhookID: webhook.ID, Event: event, Payload: payload}
+		if err := s.webhookRepo.EnqueueDelivery(ctx, nil, delivery); err != nil {
+			logger.ErrorContext(ctx, "Failed to enqueue webhook delivery", slog.Int("webhook_id", webhook.ID), slog.Any("error", err))
+		}
+	}
+}
+
+func (s *webhookService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := make(map[int]*models.Webhook)
+	delivered := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+			if err != nil && !errors.Is(err, repositories.ErrWebhookNotFound) {
+				s.logger.ErrorContext(ctx, "Failed to load webhook for delivery", slog.Int64("delivery_id", delivery.ID), slog.Any("error", err))
+				continue
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+		if s.deliver(ctx, webhook, delivery) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+func (s *webhookService) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) bool {
+	logger := s.logger.With(slog.Int64("delivery_id", delivery.ID), slog.Int("webhook_id", delivery.WebhookID), slog.String("event", string(delivery.Event)))
+
+	// Отключённая подписка не получает новых попыток; доставка остаётся в журнале как dead
+	if webhook == nil || !webhook.IsActive {
+		if err := s.webhookRepo.MarkFailed(ctx, delivery.ID, nil, "webhook is disabled", time.Now(), true); err != nil {
+			logger.ErrorContext(ctx, "Failed to record skipped webhook delivery", slog.Any("error", err))
+		}
+		return false
+	}
+
+	responseStatus, sendErr := s.send(ctx, webhook, delivery)
+	if sendErr == nil {
+		if err := s.webhookRepo.MarkDelivered(ctx, delivery.ID, *responseStatus); err != nil {
+			logger.ErrorContext(ctx, "Webhook delivered but failed to mark as delivered", slog.Any("error", err))
+		}
+		return true
+	}
+
+	attempt := delivery.Attempts + 1
+	dead := attempt >= delivery.MaxAttempts
+	nextAttemptAt := time.Now().Add(outboxBackoff(attempt))
+	if err := s.webhookRepo.MarkFailed(ctx, delivery.ID, responseStatus, truncateError(sendErr.Error()), nextAttemptAt, dead); err != nil {
+		logger.ErrorContext(ctx, "Failed to record webhook delivery failure", slog.Any("error", err))
+	}
+	if dead {
+		logger.ErrorContext(ctx, "Webhook delivery moved to dead letters", slog.Int("attempts", attempt), slog.Any("error", sendErr))
+	} else {
+		logger.WarnContext(ctx, "Webhook delivery failed, will retry",
+			slog.Int("attempts", attempt), slog.Time("next_attempt_at", nextAttemptAt), slog.Any("error", sendErr))
+	}
+	return false
+}
+
+// send выполняет POST на адрес подписки. Успехом считается любой ответ 2xx.
+func (s *webhookService) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (*int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tournament-system-webhooks/1.0")
+	req.Header.Set(WebhookHeaderEvent, string(delivery.Event))
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	req.Header.Set(WebhookHeaderSignature, signWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		return &status, fmt.Errorf("unexpected response status %d", status)
+	}
+	return &status, nil
+}
+
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookService) Create(ctx context.Context, input CreateWebhookInput, currentUserID int) (*models.Webhook, error) {
+	webhookURL, err := validateWebhookURL(input.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := validateWebhookEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		URL:       webhookURL,
+		Events:    events,
+		IsActive:  true,
+		CreatedBy: &currentUserID,
+	}
+	if input.OrganizationID != nil {
+		if err := s.checkOrganizationAccess(ctx, *input.OrganizationID, currentUserID); err != nil {
+			return nil, err
+		}
+		webhook.OrganizationID = input.OrganizationID
+	} else {
+		webhook.OwnerUserID = &currentUserID
+	}
+
+	webhook.Secret, err = generateSecureToken(webhookSecretBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Webhook created", slog.Int("webhook_id", webhook.ID), slog.Int("user_id", currentUserID))
+	return webhook, nil
+}
+
+func (s *webhookService) List(ctx context.Context, organizationID *int, currentUserID int) ([]*models.Webhook, error) {
+	var (
+		webhooks []*models.Webhook
+		err      error
+	)
+	if organizationID != nil {
+		if err := s.checkOrganizationAccess(ctx, *organizationID, currentUserID); err != nil {
+			return nil, err
+		}
+		webhooks, err = s.webhookRepo.ListByOrganization(ctx, *organizationID)
+	} else {
+		webhooks, err = s.webhookRepo.ListByOwner(ctx, currentUserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) GetByID(ctx context.Context, id int, currentUserID int) (*models.Webhook, error) {
+	webhook, err := s.getManageable(ctx, id, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// Update меняет адрес, фильтр событий или активность; секрет возвращается только при RotateSecret.
+func (s *webhookService) Update(ctx context.Context, id int, input UpdateWebhookInput, currentUserID int) (*models.Webhook, error) {
+	webhook, err := s.getManageable(ctx, id, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if input.URL != nil {
+		if webhook.URL, err = validateWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+	}
+	if input.Events != nil {
+		if webhook.Events, err = validateWebhookEvents(*input.Events); err != nil {
+			return nil, err
+		}
+	}
+	if input.IsActive != nil {
+		webhook.IsActive = *input.IsActive
+	}
+	if input.RotateSecret {
+		if webhook.Secret, err = generateSecureToken(webhookSecretBytes); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	if !input.RotateSecret {
+		webhook.Secret = ""
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id int, currentUserID int) error {
+	if _, err := s.getManageable(ctx, id, currentUserID); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID int, filter models.WebhookDeliveryFilter, currentUserID int) ([]*models.WebhookDelivery, int, error) {
+	if _, err := s.getManageable(ctx, webhookID, currentUserID); err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.webhookRepo.ListDeliveries(ctx, webhookID, filter)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, webhookID int, deliveryID int64, currentUserID int) (*models.WebhookDelivery, error) {
+	if _, err := s.getManageable(ctx, webhookID, currentUserID); err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	delivery := &models.WebhookDelivery{
+		WebhookID:    webhookID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := s.webhookRepo.EnqueueDelivery(ctx, nil, delivery); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Webhook delivery requeued", slog.Int64("delivery_id", delivery.ID), slog.Int64("redelivery_of", original.ID))
+	return delivery, nil
+}
+
+// getManageable загружает подписку, если пользователь её владелец или управляет её организацией.
+func (s *webhookService) getManageable(ctx context.Context, id int, currentUserID int) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.OwnerUserID != nil && *webhook.OwnerUserID == currentUserID {
+		return webhook, nil
+	}
+	if webhook.OrganizationID != nil {
+		if err := s.checkOrganizationAccess(ctx, *webhook.OrganizationID, currentUserID); err != nil {
+			return nil, err
+		}
+		return webhook, nil
+	}
+	return nil, fmt.Errorf("%w: webhook belongs to another user", ErrForbiddenOperation)
+}
+
+func (s *webhookService) checkOrganizationAccess(ctx context.Context, organizationID, currentUserID int) error {
+	member, err := s.orgRepo.GetMember(ctx, organizationID, currentUserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+			return fmt.Errorf("%w: not a member of the organization", ErrForbiddenOperation)
+		}
+		return fmt.Errorf("failed to check organization membership for user %d: %w", currentUserID, err)
+	}
+	if !member.Role.CanManage() {
+		return fmt.Errorf("%w: only organization owners and admins can manage webhooks", ErrForbiddenOperation)
+	}
+	return nil
+}
+
+var errWebhookAddressForbidden = errors.New("webhook target resolves to a forbidden address")
+
+// newWebhookTransport проверяет адрес уже после DNS-резолва, непосредственно перед соединением:
+// имя хоста, указывающее на 127.0.0.1 или 10.0.0.0/8, проходит проверку URL при создании подписки,
+// но не будет подключено. Прокси из окружения не используется — иначе проверялся бы адрес прокси.
+func newWebhookTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isForbiddenWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", errWebhookAddressForbidden, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func isForbiddenWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// validateWebhookURL пропускает только http(s)-адреса с публичным хостом. Это быстрая проверка
+// при сохранении подписки; окончательно адрес проверяется при соединении (newWebhookTransport).
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > webhookMaxURLLength {
+		return "", ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return "", ErrInvalidWebhookURL
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", ErrInvalidWebhookURL
+	}
+	if ip := net.ParseIP(host); ip != nil && isForbiddenWebhookIP(ip) {
+		return "", ErrInvalidWebhookURL
+	}
+	return u.String(), nil
+}
+
+func validateWebhookEvents(events []models.WebhookEvent) ([]models.WebhookEvent, error) {
+	result := make([]models.WebhookEvent, 0, len(events))
+	seen := make(map[models.WebhookEvent]bool, len(events))
+	for _, event := range events {
+		if !event.IsValid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidWebhookEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
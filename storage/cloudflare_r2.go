@@ -99,6 +99,29 @@ func (u *cloudflareR2Uploader) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (u *cloudflareR2Uploader) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	paginator := s3.NewListObjectsV2Paginator(u.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(u.bucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []ObjectInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects in R2 (prefix: %s): %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			info := ObjectInfo{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+			if obj.LastModified != nil {
+				info.LastModified = *obj.LastModified
+			}
+			objects = append(objects, info)
+		}
+	}
+	return objects, nil
+}
+
 func (u *cloudflareR2Uploader) GetPublicURL(key string) string {
 	if u.publicBaseURL == "" || key == "" {
 		return ""
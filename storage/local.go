@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LocalUploaderConfig struct {
+	Dir           string
+	PublicBaseURL string
+}
+
+// LocalUploader хранит файлы в директории на диске — для локальной разработки
+// и on-prem установок без объектного хранилища. Файлы раздаются через Handler.
+type LocalUploader struct {
+	dir           string
+	publicBaseURL string
+}
+
+func NewLocalUploader(cfg LocalUploaderConfig) (*LocalUploader, error) {
+	if cfg.Dir == "" || cfg.PublicBaseURL == "" {
+		return nil, errors.New("invalid local storage configuration: directory and public base URL are required")
+	}
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory %s: %w", cfg.Dir, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory %s: %w", dir, err)
+	}
+	return &LocalUploader{dir: dir, publicBaseURL: cfg.PublicBaseURL}, nil
+}
+
+func (u *LocalUploader) Upload(ctx context.Context, key string, contentType string, reader io.Reader) (*UploadResult, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	target := u.pathFor(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы читатели не видели недописанный объект.
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file for %s: %w", key, err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	hash := md5.New()
+	_, copyErr := io.Copy(io.MultiWriter(tmp, hash), &contextReader{ctx: ctx, r: reader})
+	closeErr := tmp.Close()
+	if copyErr != nil {
+		return nil, fmt.Errorf("failed to write object (key: %s): %w", key, copyErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to write object (key: %s): %w", key, closeErr)
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to set permissions on object (key: %s): %w", key, err)
+	}
+	if err := os.Rename(tmpName, target); err != nil {
+		return nil, fmt.Errorf("failed to store object (key: %s): %w", key, err)
+	}
+
+	return &UploadResult{
+		Key:      key,
+		Location: u.GetPublicURL(key),
+		ETag:     hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func (u *LocalUploader) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	target := u.pathFor(key)
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete object (key: %s): %w", key, err)
+	}
+	// Убираем опустевшие директории, как если бы это был бакет без каталогов.
+	for dir := filepath.Dir(target); dir != u.dir && strings.HasPrefix(dir, u.dir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (u *LocalUploader) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(u.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(u.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		// Временные файлы незавершённых загрузок не считаются объектами.
+		if strings.HasPrefix(d.Name(), ".upload-") || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects (prefix: %s): %w", prefix, err)
+	}
+	return objects, nil
+}
+
+func (u *LocalUploader) GetPublicURL(key string) string {
+	return joinPublicURL(u.publicBaseURL, key)
+}
+
+// Handler раздаёт сохранённые файлы. Монтируется с http.StripPrefix, так что
+// путь запроса совпадает с ключом. Листинг директорий и скрытые файлы не отдаются.
+func (u *LocalUploader) Handler() http.Handler {
+	fileServer := http.FileServer(http.Dir(u.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if validateKey(key) != nil || strings.Contains("/"+key, "/.") {
+			http.NotFound(w, r)
+			return
+		}
+		info, err := os.Stat(u.pathFor(key))
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+func (u *LocalUploader) pathFor(key string) string {
+	return filepath.Join(u.dir, filepath.FromSlash(key))
+}
+
+// contextReader прерывает копирование, если контекст запроса отменён.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
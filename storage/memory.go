@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryObject — объект, сохранённый MemoryUploader.
+type MemoryObject struct {
+	Key         string
+	ContentType string
+	Data        []byte
+	ETag        string
+	UploadedAt  time.Time
+}
+
+// MemoryUploader хранит файлы в памяти — для тестов.
+type MemoryUploader struct {
+	mu            sync.Mutex
+	publicBaseURL string
+	objects       map[string]MemoryObject
+	err           error
+}
+
+func NewMemoryUploader(publicBaseURL string) *MemoryUploader {
+	if publicBaseURL == "" {
+		publicBaseURL = "http://storage.local/"
+	}
+	return &MemoryUploader{
+		publicBaseURL: publicBaseURL,
+		objects:       make(map[string]MemoryObject),
+	}
+}
+
+func (u *MemoryUploader) Upload(ctx context.Context, key string, contentType string, reader io.Reader) (*UploadResult, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	u.mu.Lock()
+	failErr := u.err
+	u.mu.Unlock()
+	if failErr != nil {
+		return nil, failErr
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object (key: %s): %w", key, err)
+	}
+	sum := md5.Sum(data)
+	obj := MemoryObject{
+		Key:         key,
+		ContentType: contentType,
+		Data:        data,
+		ETag:        hex.EncodeToString(sum[:]),
+		UploadedAt:  time.Now(),
+	}
+
+	u.mu.Lock()
+	u.objects[key] = obj
+	u.mu.Unlock()
+
+	return &UploadResult{
+		Key:      key,
+		Location: u.GetPublicURL(key),
+		ETag:     obj.ETag,
+	}, nil
+}
+
+func (u *MemoryUploader) Delete(ctx context.Context, key string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.err != nil {
+		return u.err
+	}
+	delete(u.objects, key)
+	return nil
+}
+
+func (u *MemoryUploader) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.err != nil {
+		return nil, u.err
+	}
+	var objects []ObjectInfo
+	for key, obj := range u.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, ObjectInfo{Key: key, Size: int64(len(obj.Data)), LastModified: obj.UploadedAt})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// SetUploadedAt меняет время загрузки объекта — для проверки сборки мусора с периодом ожидания.
+func (u *MemoryUploader) SetUploadedAt(key string, t time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if obj, ok := u.objects[key]; ok {
+		obj.UploadedAt = t
+		u.objects[key] = obj
+	}
+}
+
+func (u *MemoryUploader) GetPublicURL(key string) string {
+	return joinPublicURL(u.publicBaseURL, key)
+}
+
+// Handler раздаёт сохранённые объекты так же, как LocalUploader.Handler,
+// чтобы URL из GetPublicURL открывались и с этим бэкендом.
+func (u *MemoryUploader) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if validateKey(key) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		obj, ok := u.Object(key)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if obj.ContentType != "" {
+			w.Header().Set("Content-Type", obj.ContentType)
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		http.ServeContent(w, r, path.Base(key), obj.UploadedAt, bytes.NewReader(obj.Data))
+	})
+}
+
+// Object возвращает сохранённый объект по ключу.
+func (u *MemoryUploader) Object(key string) (MemoryObject, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	obj, ok := u.objects[key]
+	return obj, ok
+}
+
+// Keys возвращает отсортированный список сохранённых ключей.
+func (u *MemoryUploader) Keys() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	keys := make([]string, 0, len(u.objects))
+	for key := range u.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FailWith заставляет Upload, Delete и List возвращать err; nil отключает сбои.
+func (u *MemoryUploader) FailWith(err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.err = err
+}
+
+func (u *MemoryUploader) Reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.objects = make(map[string]MemoryObject)
+	u.err = nil
+}
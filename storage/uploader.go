@@ -2,19 +2,71 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 )
 
+var ErrInvalidKey = errors.New("invalid storage key")
+
 type UploadResult struct {
 	Key      string
 	Location string
 	ETag     string
 }
 
+// ObjectInfo — объект хранилища, возвращаемый List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// FileUploader хранит загруженные файлы: в Cloudflare R2, в локальной директории или в памяти.
 type FileUploader interface {
 	Upload(ctx context.Context, key string, contentType string, reader io.Reader) (*UploadResult, error)
 
 	Delete(ctx context.Context, key string) error
 
 	GetPublicURL(key string) string
+
+	// List возвращает все объекты, ключи которых начинаются с prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// validateKey отклоняет пустые, абсолютные ключи и ключи с "..", чтобы
+// локальные бэкенды не могли выйти за пределы своей директории.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	if path.Clean(key) != key {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
+
+// joinPublicURL строит публичный URL ключа относительно baseURL.
+func joinPublicURL(baseURL, key string) string {
+	if baseURL == "" || key == "" {
+		return ""
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	ref := &url.URL{Path: key}
+	return base.ResolveReference(ref).String()
 }